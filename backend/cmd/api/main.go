@@ -5,18 +5,16 @@ import (
 	"log"
 	"os"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/jpcode092/crm-freela/configs"
 	"github.com/jpcode092/crm-freela/docs"
 	"github.com/jpcode092/crm-freela/internal/api"
-	"github.com/jpcode092/crm-freela/internal/middleware"
 	"github.com/jpcode092/crm-freela/internal/repository"
 	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/email"
 	"github.com/jpcode092/crm-freela/pkg/logger"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/jpcode092/crm-freela/pkg/oidc"
 )
 
 // @title           CRM Freela API
@@ -61,72 +59,122 @@ func main() {
 	docs.SwaggerInfo.BasePath = "/api"
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 
-	// Initialize database
-	dbConfig := &configs.Config{
-		DB: configs.DBConfig{
-			Host:     os.Getenv("DB_HOST"),
-			Port:     os.Getenv("DB_PORT"),
-			User:     os.Getenv("DB_USER"),
-			Password: os.Getenv("DB_PASSWORD"),
-			Name:     os.Getenv("DB_NAME"),
-			SSLMode:  os.Getenv("DB_SSLMODE"),
-		},
+	// Load configuration and refuse to start in release mode with insecure defaults
+	appConfig, err := configs.LoadConfig()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to load configuration: %v", err))
+	}
+	if err := appConfig.Validate(); err != nil {
+		logger.Fatal(fmt.Sprintf("Invalid configuration: %v", err))
 	}
 
-	db, err := configs.NewDatabase(dbConfig, logger)
+	// Initialize database (the schema is created by cmd/migrate)
+	db, err := configs.NewDatabase(appConfig, logger)
 	if err != nil {
 		logger.Fatal(fmt.Sprintf("Failed to initialize database: %v", err))
 	}
 	defer db.Close()
 
-	// Initialize router
-	router := gin.Default()
-
-	// Add custom logger middleware
-	router.Use(middleware.LoggerMiddleware(logger))
-
-	// Configure CORS
-	config := cors.DefaultConfig()
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://localhost:3000" // Default frontend URL if not set
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(db.DB)
+	clientRepo := repository.NewClientRepository(db.DB)
+	clientContactRepo := repository.NewClientContactRepository(db.DB)
+	clientActivityRepo := repository.NewClientActivityRepository(db.DB)
+	tagRepo := repository.NewTagRepository(db.DB)
+	customFieldRepo := repository.NewCustomFieldRepository(db.DB)
+	taskRepo := repository.NewTaskRepository(db.DB)
+	paymentRepo := repository.NewPaymentRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(db.DB)
+	revokedTokenRepo := repository.NewRevokedTokenRepository(db.DB)
+	mfaRecoveryCodeRepo := repository.NewMFARecoveryCodeRepository(db.DB)
+	auditRepo := repository.NewAuditEventRepository(db.DB)
+	apiTokenRepo := repository.NewAPITokenRepository(db.DB)
+	userIdentityRepo := repository.NewUserIdentityRepository(db.DB)
+	magicLinkRepo := repository.NewMagicLinkRepository(db.DB)
+	webAuthnCredentialRepo := repository.NewWebAuthnCredentialRepository(db.DB)
+	privacyRepo := repository.NewPrivacyRepository(db.DB)
+
+	// Initialize services
+	tokenSigner, err := services.NewTokenSigner(appConfig, logger)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to load JWT signing keys: %v", err))
 	}
-	config.AllowOrigins = []string{frontendURL}
-	config.AllowCredentials = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
-	router.Use(cors.New(config))
+	emailService := email.NewEmailService(appConfig.Email.From, appConfig.Email.Password, appConfig.Email.SMTPHost, appConfig.Email.SMTPPort)
+	auditWriter := services.NewAuditWriter(auditRepo, logger)
+	planService := services.NewPlanService(clientRepo, taskRepo, logger)
+	mfaService := services.NewMFAService(userRepo, mfaRecoveryCodeRepo, logger, appConfig)
+	loginAttempts := services.NewMemoryAttemptTracker(appConfig.Login.IPMaxFailedAttempts, appConfig.Login.LockoutBase, appConfig.Login.LockoutMax)
+	authService := services.NewAuthService(userRepo, sessionRepo, revokedTokenRepo, tokenSigner, mfaService, loginAttempts, emailService, auditWriter, logger, appConfig)
+	var oidcClient *oidc.Client
+	if appConfig.OIDC.Issuer != "" {
+		oidcClient = oidc.NewClient(oidc.Config{
+			Issuer:       appConfig.OIDC.Issuer,
+			ClientID:     appConfig.OIDC.ClientID,
+			ClientSecret: appConfig.OIDC.ClientSecret,
+			RedirectURL:  appConfig.OIDC.RedirectURL,
+			Scopes:       appConfig.OIDC.Scopes,
+		}, nil)
+	}
+	oidcService := services.NewOIDCService(oidcClient, userRepo, userIdentityRepo, authService, logger, appConfig)
+	apiTokenService := services.NewAPITokenService(apiTokenRepo, logger)
+	tagService := services.NewTagService(tagRepo, logger)
+	customFieldService := services.NewCustomFieldService(customFieldRepo, logger)
+	clientService := services.NewClientService(clientRepo, clientActivityRepo, planService, tagService, customFieldService, logger)
+	clientContactService := services.NewClientContactService(clientContactRepo, clientService, logger)
+	clientActivityService := services.NewClientActivityService(clientActivityRepo, clientService, logger)
+	clientImportService := services.NewClientImportService(clientRepo, planService, logger)
+	clientExportService := services.NewClientExportService(clientRepo, logger)
+	clientMergeService := services.NewClientMergeService(clientRepo, clientService, logger)
+	taskService := services.NewTaskService(taskRepo, clientRepo, logger)
+	paymentService := services.NewPaymentService(paymentRepo, clientRepo, taskRepo, logger)
+	paymentNoticeService := services.NewPaymentNoticeService(paymentService, clientContactService, userRepo, emailService, logger)
+	passwordResetService := services.NewPasswordResetService(userRepo, authService, emailService, auditWriter, logger, appConfig)
+	magicLinkService := services.NewMagicLinkService(userRepo, magicLinkRepo, authService, emailService, logger, appConfig)
+	webAuthnService, err := services.NewWebAuthnService(webAuthnCredentialRepo, authService, logger, appConfig)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("Failed to configure passkeys (WebAuthn): %v", err))
+	}
+	privacyService := services.NewPrivacyService(userRepo, privacyRepo, authService, mfaService, emailService, auditWriter, logger, appConfig)
+	auditService := services.NewAuditService(auditRepo, logger)
+	profileService := services.NewProfileService(userRepo, authService, tokenSigner, emailService, logger, appConfig)
+	adminService := services.NewAdminService(userRepo, clientRepo, taskRepo, paymentRepo, authService, auditWriter, logger)
 
-	// Initialize repositories, services and handlers
-	userRepo := repository.NewUserRepository(db.DB)
-	authService := services.NewAuthService(userRepo, logger, dbConfig)
+	// Initialize handlers
 	authHandler := api.NewAuthHandler(authService, logger)
-
-	// Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Setup routes
-	// Public routes
-	router.POST("/api/auth/register", authHandler.Register)
-	router.POST("/api/auth/login", authHandler.Login)
-	router.POST("/api/auth/refresh", authHandler.RefreshToken)
-
-	// Protected routes
-	authRoutes := router.Group("/api")
-	authRoutes.Use(middleware.JWTAuth())
-	{
-		// User routes
-		authRoutes.GET("/user/profile", authHandler.GetProfile)
-		
-		// TODO: Implement these routes
-		// Client routes
-		// Task routes
-		// Payment routes
-	}
+	mfaHandler := api.NewMFAHandler(mfaService, logger)
+	profileHandler := api.NewProfileHandler(profileService, logger)
+	passwordResetHandler := api.NewPasswordResetHandler(passwordResetService, logger)
+	magicLinkHandler := api.NewMagicLinkHandler(magicLinkService, logger)
+	passkeyHandler := api.NewPasskeyHandler(webAuthnService, logger)
+	privacyHandler := api.NewPrivacyHandler(privacyService, logger)
+	auditHandler := api.NewAuditHandler(auditService, logger)
+	apiTokenHandler := api.NewAPITokenHandler(apiTokenService, logger)
+	oidcHandler := api.NewOIDCHandler(oidcService, logger)
+	keysHandler := api.NewKeysHandler(tokenSigner)
+	clientHandler := api.NewClientHandler(clientService, logger)
+	clientContactHandler := api.NewClientContactHandler(clientContactService, logger)
+	clientActivityHandler := api.NewClientActivityHandler(clientActivityService, logger)
+	clientImportHandler := api.NewClientImportHandler(clientImportService, logger)
+	clientExportHandler := api.NewClientExportHandler(clientExportService, logger)
+	clientMergeHandler := api.NewClientMergeHandler(clientMergeService, logger)
+	tagHandler := api.NewTagHandler(tagService, logger)
+	customFieldHandler := api.NewCustomFieldHandler(customFieldService, logger)
+	taskHandler := api.NewTaskHandler(taskService, logger)
+	paymentHandler := api.NewPaymentHandler(paymentService, logger)
+	paymentNoticeHandler := api.NewPaymentNoticeHandler(paymentNoticeService, logger)
+	adminHandler := api.NewAdminHandler(adminService, logger)
+
+	// Setup routes (every route is registered by api.SetupRoutes)
+	router := api.NewRouter(appConfig, authService, apiTokenService, auditWriter, logger)
+	router.SetupRoutes(keysHandler, authHandler, profileHandler, passwordResetHandler, magicLinkHandler, passkeyHandler, privacyHandler, auditHandler, mfaHandler, oidcHandler, apiTokenHandler, clientHandler, clientContactHandler, clientActivityHandler, clientImportHandler, clientExportHandler, clientMergeHandler, tagHandler, customFieldHandler, taskHandler, paymentHandler, paymentNoticeHandler, adminHandler)
+
+	// Periodically erase the data of accounts whose deletion grace period is over
+	services.StartAccountPurge(privacyService, appConfig.Privacy.PurgeInterval, logger)
 
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
-		port = "8080"
+		port = appConfig.Server.Port
 	}
 
 	logger.Info(fmt.Sprintf("Server running on port %s", port))
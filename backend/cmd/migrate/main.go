@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
 )
 
 func main() {
@@ -37,12 +38,30 @@ func main() {
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.Client{},
+		&models.ClientContact{},
+		&models.Tag{},
+		&models.CustomFieldDefinition{},
 		&models.Task{},
 		&models.Payment{},
+		&models.ClientActivity{},
+		&models.Session{},
+		&models.RevokedToken{},
+		&models.MFARecoveryCode{},
+		&models.AuditEvent{},
+		&models.APIToken{},
+		&models.UserIdentity{},
+		&models.MagicLink{},
+		&models.WebAuthnCredential{},
+		&models.DataExport{},
 	)
 	if err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
 
+	// Full-text search over clients (unaccent extension, generated column and GIN index)
+	if err := repository.MigrateClientSearch(db); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
 	log.Println("Migrations completed successfully!")
 }
@@ -1,10 +1,16 @@
 package configs
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
 )
 
 // Config representa as configurações da aplicação
@@ -12,11 +18,18 @@ type Config struct {
 	Server   ServerConfig
 	DB       DBConfig
 	JWT      JWTConfig
+	MFA      MFAConfig
+	Login    LoginConfig
+	Email    EmailConfig
+	OIDC     OIDCConfig
+	WebAuthn WebAuthnConfig
+	Privacy  PrivacyConfig
 }
 
 // ServerConfig representa as configurações do servidor
 type ServerConfig struct {
 	Port string
+	Mode string // modo do gin (debug, release, test)
 }
 
 // DBConfig representa as configurações do banco de dados
@@ -31,21 +44,85 @@ type DBConfig struct {
 
 // JWTConfig representa as configurações do JWT
 type JWTConfig struct {
-	Secret        string
-	AccessTokenTTL time.Duration
+	SigningAlgorithm     string // algoritmo da chave de assinatura: EdDSA (Ed25519) ou RS256 (RSA)
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	SigningKeyFile       string        // chave privada PEM (RSA ou Ed25519) usada para assinar os tokens
+	VerificationKeyFiles []string      // chaves públicas PEM de chaves anteriores, aceitas durante a rotação
+	ImpersonationTTL     time.Duration // validade do token de acesso de suporte emitido a administradores
+}
+
+// MFAConfig representa as configurações da autenticação em dois fatores (TOTP)
+type MFAConfig struct {
+	Issuer          string
+	EncryptionKey   string        // chave usada para cifrar os segredos TOTP (AES-256-GCM); obrigatória em modo release
+	PendingTokenTTL time.Duration // validade do token "mfa_pending" emitido após a senha
+}
+
+// LoginConfig representa as configurações de proteção contra força bruta no login
+type LoginConfig struct {
+	MaxFailedAttempts   int           // falhas consecutivas por conta antes do bloqueio temporário
+	IPMaxFailedAttempts int           // falhas por IP antes de aplicar o backoff
+	LockoutBase         time.Duration // duração do primeiro bloqueio; dobra a cada nova falha
+	LockoutMax          time.Duration // duração máxima de um bloqueio
+}
+
+// EmailConfig representa as configurações de envio de e-mails e dos links enviados
+type EmailConfig struct {
+	SMTPHost              string
+	SMTPPort              string
+	From                  string
+	Password              string
+	AppURL                string        // endereço do frontend usado para montar os links enviados por e-mail
+	VerificationTokenTTL  time.Duration // validade do link de confirmação de e-mail
+	PasswordResetTokenTTL time.Duration // validade do link de redefinição de senha
+	MagicLinkTTL          time.Duration // validade do link de login sem senha
+	ResendLimit           int           // reenvios permitidos por e-mail (e 3x esse valor por IP) dentro da janela
+	ResendWindow          time.Duration
+}
+
+// OIDCConfig representa o cadastro da aplicação no provedor de identidade OpenID Connect.
+// O login via OIDC fica desativado enquanto Issuer estiver vazio.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // página do frontend que recebe o código e o state
+	Scopes       []string
+	StateTTL     time.Duration // tempo máximo entre o início do login e o retorno do provedor
+}
+
+// WebAuthnConfig representa o relying party das passkeys.
+// As passkeys ficam vinculadas ao RP ID: trocá-lo invalida as credenciais já cadastradas.
+type WebAuthnConfig struct {
+	RPID                    string   // domínio do frontend (ou um domínio pai dele)
+	RPName                  string   // nome exibido pelo navegador ao criar a passkey
+	Origins                 []string // origens do frontend autorizadas a usar as passkeys
+	Timeout                 time.Duration
+	RequireUserVerification bool // exige biometria ou PIN do autenticador, além da presença do usuário
+}
+
+// PrivacyConfig representa as configurações de exportação e exclusão dos dados do usuário (LGPD)
+type PrivacyConfig struct {
+	ExportTTL           time.Duration // validade do link de download da exportação
+	ExportLimit         int           // exportações permitidas por usuário dentro da janela
+	ExportWindow        time.Duration
+	DeletionGracePeriod time.Duration // prazo entre a solicitação de exclusão e a remoção dos dados
+	PurgeInterval       time.Duration // intervalo entre as execuções da remoção das contas vencidas
 }
 
 // LoadConfig carrega as configurações da aplicação
 func LoadConfig() (*Config, error) {
-	// Carrega o arquivo .env
+	// Carrega o arquivo .env, se existir; em contêineres as variáveis vêm do ambiente
 	err := godotenv.Load()
-	if err != nil {
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return nil, err
 	}
 
 	return &Config{
 		Server: ServerConfig{
 			Port: getEnv("SERVER_PORT", "8080"),
+			Mode: getEnv("GIN_MODE", "debug"),
 		},
 		DB: DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -56,12 +133,117 @@ func LoadConfig() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:        getEnv("JWT_SECRET", "your-256-bit-secret"),
-			AccessTokenTTL: time.Hour * 24, // 24 horas
+			SigningAlgorithm:     getEnv("JWT_SIGNING_ALG", jwk.AlgEdDSA),
+			AccessTokenTTL:       getDurationEnv("JWT_ACCESS_TOKEN_TTL", time.Minute*15),   // 15 minutos
+			RefreshTokenTTL:      getDurationEnv("JWT_REFRESH_TOKEN_TTL", time.Hour*24*30), // 30 dias
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: strings.FieldsFunc(getEnv("JWT_VERIFICATION_KEY_FILES", ""), isListSeparator),
+			ImpersonationTTL:     getDurationEnv("JWT_IMPERSONATION_TTL", time.Minute*30), // 30 minutos
+		},
+		MFA: MFAConfig{
+			Issuer:          getEnv("MFA_ISSUER", "CRM Freela"),
+			EncryptionKey:   getEnv("MFA_ENCRYPTION_KEY", ""),
+			PendingTokenTTL: getDurationEnv("MFA_PENDING_TOKEN_TTL", time.Minute*5), // 5 minutos
+		},
+		Login: LoginConfig{
+			MaxFailedAttempts:   getIntEnv("LOGIN_MAX_FAILED_ATTEMPTS", 5),
+			IPMaxFailedAttempts: getIntEnv("LOGIN_IP_MAX_FAILED_ATTEMPTS", 20),
+			LockoutBase:         getDurationEnv("LOGIN_LOCKOUT_BASE", time.Minute),
+			LockoutMax:          getDurationEnv("LOGIN_LOCKOUT_MAX", time.Hour),
+		},
+		Email: EmailConfig{
+			SMTPHost:              getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:              getEnv("SMTP_PORT", "587"),
+			From:                  getEnv("SMTP_FROM", "no-reply@crmfreela.local"),
+			Password:              getEnv("SMTP_PASSWORD", ""),
+			AppURL:                getEnv("APP_URL", "http://localhost:3000"),
+			VerificationTokenTTL:  getDurationEnv("EMAIL_VERIFICATION_TOKEN_TTL", time.Hour*24), // 24 horas
+			PasswordResetTokenTTL: getDurationEnv("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+			MagicLinkTTL:          getDurationEnv("MAGIC_LINK_TTL", 10*time.Minute),
+			ResendLimit:           getIntEnv("EMAIL_RESEND_LIMIT", 3),
+			ResendWindow:          getDurationEnv("EMAIL_RESEND_WINDOW", time.Hour),
+		},
+		OIDC: OIDCConfig{
+			Issuer:       getEnv("OIDC_ISSUER", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", getEnv("APP_URL", "http://localhost:3000")+"/auth/oidc/callback"),
+			Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+			StateTTL:     getDurationEnv("OIDC_STATE_TTL", time.Minute*10),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:                    getEnv("WEBAUTHN_RP_ID", hostname(getEnv("APP_URL", "http://localhost:3000"))),
+			RPName:                  getEnv("WEBAUTHN_RP_NAME", "CRM Freela"),
+			Origins:                 strings.FieldsFunc(getEnv("WEBAUTHN_ORIGINS", getEnv("APP_URL", "http://localhost:3000")), isListSeparator),
+			Timeout:                 getDurationEnv("WEBAUTHN_TIMEOUT", time.Minute*5),
+			RequireUserVerification: getEnv("WEBAUTHN_REQUIRE_USER_VERIFICATION", "true") == "true",
+		},
+		Privacy: PrivacyConfig{
+			ExportTTL:           getDurationEnv("DATA_EXPORT_TTL", time.Hour*24),
+			ExportLimit:         getIntEnv("DATA_EXPORT_LIMIT", 3),
+			ExportWindow:        getDurationEnv("DATA_EXPORT_WINDOW", time.Hour*24),
+			DeletionGracePeriod: getDurationEnv("ACCOUNT_DELETION_GRACE_PERIOD", time.Hour*24*30), // 30 dias
+			PurgeInterval:       getDurationEnv("ACCOUNT_PURGE_INTERVAL", time.Hour),
 		},
 	}, nil
 }
 
+// Validate verifica as configurações obrigatórias e as que não podem ficar com valores padrão em produção
+func (c *Config) Validate() error {
+	if err := c.JWT.validateKeys(); err != nil {
+		return err
+	}
+
+	if c.Server.Mode != "release" {
+		return nil
+	}
+
+	if c.JWT.SigningKeyFile == "" {
+		return errors.New("JWT_SIGNING_KEY_FILE deve ser definido em modo release")
+	}
+
+	// Fora do modo release, os segredos TOTP são cifrados com uma chave de desenvolvimento (ver services.NewMFAService)
+	if c.MFA.EncryptionKey == "" {
+		return errors.New("MFA_ENCRYPTION_KEY deve ser definido em modo release")
+	}
+
+	return nil
+}
+
+// validateKeys confere que o algoritmo é suportado e que os arquivos de chave existem, podem ser
+// lidos e, no caso da chave de assinatura, são do tipo exigido pelo algoritmo configurado
+func (c JWTConfig) validateKeys() error {
+	if c.SigningAlgorithm != jwk.AlgEdDSA && c.SigningAlgorithm != jwk.AlgRS256 {
+		return fmt.Errorf("JWT_SIGNING_ALG deve ser %s ou %s", jwk.AlgEdDSA, jwk.AlgRS256)
+	}
+
+	if c.SigningKeyFile != "" {
+		private, err := jwk.LoadPrivateKey(c.SigningKeyFile)
+		if err != nil {
+			return fmt.Errorf("JWT_SIGNING_KEY_FILE inválido: %w", err)
+		}
+		alg, err := jwk.Algorithm(private.Public())
+		if err != nil {
+			return fmt.Errorf("JWT_SIGNING_KEY_FILE inválido: %w", err)
+		}
+		if alg != c.SigningAlgorithm {
+			return fmt.Errorf("JWT_SIGNING_KEY_FILE contém uma chave %s, mas JWT_SIGNING_ALG é %s", alg, c.SigningAlgorithm)
+		}
+	}
+
+	for _, path := range c.VerificationKeyFiles {
+		public, err := jwk.LoadPublicKey(path)
+		if err != nil {
+			return fmt.Errorf("JWT_VERIFICATION_KEY_FILES inválido: %w", err)
+		}
+		if _, err := jwk.Algorithm(public); err != nil {
+			return fmt.Errorf("JWT_VERIFICATION_KEY_FILES inválido (%s): %w", path, err)
+		}
+	}
+
+	return nil
+}
+
 // getEnv retorna o valor de uma variável de ambiente ou um valor padrão
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -70,3 +252,35 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getDurationEnv retorna a duração definida em uma variável de ambiente (ex.: "15m", "720h") ou um valor padrão
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getIntEnv retorna o inteiro definido em uma variável de ambiente ou um valor padrão
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// hostname retorna o host (sem porta) de uma URL, ou vazio se ela for inválida
+func hostname(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// isListSeparator separa listas definidas em variáveis de ambiente por vírgula ou espaço
+func isListSeparator(r rune) bool {
+	return r == ',' || r == ' '
+}
@@ -0,0 +1,103 @@
+package configs_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpcode092/crm-freela/configs"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
+)
+
+// writeKey grava a chave privada em PEM (PKCS#8) em um arquivo temporário
+func writeKey(t *testing.T, key interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwt-signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateSigningKey(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519File := writeKey(t, ed25519Key)
+	rsaFile := writeKey(t, rsaKey)
+
+	notPEM := filepath.Join(t.TempDir(), "jwt-signing.pem")
+	if err := os.WriteFile(notPEM, []byte("não é uma chave"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		alg     string
+		keyFile string
+		wantErr string // trecho esperado da mensagem; vazio se a configuração for válida
+	}{
+		{"chave temporária em desenvolvimento", "debug", jwk.AlgEdDSA, "", ""},
+		{"Ed25519 com EdDSA", "release", jwk.AlgEdDSA, ed25519File, ""},
+		{"RSA com RS256", "release", jwk.AlgRS256, rsaFile, ""},
+		{"sem chave em release", "release", jwk.AlgEdDSA, "", "JWT_SIGNING_KEY_FILE deve ser definido"},
+		{"algoritmo não suportado", "debug", "HS256", "", "JWT_SIGNING_ALG"},
+		{"arquivo inexistente", "debug", jwk.AlgEdDSA, filepath.Join(t.TempDir(), "ausente.pem"), "JWT_SIGNING_KEY_FILE inválido"},
+		{"arquivo sem PEM", "debug", jwk.AlgEdDSA, notPEM, "JWT_SIGNING_KEY_FILE inválido"},
+		{"RSA com EdDSA", "debug", jwk.AlgEdDSA, rsaFile, "chave RS256"},
+		{"Ed25519 com RS256", "release", jwk.AlgRS256, ed25519File, "chave EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &configs.Config{
+				Server: configs.ServerConfig{Mode: tt.mode},
+				JWT:    configs.JWTConfig{SigningAlgorithm: tt.alg, SigningKeyFile: tt.keyFile},
+				MFA:    configs.MFAConfig{EncryptionKey: "chave-de-teste"},
+			}
+
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMFAEncryptionKey(t *testing.T) {
+	config := &configs.Config{JWT: configs.JWTConfig{SigningAlgorithm: jwk.AlgEdDSA}}
+
+	// Em desenvolvimento, a ausência da chave é suprida pela chave de desenvolvimento do MFAService
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate em desenvolvimento: %v", err)
+	}
+
+	config.Server.Mode = "release"
+	config.JWT.SigningKeyFile = writeKey(t, ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "MFA_ENCRYPTION_KEY") {
+		t.Fatalf("erro = %v, esperado MFA_ENCRYPTION_KEY obrigatório em release", err)
+	}
+}
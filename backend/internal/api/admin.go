@@ -0,0 +1,360 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/middleware"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ChangePlanRequest representa os dados de requisição para alterar o plano de um usuário
+type ChangePlanRequest struct {
+	Plan string `json:"plan" binding:"required,oneof=free basic pro" example:"pro"`
+}
+
+// ChangeRoleRequest representa os dados de requisição para alterar o papel de um usuário
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin user" example:"admin"`
+}
+
+// ImpersonateRequest representa os dados de requisição para iniciar um acesso de suporte
+type ImpersonateRequest struct {
+	Reason     string `json:"reason" binding:"required,max=500" example:"Chamado #1234: cliente não vê as tarefas"`
+	AllowWrite bool   `json:"allow_write"` // por padrão, o acesso é somente leitura
+}
+
+// EndImpersonationRequest representa o token de acesso de suporte a ser encerrado
+type EndImpersonationRequest struct {
+	AccessToken string `json:"access_token" binding:"required"`
+}
+
+// AdminHandler gerencia as requisições administrativas sobre usuários
+type AdminHandler struct {
+	adminService services.AdminService
+	logger       logger.Logger
+}
+
+// NewAdminHandler cria uma nova instância de AdminHandler
+func NewAdminHandler(adminService services.AdminService, logger logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+// ListUsers godoc
+// @Summary      Listar usuários
+// @Description  Lista os usuários com busca por nome ou e-mail e filtros por status, papel e plano
+// @Tags         admin
+// @Produce      json
+// @Security     Bearer
+// @Param        q          query  string  false  "Busca por nome ou e-mail"
+// @Param        status     query  string  false  "Status (active, inactive, blocked)"
+// @Param        role       query  string  false  "Papel (admin, user)"
+// @Param        plan       query  string  false  "Plano (free, basic, pro)"
+// @Param        page       query  int     false  "Página"
+// @Param        page_size  query  int     false  "Itens por página"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := repository.UserFilter{
+		Query:  c.Query("q"),
+		Status: models.UserStatus(c.Query("status")),
+		Role:   models.UserRole(c.Query("role")),
+		Plan:   models.PlanType(c.Query("plan")),
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	users, total, err := h.adminService.ListUsers(filter, page, pageSize)
+	if err != nil {
+		h.logger.Error("Erro ao listar usuários: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar usuários"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": users,
+		"meta": gin.H{
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// GetUser godoc
+// @Summary      Detalhar usuário
+// @Description  Retorna o usuário com o total de clientes, tarefas e pagamentos da conta
+// @Tags         admin
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID do usuário"
+// @Success      200  {object}  services.UserStats
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.adminService.GetUserStats(id)
+	if err != nil {
+		h.respondError(c, err, "Erro ao buscar usuário")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// BlockUser godoc
+// @Summary      Bloquear usuário
+// @Description  Bloqueia o usuário e revoga todas as suas sessões
+// @Tags         admin
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID do usuário"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Operação inválida"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id}/block [post]
+func (h *AdminHandler) BlockUser(c *gin.Context) {
+	h.setStatus(c, models.UserStatusBlocked, "Usuário bloqueado com sucesso")
+}
+
+// UnblockUser godoc
+// @Summary      Desbloquear usuário
+// @Description  Reativa um usuário bloqueado
+// @Tags         admin
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID do usuário"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Operação inválida"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id}/unblock [post]
+func (h *AdminHandler) UnblockUser(c *gin.Context) {
+	h.setStatus(c, models.UserStatusActive, "Usuário desbloqueado com sucesso")
+}
+
+// ChangePlan godoc
+// @Summary      Alterar plano do usuário
+// @Description  Altera o plano de assinatura do usuário
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                true  "ID do usuário"
+// @Param        request  body  ChangePlanRequest  true  "Novo plano"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id}/plan [put]
+func (h *AdminHandler) ChangePlan(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.ChangePlan(auditContext(c), id, models.PlanType(req.Plan))
+	if err != nil {
+		h.respondError(c, err, "Erro ao alterar plano do usuário")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Plano alterado com sucesso",
+		"user":    user,
+	})
+}
+
+// ChangeRole godoc
+// @Summary      Alterar papel do usuário
+// @Description  Altera o papel do usuário (admin ou user). Não é possível alterar o próprio papel
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                true  "ID do usuário"
+// @Param        request  body  ChangeRoleRequest  true  "Novo papel"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, err := h.adminService.ChangeRole(auditContext(c), id, models.UserRole(req.Role))
+	if err != nil {
+		h.respondError(c, err, "Erro ao alterar papel do usuário")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Papel alterado com sucesso",
+		"user":    user,
+	})
+}
+
+// Impersonate godoc
+// @Summary      Iniciar acesso de suporte
+// @Description  Emite um access token de curta duração para ver a conta como o usuário a vê. O token é somente leitura, salvo allow_write, e cada requisição é registrada na trilha de auditoria com o ID do administrador
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                 true  "ID do usuário"
+// @Param        request  body  ImpersonateRequest  true  "Motivo do acesso"
+// @Success      201  {object}  services.ImpersonationToken
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos ou operação inválida"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      404  {object}  map[string]interface{} "Usuário não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/users/{id}/impersonate [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	token, err := h.adminService.StartImpersonation(auditContext(c), id, req.AllowWrite, req.Reason)
+	if err != nil {
+		h.respondError(c, err, "Erro ao iniciar acesso de suporte")
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// EndImpersonation godoc
+// @Summary      Encerrar acesso de suporte
+// @Description  Revoga, antes de expirar, um token de acesso de suporte emitido ao administrador autenticado
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request  body  EndImpersonationRequest  true  "Token de acesso de suporte"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Token inválido"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/impersonation/end [post]
+func (h *AdminHandler) EndImpersonation(c *gin.Context) {
+	var req EndImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.adminService.EndImpersonation(auditContext(c), req.AccessToken); err != nil {
+		switch err {
+		case errors.ErrNotImpersonating, errors.ErrInvalidToken, errors.ErrTokenExpired, errors.ErrTokenRevoked:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token de acesso de suporte inválido ou já encerrado"})
+		default:
+			h.logger.Error("Erro ao encerrar acesso de suporte: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar acesso de suporte"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Acesso de suporte encerrado"})
+}
+
+// setStatus altera o status do usuário indicado na rota
+func (h *AdminHandler) setStatus(c *gin.Context, status models.UserStatus, message string) {
+	id, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.SetUserStatus(auditContext(c), id, status)
+	if err != nil {
+		h.respondError(c, err, "Erro ao alterar status do usuário")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"user":    user,
+	})
+}
+
+// respondError converte os erros do serviço administrativo em respostas HTTP
+func (h *AdminHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case errors.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+	case errors.ErrSelfAdminAction:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível alterar a própria conta"})
+	case errors.ErrCannotImpersonate:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Não é possível acessar a conta de outro administrador"})
+	case errors.ErrUserDeactivated:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Usuário desativado"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// parseUserID lê o ID do usuário da rota, respondendo com 400 quando inválido
+func parseUserID(c *gin.Context) (uint, bool) {
+	return parsePathID(c, "id")
+}
+
+// auditContext identifica o usuário autenticado e a origem da requisição para a trilha de auditoria
+func auditContext(c *gin.Context) services.AuditContext {
+	ctx := services.AuditContext{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	// Em um acesso de suporte, o autor é o administrador, não o usuário do token
+	if admin, ok := middleware.Impersonator(c); ok {
+		ctx.ActorID = admin.ID
+	} else if user, ok := middleware.CurrentUser(c); ok {
+		ctx.ActorID = user.ID
+	}
+	return ctx
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// defaultAPITokenDays é a validade aplicada quando a requisição não informa expires_in_days
+const defaultAPITokenDays = 90
+
+// CreateAPITokenRequest representa os dados de requisição para criação de um token de acesso pessoal
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100" example:"Script de faturamento"`
+	Scopes        []string `json:"scopes" binding:"required,min=1" example:"clients:read,payments:write"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=0,max=365" example:"90"` // 0 cria um token sem expiração
+}
+
+// APITokenResponse representa um token de acesso pessoal na listagem
+type APITokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APITokenHandler gerencia as requisições de tokens de acesso pessoal
+type APITokenHandler struct {
+	apiTokenService services.APITokenService
+	logger          logger.Logger
+}
+
+// NewAPITokenHandler cria uma nova instância de APITokenHandler
+func NewAPITokenHandler(apiTokenService services.APITokenService, logger logger.Logger) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+		logger:          logger,
+	}
+}
+
+// Create godoc
+// @Summary      Criar token de acesso pessoal
+// @Description  Cria um token com nome, escopos e validade para scripts e integrações. O token é exibido uma única vez
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body CreateAPITokenRequest true "Dados do token"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/tokens [post]
+func (h *APITokenHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	days := defaultAPITokenDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+
+	token, plain, err := h.apiTokenService.Create(user.ID, req.Name, req.Scopes, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "available_scopes": models.APITokenScopes})
+			return
+		}
+		h.logger.Error("Erro ao criar token de acesso: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar token de acesso"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Token criado. Copie-o agora: ele não será exibido novamente",
+		"token":     plain,
+		"api_token": apiTokenResponse(token),
+	})
+}
+
+// List godoc
+// @Summary      Listar tokens de acesso pessoal
+// @Description  Lista os tokens ativos do usuário, com escopos, validade e último uso
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/tokens [get]
+func (h *APITokenHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.apiTokenService.List(user.ID)
+	if err != nil {
+		h.logger.Error("Erro ao listar tokens de acesso: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar tokens de acesso"})
+		return
+	}
+
+	data := make([]APITokenResponse, 0, len(tokens))
+	for i := range tokens {
+		data = append(data, apiTokenResponse(&tokens[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Revoke godoc
+// @Summary      Revogar token de acesso pessoal
+// @Description  Revoga um token de acesso do usuário
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID do token"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Token não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/tokens/{id} [delete]
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := h.apiTokenService.Revoke(user.ID, uint(id)); err != nil {
+		if err == errors.ErrAPITokenNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Token não encontrado"})
+			return
+		}
+		h.logger.Error("Erro ao revogar token de acesso: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revogar token de acesso"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revogado com sucesso"})
+}
+
+// apiTokenResponse converte o token para a resposta da API, sem o hash
+func apiTokenResponse(token *models.APIToken) APITokenResponse {
+	return APITokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.ScopeList(),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		LastUsedIP: token.LastUsedIP,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// AuditHandler gerencia as consultas à trilha de auditoria
+type AuditHandler struct {
+	auditService services.AuditService
+	logger       logger.Logger
+}
+
+// NewAuditHandler cria uma nova instância de AuditHandler
+func NewAuditHandler(auditService services.AuditService, logger logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// ListMyEvents godoc
+// @Summary      Listar eventos de segurança
+// @Description  Lista os eventos de segurança da conta do usuário (logins, renovações de sessão, redefinições de senha, ações administrativas e exportações de dados), do mais recente ao mais antigo
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Param        page       query  int  false  "Página"
+// @Param        page_size  query  int  false  "Itens por página"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/security-events [get]
+func (h *AuditHandler) ListMyEvents(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	events, total, err := h.auditService.ListUserEvents(user.ID, page, pageSize)
+	if err != nil {
+		h.logger.Error("Erro ao listar eventos de segurança: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar eventos de segurança"})
+		return
+	}
+
+	respondEvents(c, events, total, page, pageSize)
+}
+
+// ListEvents godoc
+// @Summary      Consultar trilha de auditoria
+// @Description  Lista os eventos da trilha de auditoria com filtros por conta afetada, autor, ação, resultado, IP e período
+// @Tags         admin
+// @Produce      json
+// @Security     Bearer
+// @Param        user_id    query  int     false  "Conta afetada"
+// @Param        actor_id   query  int     false  "Autor da ação"
+// @Param        action     query  string  false  "Ação (ex.: auth.login, admin.plan_changed)"
+// @Param        outcome    query  string  false  "Resultado (success, failure)"
+// @Param        ip         query  string  false  "IP de origem"
+// @Param        from       query  string  false  "Início do período (RFC 3339 ou AAAA-MM-DD)"
+// @Param        to         query  string  false  "Fim do período, exclusivo (RFC 3339 ou AAAA-MM-DD)"
+// @Param        page       query  int     false  "Página"
+// @Param        page_size  query  int     false  "Itens por página"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Filtro inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      403  {object}  map[string]interface{} "Acesso negado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /admin/audit-events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	filter := repository.AuditEventFilter{
+		Action:  models.AuditAction(c.Query("action")),
+		Outcome: models.AuditOutcome(c.Query("outcome")),
+		IP:      c.Query("ip"),
+	}
+
+	var ok bool
+	if filter.UserID, ok = queryID(c, "user_id"); !ok {
+		return
+	}
+	if filter.ActorID, ok = queryID(c, "actor_id"); !ok {
+		return
+	}
+	if filter.From, ok = queryTime(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = queryTime(c, "to"); !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	events, total, err := h.auditService.Search(filter, page, pageSize)
+	if err != nil {
+		h.logger.Error("Erro ao consultar trilha de auditoria: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao consultar trilha de auditoria"})
+		return
+	}
+
+	respondEvents(c, events, total, page, pageSize)
+}
+
+// respondEvents responde com a página de eventos no formato das demais listagens
+func respondEvents(c *gin.Context, events []models.AuditEvent, total int64, page, pageSize int) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": events,
+		"meta": gin.H{
+			"total":     total,
+			"page":      page,
+			"page_size": pageSize,
+		},
+	})
+}
+
+// queryID lê um ID opcional da query string, respondendo com 400 quando inválido
+func queryID(c *gin.Context, name string) (uint, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Filtro " + name + " inválido"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// queryTime lê uma data opcional da query string (RFC 3339 ou AAAA-MM-DD), respondendo com 400 quando inválida
+func queryTime(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Filtro " + name + " inválido; use RFC 3339 ou AAAA-MM-DD"})
+		return nil, false
+	}
+	return &t, true
+}
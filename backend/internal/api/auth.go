@@ -1,10 +1,16 @@
 package api
 
 import (
+	stderrors "errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/middleware"
+	"github.com/jpcode092/crm-freela/internal/models"
 	"github.com/jpcode092/crm-freela/internal/services"
 	"github.com/jpcode092/crm-freela/pkg/logger"
 )
@@ -22,10 +28,32 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"123456"`
 }
 
+// VerifyMFARequest representa os dados de requisição para validação do segundo fator
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+// VerifyEmailRequest representa os dados de requisição para confirmação de e-mail
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest representa os dados de requisição para reenvio do link de confirmação
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// RefreshTokenRequest representa os dados de requisição para renovação de token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"Zk9xY2pQd0..."`
+}
+
 // AuthResponse representa a resposta da autenticação
 type AuthResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  struct {
+	Token  string             `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Tokens services.TokenPair `json:"tokens"`
+	User   struct {
 		ID    uint   `json:"id" example:"1"`
 		Name  string `json:"name" example:"John Doe"`
 		Email string `json:"email" example:"john@example.com"`
@@ -48,12 +76,12 @@ func NewAuthHandler(authService services.AuthService, logger logger.Logger) *Aut
 
 // Register godoc
 // @Summary      Registrar novo usuário
-// @Description  Registra um novo usuário no sistema
+// @Description  Registra um novo usuário no sistema. A conta permanece inativa até a confirmação do e-mail enviado
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body AuthRequest true "Dados do usuário"
-// @Success      201  {object}  AuthResponse
+// @Success      201  {object}  map[string]interface{}
 // @Failure      400  {object}  map[string]interface{} "Dados inválidos"
 // @Failure      409  {object}  map[string]interface{} "E-mail já cadastrado"
 // @Failure      500  {object}  map[string]interface{} "Erro interno"
@@ -82,38 +110,89 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Gera um token para o usuário recém-registrado
-	_, token, err := h.authService.Login(req.Email, req.Password)
-	if err != nil {
-		h.logger.Error("Erro ao gerar token após registro: " + err.Error())
-		// Mesmo que não consiga gerar o token, o registro foi bem-sucedido
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "Usuário registrado com sucesso, mas não foi possível gerar o token",
-			"user": gin.H{
-				"id":    user.ID,
-				"name":  user.Name,
-				"email": user.Email,
-			},
-		})
-		return
-	}
-
 	h.logger.Info("Usuário registrado com sucesso: " + user.Email)
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Usuário registrado com sucesso",
-		"token":   token,
+		"message":               "Usuário registrado com sucesso. Confirme seu e-mail para acessar a conta",
+		"verification_required": true,
 		"user": gin.H{
 			"id":    user.ID,
 			"name":  user.Name,
 			"email": user.Email,
-			"plan":  user.Plan,
 		},
 	})
 }
 
+// VerifyEmail godoc
+// @Summary      Confirmar e-mail
+// @Description  Confirma o e-mail do usuário a partir do token enviado no link de verificação e ativa a conta
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyEmailRequest true "Token do link de confirmação"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Link inválido ou expirado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(req.Token)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidToken, errors.ErrTokenRevoked:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de confirmação inválido", "code": "invalid_token"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de confirmação expirado", "code": "token_expired"})
+		default:
+			h.logger.Error("Erro ao confirmar e-mail: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao confirmar e-mail"})
+		}
+		return
+	}
+
+	h.logger.Info("E-mail confirmado com sucesso: " + user.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "E-mail confirmado com sucesso"})
+}
+
+// ResendVerification godoc
+// @Summary      Reenviar confirmação de e-mail
+// @Description  Reenvia o link de confirmação de e-mail. Responde com sucesso mesmo se o e-mail não estiver cadastrado
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResendVerificationRequest true "E-mail cadastrado"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.authService.ResendVerification(req.Email, c.ClientIP()); err != nil {
+		if respondLockout(c, err) {
+			h.logger.Warn("Reenvio de confirmação limitado: " + req.Email + " (IP " + c.ClientIP() + ")")
+			return
+		}
+		h.logger.Error("Erro ao reenviar confirmação de e-mail: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao reenviar confirmação de e-mail"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Se o e-mail estiver cadastrado e pendente de confirmação, um novo link foi enviado"})
+}
+
 // Login godoc
 // @Summary      Login de usuário
-// @Description  Autentica um usuário no sistema
+// @Description  Autentica um usuário no sistema. Se o usuário tiver MFA ativo, retorna mfa_required e um mfa_token a ser trocado em /auth/mfa/verify
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -121,6 +200,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success      200  {object}  AuthResponse
 // @Failure      400  {object}  map[string]interface{} "Dados inválidos"
 // @Failure      401  {object}  map[string]interface{} "Credenciais inválidas"
+// @Failure      403  {object}  map[string]interface{} "E-mail não confirmado (code email_not_verified), login com senha desativado (code password_login_disabled) ou usuário desativado"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
 // @Failure      500  {object}  map[string]interface{} "Erro interno"
 // @Router       /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -135,15 +216,21 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	
 	h.logger.Info("Processando login para email: " + req.Email)
 
-	user, token, err := h.authService.Login(req.Email, req.Password)
+	result, err := h.authService.Login(req.Email, req.Password, sessionMeta(c))
 	if err != nil {
+		if respondLockout(c, err) {
+			h.logger.Warn("Tentativa de login bloqueada: " + req.Email + " (IP " + c.ClientIP() + ")")
+			return
+		}
 		switch err {
-		case errors.ErrUserNotFound:
-			h.logger.Warn("Tentativa de login com usuário não encontrado: " + req.Email)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
-		case errors.ErrInvalidPassword:
-			h.logger.Warn("Tentativa de login com senha inválida: " + req.Email)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha inválida"})
+		case errors.ErrInvalidCredentials:
+			h.logger.Warn("Tentativa de login com credenciais inválidas: " + req.Email)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "E-mail ou senha inválidos"})
+		case errors.ErrEmailNotVerified:
+			h.logger.Warn("Tentativa de login com e-mail não confirmado: " + req.Email)
+			c.JSON(http.StatusForbidden, gin.H{"error": "E-mail não confirmado", "code": "email_not_verified"})
+		case errors.ErrPasswordLoginDisabled:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Login com senha desativado. Entre pelo link enviado ao seu e-mail", "code": "password_login_disabled"})
 		case errors.ErrUserDeactivated:
 			h.logger.Warn("Tentativa de login com usuário desativado: " + req.Email)
 			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
@@ -154,48 +241,99 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Login realizado com sucesso: " + user.Email)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login realizado com sucesso",
-		"token":   token,
-		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"plan":  user.Plan,
-		},
-	})
+	if result.MFARequired() {
+		h.logger.Info("Login aguardando segundo fator: " + req.Email)
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Informe o código de verificação",
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+
+	h.logger.Info("Login realizado com sucesso: " + req.Email)
+	respondWithTokens(c, http.StatusOK, "Login realizado com sucesso", result)
+}
+
+// VerifyMFA godoc
+// @Summary      Validar segundo fator
+// @Description  Troca o token "mfa_pending" retornado pelo login e um código TOTP (ou de recuperação) pelo par de tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyMFARequest true "Token pendente e código"
+// @Success      200  {object}  AuthResponse
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Token ou código inválido"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.authService.VerifyMFA(req.MFAToken, req.Code, sessionMeta(c))
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidToken, errors.ErrTokenRevoked:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expirado, faça login novamente"})
+		case errors.ErrInvalidMFACode:
+			h.logger.Warn("Código de verificação inválido a partir do IP " + c.ClientIP())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+		case errors.ErrUserDeactivated:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
+		default:
+			h.logger.Error("Erro ao validar segundo fator: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar segundo fator"})
+		}
+		return
+	}
+
+	h.logger.Info("Login realizado com sucesso: " + result.User.Email)
+	respondWithTokens(c, http.StatusOK, "Login realizado com sucesso", result)
 }
 
 // RefreshToken godoc
 // @Summary      Renovar token
-// @Description  Renova o token JWT do usuário
+// @Description  Troca um refresh token por um novo par de tokens (o refresh token apresentado é invalidado)
 // @Tags         auth
 // @Accept       json
 // @Produce      json
-// @Security     Bearer
-// @Success      200  {object}  map[string]string
+// @Param        request body RefreshTokenRequest true "Refresh token"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
 // @Failure      401  {object}  map[string]interface{} "Token inválido"
 // @Failure      500  {object}  map[string]interface{} "Erro interno"
 // @Router       /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	token := c.GetHeader("Authorization")
-	if token == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token não fornecido"})
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
 		return
 	}
 
-	newToken, err := h.authService.RefreshToken(token)
+	tokens, err := h.authService.RefreshToken(req.RefreshToken, sessionMeta(c))
 	if err != nil {
 		switch err {
 		case errors.ErrInvalidToken:
-			h.logger.Warn("Tentativa de renovar token inválido: " + token)
+			h.logger.Warn("Tentativa de renovar com refresh token inválido")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
 		case errors.ErrTokenExpired:
-			h.logger.Warn("Tentativa de renovar token expirado: " + token)
+			h.logger.Warn("Tentativa de renovar com refresh token expirado")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expirado"})
+		case errors.ErrRefreshTokenReused:
+			h.logger.Warn("Refresh token reutilizado a partir do IP " + c.ClientIP())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão revogada"})
 		case errors.ErrUserDeactivated:
-			h.logger.Warn("Tentativa de renovar token com usuário desativado: " + token)
+			h.logger.Warn("Tentativa de renovar token com usuário desativado")
 			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
 		default:
 			h.logger.Error("Erro ao renovar token: " + err.Error())
@@ -204,49 +342,189 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Token renovado com sucesso: " + newToken)
+	h.logger.Info("Token renovado com sucesso")
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Token renovado com sucesso",
-		"token":   newToken,
+		"tokens":  tokens,
 	})
 }
 
-// GetProfile godoc
-// @Summary      Obter perfil do usuário
-// @Description  Retorna os dados do perfil do usuário autenticado
+// Logout godoc
+// @Summary      Logout
+// @Description  Revoga o access token atual e encerra a sessão (família de refresh tokens) que o emitiu
+// @Tags         auth
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, exists := currentClaims(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	if err := h.authService.Logout(claims); err != nil {
+		h.logger.Error("Erro ao fazer logout: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao fazer logout"})
+		return
+	}
+
+	h.logger.Info(fmt.Sprintf("Logout realizado com sucesso: usuário %d", claims.UserID))
+	c.JSON(http.StatusOK, gin.H{"message": "Logout realizado com sucesso"})
+}
+
+// ListSessions godoc
+// @Summary      Listar sessões
+// @Description  Lista as sessões ativas do usuário autenticado (dispositivo, IP e último acesso)
 // @Tags         user
-// @Accept       json
 // @Produce      json
 // @Security     Bearer
 // @Success      200  {object}  map[string]interface{}
 // @Failure      401  {object}  map[string]interface{} "Não autorizado"
 // @Failure      500  {object}  map[string]interface{} "Erro interno"
-// @Router       /user/profile [get]
-func (h *AuthHandler) GetProfile(c *gin.Context) {
-	userID, exists := c.Get("userID")
+// @Router       /user/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	claims, exists := currentClaims(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
 		return
 	}
 
-	user, err := h.authService.GetUserByID(userID.(uint))
+	sessions, err := h.authService.ListSessions(claims.UserID)
 	if err != nil {
-		if err == errors.ErrUserNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+		h.logger.Error("Erro ao listar sessões: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar sessões"})
+		return
+	}
+
+	// Cada família de refresh tokens é apresentada como uma sessão; o último
+	// token emitido na família indica quando o dispositivo foi visto pela última vez
+	data := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, gin.H{
+			"id":           session.FamilyID,
+			"device":       session.UserAgent,
+			"ip":           session.IP,
+			"last_seen_at": session.CreatedAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.FamilyID == claims.SessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// RevokeSession godoc
+// @Summary      Revogar sessão
+// @Description  Encerra uma sessão do usuário autenticado, invalidando seus tokens
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path      string  true  "ID da sessão"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Sessão não encontrada"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	err := h.authService.RevokeSession(user.ID, c.Param("id"))
+	if err != nil {
+		if err == errors.ErrSessionNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sessão não encontrada"})
 			return
 		}
-		h.logger.Error("Erro ao buscar perfil: " + err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar perfil"})
+		h.logger.Error("Erro ao revogar sessão: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao revogar sessão"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sessão revogada com sucesso"})
+}
+
+// GetProfile godoc
+// @Summary      Obter perfil do usuário
+// @Description  Retorna os dados do perfil do usuário autenticado
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/profile [get]
+func (h *AuthHandler) GetProfile(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
 	h.logger.Info("Perfil do usuário obtido com sucesso: " + user.Email)
-	c.JSON(http.StatusOK, gin.H{
+	c.JSON(http.StatusOK, gin.H{"user": profileResponse(user)})
+}
+
+// respondLockout responde com 429 e o cabeçalho Retry-After quando o erro indica bloqueio por excesso de tentativas
+func respondLockout(c *gin.Context, err error) bool {
+	var lockout *services.LockoutError
+	if !stderrors.As(err, &lockout) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(lockout.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Muitas tentativas. Tente novamente mais tarde",
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+// respondWithTokens responde com o par de tokens e os dados básicos do usuário autenticado
+func respondWithTokens(c *gin.Context, status int, message string, result *services.LoginResult) {
+	c.JSON(status, gin.H{
+		"message": message,
+		"token":   result.Tokens.AccessToken,
+		"tokens":  result.Tokens,
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"plan":  user.Plan,
+			"id":    result.User.ID,
+			"name":  result.User.Name,
+			"email": result.User.Email,
+			"plan":  result.User.Plan,
 		},
 	})
 }
+
+// currentUser retorna o usuário carregado pelo AuthMiddleware, respondendo com 401 quando a rota não é autenticada
+func currentUser(c *gin.Context) (*models.User, bool) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return nil, false
+	}
+	return user, true
+}
+
+// currentClaims retorna os claims do access token validado pelo middleware de autenticação
+func currentClaims(c *gin.Context) (*services.Claims, bool) {
+	value, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*services.Claims)
+	return claims, ok
+}
+
+// sessionMeta extrai os dados do dispositivo que originou a requisição
+func sessionMeta(c *gin.Context) services.SessionMeta {
+	return services.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ClientActivityRequest representa os dados de registro/edição de uma atividade do cliente
+type ClientActivityRequest struct {
+	Type       string     `json:"type" binding:"required,oneof=note call meeting email" example:"call"`
+	Body       string     `json:"body" binding:"max=10000" example:"Alinhamento do escopo da fase 2"`
+	OccurredAt *time.Time `json:"occurred_at" example:"2024-03-18T14:30:00Z"` // padrão: agora
+	TaskID     *uint      `json:"task_id"`
+	PaymentID  *uint      `json:"payment_id"`
+}
+
+// TimelineQuery representa os parâmetros de paginação da linha do tempo
+type TimelineQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// ClientActivityHandler gerencia as requisições das atividades e da linha do tempo dos clientes
+type ClientActivityHandler struct {
+	activityService services.ClientActivityService
+	logger          logger.Logger
+}
+
+// NewClientActivityHandler cria uma nova instância de ClientActivityHandler
+func NewClientActivityHandler(activityService services.ClientActivityService, logger logger.Logger) *ClientActivityHandler {
+	return &ClientActivityHandler{
+		activityService: activityService,
+		logger:          logger,
+	}
+}
+
+// Timeline godoc
+// @Summary      Linha do tempo do cliente
+// @Description  Lista, do mais recente para o mais antigo, as atividades registradas e os eventos das tarefas (criadas e concluídas) e dos pagamentos (recebidos e vencidos) do cliente. Use o next_cursor retornado para buscar a próxima página
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id      path   int     true   "ID do cliente"
+// @Param        cursor  query  string  false  "Cursor da próxima página"
+// @Param        limit   query  int     false  "Itens por página (padrão 20, máximo 100)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID ou cursor inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/timeline [get]
+func (h *ClientActivityHandler) Timeline(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query TimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	timeline, err := h.activityService.Timeline(user.ID, clientID, query.Cursor, query.Limit)
+	if err != nil {
+		h.respondError(c, err, "Erro ao montar linha do tempo")
+		return
+	}
+
+	var nextCursor interface{}
+	if timeline.NextCursor != "" {
+		nextCursor = timeline.NextCursor
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": timeline.Entries,
+		"meta": gin.H{
+			"next_cursor": nextCursor,
+		},
+	})
+}
+
+// Create godoc
+// @Summary      Registrar atividade do cliente
+// @Description  Registra uma anotação, ligação, reunião ou e-mail no histórico do cliente, opcionalmente vinculada a uma tarefa ou a um pagamento do cliente
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                    true  "ID do cliente"
+// @Param        request  body  ClientActivityRequest  true  "Dados da atividade"
+// @Success      201  {object}  models.ClientActivity
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/activities [post]
+func (h *ClientActivityHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ClientActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	activity, err := h.activityService.Create(user.ID, clientID, req.input())
+	if err != nil {
+		h.respondError(c, err, "Erro ao registrar atividade")
+		return
+	}
+
+	c.JSON(http.StatusCreated, activity)
+}
+
+// Update godoc
+// @Summary      Atualizar atividade do cliente
+// @Description  Atualiza uma atividade registrada. Mudanças de status e mesclagens são registradas pelo sistema e não podem ser alteradas
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id          path  int                    true  "ID do cliente"
+// @Param        activityId  path  int                    true  "ID da atividade"
+// @Param        request     body  ClientActivityRequest  true  "Dados da atividade"
+// @Success      200  {object}  models.ClientActivity
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente ou atividade não encontrada"
+// @Failure      409  {object}  map[string]interface{} "Atividade registrada pelo sistema"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/activities/{activityId} [put]
+func (h *ClientActivityHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+	activityID, ok := parsePathID(c, "activityId")
+	if !ok {
+		return
+	}
+
+	var req ClientActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	activity, err := h.activityService.Update(user.ID, clientID, activityID, req.input())
+	if err != nil {
+		h.respondError(c, err, "Erro ao atualizar atividade")
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// Delete godoc
+// @Summary      Remover atividade do cliente
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id          path  int  true  "ID do cliente"
+// @Param        activityId  path  int  true  "ID da atividade"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente ou atividade não encontrada"
+// @Failure      409  {object}  map[string]interface{} "Atividade registrada pelo sistema"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/activities/{activityId} [delete]
+func (h *ClientActivityHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+	activityID, ok := parsePathID(c, "activityId")
+	if !ok {
+		return
+	}
+
+	if err := h.activityService.Delete(user.ID, clientID, activityID); err != nil {
+		h.respondError(c, err, "Erro ao remover atividade")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Atividade removida com sucesso"})
+}
+
+// respondError converte os erros do serviço de atividades na resposta HTTP
+func (h *ClientActivityHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrClientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+	case services.ErrActivityNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Atividade não encontrada"})
+	case services.ErrActivityInvalidLink:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A tarefa ou o pagamento informado não pertence ao cliente"})
+	case services.ErrActivityReadOnly:
+		c.JSON(http.StatusConflict, gin.H{"error": "Atividades registradas pelo sistema não podem ser alteradas"})
+	case services.ErrInvalidCursor:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cursor inválido"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// input converte a requisição nos dados do serviço
+func (req *ClientActivityRequest) input() services.ActivityInput {
+	input := services.ActivityInput{
+		Type:      models.ActivityType(req.Type),
+		Body:      req.Body,
+		TaskID:    req.TaskID,
+		PaymentID: req.PaymentID,
+	}
+	if req.OccurredAt != nil {
+		input.OccurredAt = *req.OccurredAt
+	}
+	return input
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ClientContactRequest representa os dados de criação/atualização de um contato do cliente
+type ClientContactRequest struct {
+	Name      string `json:"name" binding:"required,min=2,max=100" example:"Maria Souza"`
+	Role      string `json:"role" binding:"max=100" example:"Financeiro"`
+	Email     string `json:"email" binding:"omitempty,email,max=100" example:"financeiro@agencia.com"`
+	Phone     string `json:"phone" binding:"max=20" example:"+55 11 99999-0000"`
+	IsPrimary bool   `json:"is_primary"`
+	IsBilling bool   `json:"is_billing"` // recebe as cobranças e os lembretes de pagamento
+}
+
+// ClientContactHandler gerencia as requisições dos contatos dos clientes
+type ClientContactHandler struct {
+	contactService services.ClientContactService
+	logger         logger.Logger
+}
+
+// NewClientContactHandler cria uma nova instância de ClientContactHandler
+func NewClientContactHandler(contactService services.ClientContactService, logger logger.Logger) *ClientContactHandler {
+	return &ClientContactHandler{
+		contactService: contactService,
+		logger:         logger,
+	}
+}
+
+// List godoc
+// @Summary      Listar contatos do cliente
+// @Description  Lista os contatos do cliente, com o principal e o de cobrança primeiro
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID do cliente"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/contacts [get]
+func (h *ClientContactHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	contacts, err := h.contactService.List(user.ID, clientID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao listar contatos")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": contacts})
+}
+
+// Create godoc
+// @Summary      Adicionar contato ao cliente
+// @Description  Adiciona um contato ao cliente. Marcar o contato como principal ou de cobrança retira a marcação do contato que a tinha
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                   true  "ID do cliente"
+// @Param        request  body  ClientContactRequest  true  "Dados do contato"
+// @Success      201  {object}  models.ClientContact
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Marcação de principal ou de cobrança em conflito"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/contacts [post]
+func (h *ClientContactHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ClientContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	contact, err := h.contactService.Create(user.ID, clientID, req.input())
+	if err != nil {
+		h.respondError(c, err, "Erro ao adicionar contato")
+		return
+	}
+
+	c.JSON(http.StatusCreated, contact)
+}
+
+// Update godoc
+// @Summary      Atualizar contato do cliente
+// @Description  Atualiza os dados e as marcações de principal e de cobrança do contato
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id         path  int                   true  "ID do cliente"
+// @Param        contactId  path  int                   true  "ID do contato"
+// @Param        request    body  ClientContactRequest  true  "Dados do contato"
+// @Success      200  {object}  models.ClientContact
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente ou contato não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Marcação de principal ou de cobrança em conflito"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/contacts/{contactId} [put]
+func (h *ClientContactHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+	contactID, ok := parsePathID(c, "contactId")
+	if !ok {
+		return
+	}
+
+	var req ClientContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	contact, err := h.contactService.Update(user.ID, clientID, contactID, req.input())
+	if err != nil {
+		h.respondError(c, err, "Erro ao atualizar contato")
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// Delete godoc
+// @Summary      Remover contato do cliente
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id         path  int  true  "ID do cliente"
+// @Param        contactId  path  int  true  "ID do contato"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente ou contato não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/contacts/{contactId} [delete]
+func (h *ClientContactHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	clientID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+	contactID, ok := parsePathID(c, "contactId")
+	if !ok {
+		return
+	}
+
+	if err := h.contactService.Delete(user.ID, clientID, contactID); err != nil {
+		h.respondError(c, err, "Erro ao remover contato")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contato removido com sucesso"})
+}
+
+// respondError converte os erros do serviço de contatos na resposta HTTP
+func (h *ClientContactHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrClientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+	case services.ErrContactNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contato não encontrado"})
+	case services.ErrContactFlagConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": "Outro contato do cliente acabou de ser marcado como principal ou de cobrança; tente novamente"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// input converte a requisição nos dados do serviço
+func (req *ClientContactRequest) input() services.ContactInput {
+	return services.ContactInput{
+		Name:      req.Name,
+		Role:      req.Role,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		IsPrimary: req.IsPrimary,
+		IsBilling: req.IsBilling,
+	}
+}
+
+// parsePathID lê um ID numérico do caminho, respondendo 400 se for inválido
+func parsePathID(c *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return 0, false
+	}
+	return uint(id), true
+}
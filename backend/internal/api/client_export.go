@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ClientExportQuery representa o formato e os filtros da exportação, os mesmos da listagem (a paginação é ignorada)
+type ClientExportQuery struct {
+	ClientListQuery
+	Format string `form:"format" binding:"required,oneof=vcf csv"`
+}
+
+// exportContentTypes relaciona cada formato ao tipo de conteúdo da resposta
+var exportContentTypes = map[string]string{
+	services.ExportFormatVCard: "text/vcard; charset=utf-8",
+	services.ExportFormatCSV:   "text/csv; charset=utf-8",
+}
+
+// ClientExportHandler gerencia a exportação de clientes
+type ClientExportHandler struct {
+	exportService services.ClientExportService
+	logger        logger.Logger
+}
+
+// NewClientExportHandler cria uma nova instância de ClientExportHandler
+func NewClientExportHandler(exportService services.ClientExportService, logger logger.Logger) *ClientExportHandler {
+	return &ClientExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// Export godoc
+// @Summary      Exportar clientes
+// @Description  Exporta os clientes como vCard 3.0 (para agendas de celular) ou CSV (para planilhas), com os mesmos filtros e a mesma ordenação da listagem. O arquivo é enviado à medida que é gerado
+// @Tags         clients
+// @Produce      text/vcard
+// @Produce      text/csv
+// @Security     Bearer
+// @Param        format   query  string  true   "vcf ou csv"
+// @Param        q        query  string  false  "Busca textual"
+// @Param        status   query  string  false  "Status do cliente"
+// @Param        company  query  string  false  "Empresa (busca parcial)"
+// @Param        tags     query  string  false  "IDs das tags separados por vírgula"
+// @Param        sort     query  string  false  "Ordenação"
+// @Param        order    query  string  false  "asc ou desc"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]interface{} "Parâmetros inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Router       /clients/export [get]
+func (h *ClientExportHandler) Export(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var query ClientExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("clientes-%s.%s", time.Now().Format("2006-01-02"), query.Format)
+	c.Header("Content-Type", exportContentTypes[query.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// Depois que os primeiros bytes são enviados não há como trocar o status; o erro fica no log
+	// e o arquivo chega incompleto ao cliente
+	if err := h.exportService.Export(user.ID, filter, query.Format, c.Writer); err != nil {
+		h.logger.Error("Erro ao exportar clientes: " + err.Error())
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Writer.Header().Del("Content-Type")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao exportar clientes"})
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+const (
+	// maxImportFileSize limita o tamanho do arquivo de importação (5 MB)
+	maxImportFileSize = 5 << 20
+	// maxImportFormOverhead é a folga para os demais campos e os cabeçalhos do formulário multipart
+	maxImportFormOverhead = 64 << 10
+)
+
+// ClientImportRequest representa os campos do formulário (multipart) de importação de clientes
+type ClientImportRequest struct {
+	Format  string `form:"format" binding:"omitempty,oneof=csv vcard"` // padrão: pela extensão do arquivo
+	Mapping string `form:"mapping"`                                    // JSON {"campo": "cabeçalho da coluna"}
+	DryRun  bool   `form:"dry_run"`
+}
+
+// ClientImportHandler gerencia a importação de clientes
+type ClientImportHandler struct {
+	importService services.ClientImportService
+	logger        logger.Logger
+}
+
+// NewClientImportHandler cria uma nova instância de ClientImportHandler
+func NewClientImportHandler(importService services.ClientImportService, logger logger.Logger) *ClientImportHandler {
+	return &ClientImportHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// Import godoc
+// @Summary      Importar clientes
+// @Description  Importa clientes de um arquivo CSV ou vCard (3.0 e 4.0). No CSV, mapping relaciona os campos name, email, phone, company, address e notes aos cabeçalhos das colunas; sem mapping, é usado o sugerido a partir dos cabeçalhos. Linhas inválidas e duplicadas (mesmo e-mail ou empresa de um cliente existente ou de uma linha anterior) são ignoradas e informadas em rows. Com dry_run=true, retorna a prévia sem criar nada; do contrário, cria os clientes válidos em uma única transação
+// @Tags         clients
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     Bearer
+// @Param        file     formData  file    true   "Arquivo .csv ou .vcf (até 5 MB e 1000 clientes)"
+// @Param        format   formData  string  false  "csv ou vcard (padrão: pela extensão)"
+// @Param        mapping  formData  string  false  "Mapeamento de colunas em JSON, ex.: {\"name\":\"Nome\",\"email\":\"E-mail\"}"
+// @Param        dry_run  formData  bool    false  "Apenas simular a importação"
+// @Success      200  {object}  services.ImportResult "Prévia da importação"
+// @Success      201  {object}  services.ImportResult "Clientes importados"
+// @Failure      400  {object}  map[string]interface{} "Arquivo ou mapeamento inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      403  {object}  map[string]interface{} "Limite de clientes do plano excedido"
+// @Failure      413  {object}  map[string]interface{} "Arquivo muito grande"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/import [post]
+func (h *ClientImportHandler) Import(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	// Limita a leitura do corpo antes do parse do formulário, que do contrário gravaria em disco um upload de qualquer tamanho
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+maxImportFormOverhead)
+
+	var req ClientImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		if isRequestTooLarge(err) {
+			respondImportTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if isRequestTooLarge(err) {
+			respondImportTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo no campo file"})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		respondImportTooLarge(c)
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = importFormatFromFilename(fileHeader.Filename)
+	}
+
+	var mapping map[string]string
+	if req.Mapping != "" {
+		if err := json.Unmarshal([]byte(req.Mapping), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mapeamento inválido", "details": "mapping deve ser um objeto JSON de campo para cabeçalho"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.logger.Error("Erro ao abrir arquivo de importação: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao importar clientes"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.Import(user.ID, services.ImportInput{
+		Format:  format,
+		File:    file,
+		Mapping: mapping,
+		DryRun:  req.DryRun,
+	})
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+// respondError converte os erros do serviço de importação na resposta HTTP
+func (h *ClientImportHandler) respondError(c *gin.Context, err error) {
+	var fileErr *services.ImportFileError
+	switch {
+	case errors.As(err, &fileErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo de importação inválido", "details": fileErr.Message})
+	case err == services.ErrImportEmpty, err == services.ErrImportTooManyRows, err == services.ErrImportUnknownFormat:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err == services.ErrClientLimitExceeded:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("Erro ao importar clientes: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao importar clientes"})
+	}
+}
+
+// isRequestTooLarge indica se a leitura do corpo parou no limite do http.MaxBytesReader
+func isRequestTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// respondImportTooLarge responde 413 para um arquivo acima do limite
+func respondImportTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "O arquivo deve ter no máximo 5 MB"})
+}
+
+// importFormatFromFilename deduz o formato pela extensão do arquivo; na dúvida, CSV
+func importFormatFromFilename(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".vcf", ".vcard":
+		return services.ImportFormatVCard
+	default:
+		return services.ImportFormatCSV
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// DuplicatesQuery representa os parâmetros da sugestão de clientes duplicados
+type DuplicatesQuery struct {
+	Limit int `form:"limit"`
+}
+
+// ClientMergeRequest representa o cliente a ser mesclado ao cliente da rota
+type ClientMergeRequest struct {
+	SourceID uint `json:"source_id" binding:"required" example:"42"`
+}
+
+// ClientMergeHandler gerencia a detecção e a mesclagem de clientes duplicados
+type ClientMergeHandler struct {
+	mergeService services.ClientMergeService
+	logger       logger.Logger
+}
+
+// NewClientMergeHandler cria uma nova instância de ClientMergeHandler
+func NewClientMergeHandler(mergeService services.ClientMergeService, logger logger.Logger) *ClientMergeHandler {
+	return &ClientMergeHandler{
+		mergeService: mergeService,
+		logger:       logger,
+	}
+}
+
+// Duplicates godoc
+// @Summary      Sugerir clientes duplicados
+// @Description  Lista os pares de clientes que provavelmente são o mesmo, comparando o nome normalizado (sem acentos, pontuação e terminações como Ltda. e S/A), a empresa, o e-mail, o domínio corporativo do e-mail e o telefone. Em cada par, client é o mais antigo e o destino sugerido para a mesclagem
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        limit  query  int  false  "Máximo de sugestões (padrão 20, máximo 100)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/duplicates [get]
+func (h *ClientMergeHandler) Duplicates(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var query DuplicatesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	suggestions, err := h.mergeService.Duplicates(user.ID, query.Limit)
+	if err != nil {
+		h.respondError(c, err, "Erro ao buscar clientes duplicados")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": suggestions})
+}
+
+// Merge godoc
+// @Summary      Mesclar clientes
+// @Description  Mescla o cliente source_id ao cliente da rota: as tarefas, os pagamentos, os contatos, as tags e o histórico passam para o cliente da rota, que fica com os dados mais completos dos dois. A mesclagem é registrada no histórico e o cliente de origem é removido
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                 true  "ID do cliente de destino"
+// @Param        request  body  ClientMergeRequest  true  "Cliente de origem"
+// @Success      200  {object}  services.ClientMergeResult
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/merge [post]
+func (h *ClientMergeHandler) Merge(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	targetID, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ClientMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.mergeService.Merge(user.ID, targetID, req.SourceID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao mesclar clientes")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondError converte os erros do serviço de mesclagem na resposta HTTP
+func (h *ClientMergeHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrClientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+	case services.ErrMergeSourceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente de origem não encontrado"})
+	case services.ErrMergeSameClient:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Um cliente não pode ser mesclado com ele mesmo"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
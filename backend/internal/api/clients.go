@@ -1,11 +1,15 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
 	"github.com/jpcode092/crm-freela/internal/services"
 	"github.com/jpcode092/crm-freela/pkg/logger"
 )
@@ -16,7 +20,76 @@ type ClientRequest struct {
 	Email   string `json:"email" binding:"required,email"`
 	Phone   string `json:"phone" binding:"required"`
 	Address string `json:"address" binding:"required"`
+	Company string `json:"company" binding:"max=100"`
+	Notes   string `json:"notes"`
 	Status  string `json:"status" binding:"omitempty,oneof=active inactive blocked"`
+	TagIDs  []uint `json:"tag_ids"` // omitido na edição, mantém as tags atuais
+	// Valores dos campos personalizados, indexados pela chave; omitido na edição, mantém os valores atuais
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+// input converte a requisição nos dados do serviço, com o status ativo por padrão
+func (req *ClientRequest) input() services.ClientInput {
+	status := models.ClientActive
+	if req.Status != "" {
+		status = models.ClientStatus(req.Status)
+	}
+
+	return services.ClientInput{
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Company:      req.Company,
+		Address:      req.Address,
+		Notes:        req.Notes,
+		Status:       status,
+		TagIDs:       req.TagIDs,
+		CustomFields: req.CustomFields,
+	}
+}
+
+// ClientListQuery representa os parâmetros de busca, filtro e ordenação da listagem de clientes
+type ClientListQuery struct {
+	Query    string `form:"q" binding:"max=200"`
+	Status   string `form:"status" binding:"omitempty,oneof=active inactive archived"`
+	Company  string `form:"company" binding:"max=100"`
+	Tags     string `form:"tags"` // IDs das tags separados por vírgula; o cliente precisa ter todas
+	Sort     string `form:"sort" binding:"omitempty,oneof=relevance name company created_at updated_at"`
+	Order    string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	// Sem status, os clientes arquivados só são listados com include_archived=true
+	IncludeArchived bool `form:"include_archived"`
+}
+
+// ArchiveClientRequest representa a escolha do que fazer com as tarefas em aberto ao arquivar o cliente
+type ArchiveClientRequest struct {
+	// cancel cancela as tarefas em aberto; block (padrão) recusa o arquivamento se houver alguma
+	OpenTasks string `json:"open_tasks" binding:"omitempty,oneof=cancel block" example:"cancel"`
+}
+
+// TrashQuery representa os parâmetros de paginação da lixeira
+type TrashQuery struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// filter converte os parâmetros nos filtros da busca de clientes
+func (query *ClientListQuery) filter() (repository.ClientFilter, error) {
+	tagIDs, err := parseIDList(query.Tags)
+	if err != nil {
+		return repository.ClientFilter{}, errors.New("tags deve conter IDs separados por vírgula")
+	}
+
+	return repository.ClientFilter{
+		TagIDs:          tagIDs,
+		Query:           query.Query,
+		Status:          models.ClientStatus(query.Status),
+		Company:         query.Company,
+		Sort:            query.Sort,
+		Order:           query.Order,
+		IncludeArchived: query.IncludeArchived,
+	}, nil
 }
 
 // ClientHandler gerencia as requisições relacionadas a clientes
@@ -41,32 +114,21 @@ func (h *ClientHandler) Create(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	// Define o status como ativo por padrão
-	status := models.ClientActive
-	if req.Status != "" {
-		status = models.ClientStatus(req.Status)
-	}
-
-	client, err := h.clientService.Create(
-		userID.(uint),
-		req.Name,
-		req.Email,
-		req.Phone,
-		req.Address,
-		status,
-	)
+	client, err := h.clientService.Create(user.ID, req.input())
 
 	if err != nil {
 		if err == services.ErrClientLimitExceeded {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if respondClientInputError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
 		return
 	}
@@ -82,13 +144,12 @@ func (h *ClientHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	client, err := h.clientService.GetByID(uint(id), userID.(uint))
+	client, err := h.clientService.GetByID(uint(id), user.ID)
 	if err != nil {
 		if err == services.ErrClientNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
@@ -101,19 +162,49 @@ func (h *ClientHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, client)
 }
 
-// List processa a requisição de listagem de clientes
+// List godoc
+// @Summary      Listar clientes
+// @Description  Lista os clientes do usuário com busca textual em nome, e-mail, empresa e observações, sem distinção de acentos. Os arquivados ficam de fora, salvo com status=archived ou include_archived=true. Com q, cada cliente traz a relevância (rank) e os trechos encontrados (highlights, em HTML com <mark>)
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        q          query  string  false  "Busca textual (prefixos de palavras)"
+// @Param        status     query  string  false  "Status (active, inactive, archived)"
+// @Param        include_archived  query  bool  false  "Incluir os arquivados quando status não é informado"
+// @Param        company    query  string  false  "Busca parcial pela empresa"
+// @Param        tags       query  string  false  "IDs de tags separados por vírgula (clientes com todas)"
+// @Param        sort       query  string  false  "Ordenação (relevance, name, company, created_at, updated_at)"
+// @Param        order      query  string  false  "Direção (asc, desc)"
+// @Param        page       query  int     false  "Página"
+// @Param        page_size  query  int     false  "Itens por página"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Parâmetros inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients [get]
 func (h *ClientHandler) List(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	query := ClientListQuery{Page: 1, PageSize: 10}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	filter, err := query.filter()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
 
-	clients, total, err := h.clientService.GetByUserID(userID.(uint), page, pageSize)
+	// A meta informa a página e o tamanho efetivamente usados na busca
+	query.Page, query.PageSize = services.NormalizeClientPage(query.Page, query.PageSize)
+	clients, total, err := h.clientService.Search(user.ID, filter, query.Page, query.PageSize)
 	if err != nil {
+		h.logger.Error("Erro ao listar clientes: " + err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar clientes"})
 		return
 	}
@@ -122,8 +213,8 @@ func (h *ClientHandler) List(c *gin.Context) {
 		"data": clients,
 		"meta": gin.H{
 			"total":     total,
-			"page":      page,
-			"page_size": pageSize,
+			"page":      query.Page,
+			"page_size": query.PageSize,
 		},
 	})
 }
@@ -142,33 +233,21 @@ func (h *ClientHandler) Update(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	// Define o status como ativo por padrão
-	status := models.ClientActive
-	if req.Status != "" {
-		status = models.ClientStatus(req.Status)
-	}
-
-	client, err := h.clientService.Update(
-		uint(id),
-		userID.(uint),
-		req.Name,
-		req.Email,
-		req.Phone,
-		req.Address,
-		status,
-	)
+	client, err := h.clientService.Update(uint(id), user.ID, req.input())
 
 	if err != nil {
 		if err == services.ErrClientNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
 			return
 		}
+		if respondClientInputError(c, err) {
+			return
+		}
 		if err == services.ErrClientLimitExceeded {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
@@ -188,13 +267,12 @@ func (h *ClientHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	err = h.clientService.Delete(uint(id), userID.(uint))
+	err = h.clientService.Delete(uint(id), user.ID)
 	if err != nil {
 		if err == services.ErrClientNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
@@ -206,3 +284,243 @@ func (h *ClientHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// Archive godoc
+// @Summary      Arquivar cliente
+// @Description  Arquiva o cliente, que deixa de aparecer nas listagens. Com tarefas em aberto (todo, in_progress, review), open_tasks=cancel as cancela e open_tasks=block (padrão) recusa o arquivamento com 409. Os pagamentos não são alterados e os pendentes continuam sendo cobrados
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                   true   "ID do cliente"
+// @Param        request  body  ArchiveClientRequest  false  "O que fazer com as tarefas em aberto"
+// @Success      200  {object}  services.ClientArchiveResult
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Cliente já arquivado ou com tarefas em aberto"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/archive [post]
+func (h *ClientHandler) Archive(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	// O corpo é opcional: sem ele, vale open_tasks=block
+	var req ArchiveClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.clientService.Archive(id, user.ID, req.OpenTasks == "cancel")
+	if err != nil {
+		var openTasksErr *services.ClientOpenTasksError
+		if errors.As(err, &openTasksErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "O cliente tem tarefas em aberto; conclua-as ou arquive com open_tasks=cancel",
+				"open_tasks": openTasksErr.Count,
+			})
+			return
+		}
+		h.respondError(c, err, "Erro ao arquivar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Unarchive godoc
+// @Summary      Reativar cliente arquivado
+// @Description  Reativa um cliente arquivado, que volta às listagens. As tarefas canceladas no arquivamento continuam canceladas
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID do cliente"
+// @Success      200  {object}  models.Client
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Cliente não está arquivado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/unarchive [post]
+func (h *ClientHandler) Unarchive(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	client, err := h.clientService.Unarchive(id, user.ID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao reativar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// Trash godoc
+// @Summary      Lixeira de clientes
+// @Description  Lista os clientes removidos, do removido mais recentemente para o mais antigo, com a data de remoção
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        page       query  int  false  "Página"
+// @Param        page_size  query  int  false  "Itens por página (padrão 20, máximo 100)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/trash [get]
+func (h *ClientHandler) Trash(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	query := TrashQuery{Page: 1, PageSize: 20}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos", "details": err.Error()})
+		return
+	}
+
+	query.Page, query.PageSize = services.NormalizeClientPage(query.Page, query.PageSize)
+	clients, total, err := h.clientService.Trash(user.ID, query.Page, query.PageSize)
+	if err != nil {
+		h.respondError(c, err, "Erro ao listar a lixeira")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": clients,
+		"meta": gin.H{
+			"total":     total,
+			"page":      query.Page,
+			"page_size": query.PageSize,
+		},
+	})
+}
+
+// Restore godoc
+// @Summary      Restaurar cliente da lixeira
+// @Description  Restaura o cliente removido junto com as tarefas, os pagamentos, os contatos e o histórico removidos com ele. Como os clientes na lixeira não contam no limite do plano, a restauração é recusada com 403 se ultrapassá-lo
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID do cliente"
+// @Success      200  {object}  models.Client
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      403  {object}  map[string]interface{} "Limite de clientes do plano excedido"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado na lixeira"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/restore [post]
+func (h *ClientHandler) Restore(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	client, err := h.clientService.Restore(id, user.ID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao restaurar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+// Purge godoc
+// @Summary      Apagar cliente definitivamente
+// @Description  Apaga de vez um cliente da lixeira, com as suas tarefas, pagamentos, contatos e histórico. Não pode ser desfeito
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID do cliente"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Cliente não encontrado na lixeira"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /clients/{id}/purge [delete]
+func (h *ClientHandler) Purge(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.clientService.Purge(id, user.ID); err != nil {
+		h.respondError(c, err, "Erro ao apagar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cliente apagado definitivamente"})
+}
+
+// respondError converte os erros do arquivamento e da lixeira na resposta HTTP
+func (h *ClientHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrClientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+	case services.ErrClientNotInTrash:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado na lixeira"})
+	case services.ErrClientAlreadyArchived:
+		c.JSON(http.StatusConflict, gin.H{"error": "Cliente já está arquivado"})
+	case services.ErrClientNotArchived:
+		c.JSON(http.StatusConflict, gin.H{"error": "Cliente não está arquivado"})
+	case services.ErrClientLimitExceeded:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// respondClientInputError responde 400 quando as tags ou os campos personalizados informados são inválidos.
+// Retorna false se o erro for de outro tipo.
+func respondClientInputError(c *gin.Context, err error) bool {
+	var fieldErr *services.CustomFieldError
+	switch {
+	case errors.As(err, &fieldErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Campo personalizado inválido", "field": fieldErr.Key, "details": fieldErr.Message})
+	case err == services.ErrTagNotFound:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tag não encontrada"})
+	default:
+		return false
+	}
+	return true
+}
+
+// parseIDList lê uma lista de IDs separados por vírgula ("3,7"); a lista vazia é válida
+func parseIDList(value string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// CreateCustomFieldRequest representa os dados de criação de um campo personalizado
+type CreateCustomFieldRequest struct {
+	Key     string   `json:"key" binding:"required,min=1,max=50" example:"segmento"` // letras minúsculas, números e _
+	Label   string   `json:"label" binding:"required,min=1,max=100" example:"Segmento"`
+	Type    string   `json:"type" binding:"required,oneof=text number date select" example:"select"`
+	Options []string `json:"options" binding:"max=100,dive,max=100" example:"Varejo,Serviços"` // obrigatório para select
+}
+
+// UpdateCustomFieldRequest representa a edição de um campo personalizado; a chave e o tipo não mudam
+type UpdateCustomFieldRequest struct {
+	Label   string   `json:"label" binding:"required,min=1,max=100" example:"Segmento"`
+	Options []string `json:"options" binding:"max=100,dive,max=100"`
+}
+
+// CustomFieldHandler gerencia as requisições dos campos personalizados dos clientes
+type CustomFieldHandler struct {
+	customFieldService services.CustomFieldService
+	logger             logger.Logger
+}
+
+// NewCustomFieldHandler cria uma nova instância de CustomFieldHandler
+func NewCustomFieldHandler(customFieldService services.CustomFieldService, logger logger.Logger) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+		logger:             logger,
+	}
+}
+
+// List godoc
+// @Summary      Listar campos personalizados
+// @Description  Lista os campos personalizados dos clientes do usuário
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /custom-fields [get]
+func (h *CustomFieldHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	fields, err := h.customFieldService.List(user.ID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao listar campos personalizados")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": fields})
+}
+
+// Create godoc
+// @Summary      Criar campo personalizado
+// @Description  Define um campo para os clientes do usuário (text, number, date ou select). Os valores ficam em custom_fields do cliente, indexados pela chave
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request  body  CreateCustomFieldRequest  true  "Definição do campo"
+// @Success      201  {object}  models.CustomFieldDefinition
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      409  {object}  map[string]interface{} "Chave já usada"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /custom-fields [post]
+func (h *CustomFieldHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+	if !validFieldKey(req.Key) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": "a chave deve conter apenas letras minúsculas, números e _"})
+		return
+	}
+
+	field, err := h.customFieldService.Create(user.ID, req.Key, req.Label, models.CustomFieldType(req.Type), req.Options)
+	if err != nil {
+		h.respondError(c, err, "Erro ao criar campo personalizado")
+		return
+	}
+
+	c.JSON(http.StatusCreated, field)
+}
+
+// Update godoc
+// @Summary      Atualizar campo personalizado
+// @Description  Altera o rótulo e as opções do campo; a chave e o tipo não mudam. Remover opções que ainda são o valor de algum cliente (inclusive os da lixeira) é recusado com 409, com as opções em options e a quantidade de clientes em clients
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int                       true  "ID do campo"
+// @Param        request  body  UpdateCustomFieldRequest  true  "Rótulo e opções"
+// @Success      200  {object}  models.CustomFieldDefinition
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Campo não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Opções removidas em uso"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /custom-fields/{id} [put]
+func (h *CustomFieldHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	field, err := h.customFieldService.Update(user.ID, id, req.Label, req.Options)
+	if err != nil {
+		var inUseErr *services.CustomFieldOptionsInUseError
+		if errors.As(err, &inUseErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "As opções removidas ainda são usadas por clientes; altere esses clientes antes de removê-las",
+				"options": inUseErr.Options,
+				"clients": inUseErr.Clients,
+			})
+			return
+		}
+		h.respondError(c, err, "Erro ao atualizar campo personalizado")
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// Delete godoc
+// @Summary      Remover campo personalizado
+// @Description  Remove o campo e o seu valor de todos os clientes
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID do campo"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Campo não encontrado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /custom-fields/{id} [delete]
+func (h *CustomFieldHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.customFieldService.Delete(user.ID, id); err != nil {
+		h.respondError(c, err, "Erro ao remover campo personalizado")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Campo personalizado removido com sucesso"})
+}
+
+// respondError converte os erros do serviço de campos personalizados na resposta HTTP
+func (h *CustomFieldHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrCustomFieldNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campo personalizado não encontrado"})
+	case services.ErrCustomFieldKeyInUse:
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe um campo personalizado com esta chave"})
+	case services.ErrCustomFieldNoOptions:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe ao menos uma opção para campos do tipo select"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// validFieldKey aceita chaves com letras minúsculas sem acento, números e _, começando por letra
+func validFieldKey(key string) bool {
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case (r >= '0' && r <= '9' || r == '_') && i > 0:
+		default:
+			return false
+		}
+	}
+	return key != ""
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+)
+
+// KeysHandler publica as chaves públicas usadas para validar os tokens emitidos
+type KeysHandler struct {
+	signer services.TokenSigner
+}
+
+// NewKeysHandler cria uma nova instância de KeysHandler
+func NewKeysHandler(signer services.TokenSigner) *KeysHandler {
+	return &KeysHandler{
+		signer: signer,
+	}
+}
+
+// JWKS godoc
+// @Summary      Chaves públicas (JWKS)
+// @Description  Retorna as chaves públicas ativas (RFC 7517) para validação dos tokens pelo kid
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  jwk.Set
+// @Router       /.well-known/jwks.json [get]
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.signer.JWKS())
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// MagicLinkRequest representa os dados de requisição do link de login sem senha
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// ConsumeMagicLinkRequest representa os dados de requisição para entrar com o link recebido
+type ConsumeMagicLinkRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MagicLinkHandler gerencia as requisições de login sem senha
+type MagicLinkHandler struct {
+	magicLinkService services.MagicLinkService
+	logger           logger.Logger
+}
+
+// NewMagicLinkHandler cria uma nova instância de MagicLinkHandler
+func NewMagicLinkHandler(magicLinkService services.MagicLinkService, logger logger.Logger) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		magicLinkService: magicLinkService,
+		logger:           logger,
+	}
+}
+
+// Request godoc
+// @Summary      Solicitar link de login
+// @Description  Envia um link de login de uso único para o e-mail. Responde com sucesso mesmo se o e-mail não estiver cadastrado
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body MagicLinkRequest true "E-mail cadastrado"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Router       /auth/magic-link [post]
+func (h *MagicLinkHandler) Request(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.magicLinkService.RequestLink(req.Email, c.ClientIP()); err != nil {
+		if respondLockout(c, err) {
+			h.logger.Warn("Solicitação de link de login limitada: " + req.Email + " (IP " + c.ClientIP() + ")")
+			return
+		}
+		// Falhas (ex.: envio do e-mail) não mudam a resposta, que revelaria que o e-mail existe
+		h.logger.Error("Erro ao enviar link de login: " + err.Error())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Se o e-mail estiver cadastrado, enviaremos um link de acesso"})
+}
+
+// Consume godoc
+// @Summary      Entrar com link de login
+// @Description  Troca o token do link recebido por e-mail pelo par de tokens. Se o usuário tiver MFA ativo, retorna mfa_required e um mfa_token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ConsumeMagicLinkRequest true "Token recebido no link"
+// @Success      200  {object}  AuthResponse
+// @Failure      400  {object}  map[string]interface{} "Link inválido, expirado ou já utilizado"
+// @Failure      403  {object}  map[string]interface{} "Usuário desativado"
+// @Failure      429  {object}  map[string]interface{} "Conta bloqueada temporariamente"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/magic-link/consume [post]
+func (h *MagicLinkHandler) Consume(c *gin.Context) {
+	var req ConsumeMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.magicLinkService.Consume(req.Token, sessionMeta(c))
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de acesso inválido ou já utilizado", "code": "invalid_token"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de acesso expirado", "code": "token_expired"})
+		case errors.ErrUserDeactivated:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
+		default:
+			h.logger.Error("Erro ao entrar com link de login: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao fazer login"})
+		}
+		return
+	}
+
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Informe o código de verificação",
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+
+	h.logger.Info("Login por link realizado com sucesso: " + result.User.Email)
+	respondWithTokens(c, http.StatusOK, "Login realizado com sucesso", result)
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ConfirmMFARequest representa os dados de requisição para confirmar o cadastro do MFA
+type ConfirmMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+// DisableMFARequest representa os dados de requisição para desativar o MFA
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+// MFAHandler gerencia as requisições de cadastro da autenticação em dois fatores
+type MFAHandler struct {
+	mfaService services.MFAService
+	logger     logger.Logger
+}
+
+// NewMFAHandler cria uma nova instância de MFAHandler
+func NewMFAHandler(mfaService services.MFAService, logger logger.Logger) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+		logger:     logger,
+	}
+}
+
+// Enroll godoc
+// @Summary      Iniciar cadastro do MFA
+// @Description  Gera um segredo TOTP e a URI otpauth:// para cadastro em um aplicativo autenticador
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  services.MFAEnrollment
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      409  {object}  map[string]interface{} "MFA já ativado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(user.ID)
+	if err != nil {
+		if err == errors.ErrMFAAlreadyEnabled {
+			c.JSON(http.StatusConflict, gin.H{"error": "Autenticação em dois fatores já está ativada"})
+			return
+		}
+		h.logger.Error("Erro ao iniciar cadastro do MFA: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao iniciar cadastro do MFA"})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// Confirm godoc
+// @Summary      Confirmar cadastro do MFA
+// @Description  Ativa o MFA após validar o primeiro código e retorna os códigos de recuperação (exibidos uma única vez)
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body ConfirmMFARequest true "Código do aplicativo autenticador"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Código inválido"
+// @Failure      409  {object}  map[string]interface{} "MFA já ativado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/mfa/confirm [post]
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req ConfirmMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.mfaService.ConfirmEnrollment(user.ID, req.Code)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidMFACode:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+		case errors.ErrMFAAlreadyEnabled:
+			c.JSON(http.StatusConflict, gin.H{"error": "Autenticação em dois fatores já está ativada"})
+		case errors.ErrMFANotEnrolled:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Inicie o cadastro do MFA antes de confirmá-lo"})
+		default:
+			h.logger.Error("Erro ao confirmar cadastro do MFA: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao confirmar cadastro do MFA"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Autenticação em dois fatores ativada com sucesso",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// Disable godoc
+// @Summary      Desativar MFA
+// @Description  Desativa a autenticação em dois fatores; exige a senha atual e um código válido
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body DisableMFARequest true "Senha e código"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Senha ou código inválido"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	err := h.mfaService.Disable(user.ID, req.Password, req.Code)
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidPassword:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha inválida"})
+		case errors.ErrInvalidMFACode:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido"})
+		case errors.ErrMFANotEnabled:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Autenticação em dois fatores não está ativada"})
+		default:
+			h.logger.Error("Erro ao desativar MFA: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar MFA"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Autenticação em dois fatores desativada"})
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+const (
+	// oidcBindingCookie guarda o vínculo entre o login OIDC em andamento e o navegador que o iniciou
+	oidcBindingCookie = "oidc_binding"
+	// oidcCookiePath restringe o cookie às rotas do login OIDC
+	oidcCookiePath = "/api/auth/oidc"
+)
+
+// OIDCCallbackRequest representa os dados devolvidos pelo provedor de identidade na URL de retorno
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// OIDCHandler gerencia as requisições de login via provedor OpenID Connect
+type OIDCHandler struct {
+	oidcService services.OIDCService
+	logger      logger.Logger
+}
+
+// NewOIDCHandler cria uma nova instância de OIDCHandler
+func NewOIDCHandler(oidcService services.OIDCService, logger logger.Logger) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		logger:      logger,
+	}
+}
+
+// Authorize godoc
+// @Summary      Iniciar login via provedor de identidade
+// @Description  Retorna a URL de autorização do provedor OpenID Connect (authorization code + PKCE) e o state do login, e grava o cookie HttpOnly oidc_binding, que vincula o login a este navegador
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  services.OIDCAuthorization
+// @Failure      404  {object}  map[string]interface{} "Provedor não configurado"
+// @Failure      502  {object}  map[string]interface{} "Provedor indisponível"
+// @Router       /auth/oidc/authorize [get]
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authorization, err := h.oidcService.Begin(c.Request.Context())
+	if err != nil {
+		if err == errors.ErrOIDCDisabled {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Login via provedor de identidade não configurado"})
+			return
+		}
+		h.logger.Error("Erro ao iniciar login OIDC: " + err.Error())
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Provedor de identidade indisponível"})
+		return
+	}
+
+	maxAge := int(time.Until(authorization.ExpiresAt).Seconds())
+	setOIDCBindingCookie(c, authorization.Binding, maxAge)
+	c.JSON(http.StatusOK, authorization)
+}
+
+// Callback godoc
+// @Summary      Concluir login via provedor de identidade
+// @Description  Troca o código de autorização devolvido pelo provedor pelo par de tokens da aplicação. Exige o cookie oidc_binding gravado pelo authorize no mesmo navegador. Se o usuário tiver MFA ativo, retorna mfa_required e um mfa_token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body OIDCCallbackRequest true "Código e state devolvidos pelo provedor"
+// @Success      200  {object}  AuthResponse
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Login inválido ou expirado"
+// @Failure      403  {object}  map[string]interface{} "E-mail não verificado pelo provedor ou usuário desativado"
+// @Failure      404  {object}  map[string]interface{} "Provedor não configurado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/oidc/callback [post]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	// O cookie vale para uma única tentativa; sem ele, o Complete recusa o state
+	binding, _ := c.Cookie(oidcBindingCookie)
+	setOIDCBindingCookie(c, "", -1)
+
+	result, err := h.oidcService.Complete(c.Request.Context(), req.State, binding, req.Code, sessionMeta(c))
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrOIDCDisabled:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Login via provedor de identidade não configurado"})
+		case errors.ErrInvalidToken:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Login inválido ou expirado. Tente novamente"})
+		case errors.ErrOIDCEmailNotVerified:
+			c.JSON(http.StatusForbidden, gin.H{"error": "O provedor de identidade não confirmou o e-mail da conta"})
+		case errors.ErrUserDeactivated:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
+		default:
+			h.logger.Error("Erro ao concluir login OIDC: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao fazer login"})
+		}
+		return
+	}
+
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Informe o código de verificação",
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+
+	h.logger.Info("Login via provedor de identidade realizado com sucesso: " + result.User.Email)
+	respondWithTokens(c, http.StatusOK, "Login realizado com sucesso", result)
+}
+
+// setOIDCBindingCookie grava (ou, com maxAge negativo, apaga) o cookie de vínculo do login OIDC.
+// SameSite=Lax impede que outro site o envie; em modo release, o cookie só trafega por HTTPS.
+func setOIDCBindingCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, value, maxAge, oidcCookiePath, "", gin.Mode() == gin.ReleaseMode, true)
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/webauthn"
+)
+
+// FinishPasskeyRegistrationRequest representa a resposta de navigator.credentials.create() e o nome da passkey
+type FinishPasskeyRegistrationRequest struct {
+	Name       string                       `json:"name" binding:"max=100" example:"MacBook"`
+	Credential webauthn.AttestationResponse `json:"credential"`
+}
+
+// FinishPasskeyLoginRequest representa a resposta de navigator.credentials.get()
+type FinishPasskeyLoginRequest struct {
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+// PasskeyResponse representa uma passkey na listagem
+type PasskeyResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PasskeyHandler gerencia as requisições de cadastro de passkeys e de login com elas
+type PasskeyHandler struct {
+	webAuthnService services.WebAuthnService
+	logger          logger.Logger
+}
+
+// NewPasskeyHandler cria uma nova instância de PasskeyHandler
+func NewPasskeyHandler(webAuthnService services.WebAuthnService, logger logger.Logger) *PasskeyHandler {
+	return &PasskeyHandler{
+		webAuthnService: webAuthnService,
+		logger:          logger,
+	}
+}
+
+// BeginRegistration godoc
+// @Summary      Iniciar cadastro de passkey
+// @Description  Retorna as opções (publicKey) para navigator.credentials.create()
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/passkeys/register/begin [post]
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	options, err := h.webAuthnService.BeginRegistration(user.ID)
+	if err != nil {
+		h.logger.Error("Erro ao iniciar cadastro de passkey: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao iniciar cadastro de passkey"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"publicKey": options})
+}
+
+// FinishRegistration godoc
+// @Summary      Concluir cadastro de passkey
+// @Description  Valida a resposta de navigator.credentials.create() e grava a passkey
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body FinishPasskeyRegistrationRequest true "Credencial criada pelo autenticador"
+// @Success      201  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos ou passkey rejeitada"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/passkeys/register/finish [post]
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	credential, err := h.webAuthnService.FinishRegistration(user.ID, req.Name, &req.Credential)
+	if err != nil {
+		if err == errors.ErrInvalidPasskey {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Não foi possível validar a passkey. Tente novamente", "code": "invalid_passkey"})
+			return
+		}
+		h.logger.Error("Erro ao cadastrar passkey: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cadastrar passkey"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Passkey cadastrada com sucesso",
+		"passkey": passkeyResponse(credential),
+	})
+}
+
+// List godoc
+// @Summary      Listar passkeys
+// @Description  Lista as passkeys cadastradas pelo usuário
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/passkeys [get]
+func (h *PasskeyHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	credentials, err := h.webAuthnService.ListCredentials(user.ID)
+	if err != nil {
+		h.logger.Error("Erro ao listar passkeys: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar passkeys"})
+		return
+	}
+
+	data := make([]PasskeyResponse, 0, len(credentials))
+	for i := range credentials {
+		data = append(data, passkeyResponse(&credentials[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Delete godoc
+// @Summary      Remover passkey
+// @Description  Remove uma passkey do usuário; ela deixa de ser aceita no login
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Param        id   path  int  true  "ID da passkey"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Passkey não encontrada"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/passkeys/{id} [delete]
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := h.webAuthnService.DeleteCredential(user.ID, uint(id)); err != nil {
+		if err == errors.ErrPasskeyNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Passkey não encontrada"})
+			return
+		}
+		h.logger.Error("Erro ao remover passkey: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover passkey"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Passkey removida com sucesso"})
+}
+
+// BeginLogin godoc
+// @Summary      Iniciar login com passkey
+// @Description  Retorna as opções (publicKey) para navigator.credentials.get(). O usuário é identificado pela passkey escolhida
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/passkey/begin [post]
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	options, err := h.webAuthnService.BeginLogin(c.ClientIP())
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		h.logger.Error("Erro ao iniciar login com passkey: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao iniciar login com passkey"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"publicKey": options})
+}
+
+// FinishLogin godoc
+// @Summary      Entrar com passkey
+// @Description  Valida a resposta de navigator.credentials.get() e retorna o par de tokens. Se o usuário tiver MFA ativo, retorna mfa_required e um mfa_token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body FinishPasskeyLoginRequest true "Assinatura feita pela passkey"
+// @Success      200  {object}  AuthResponse
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Passkey não reconhecida"
+// @Failure      403  {object}  map[string]interface{} "Usuário desativado"
+// @Failure      429  {object}  map[string]interface{} "Conta bloqueada temporariamente"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/passkey/finish [post]
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	var req FinishPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.webAuthnService.FinishLogin(&req.Credential, sessionMeta(c))
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidCredentials:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Passkey não reconhecida", "code": "invalid_passkey"})
+		case errors.ErrUserDeactivated:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
+		default:
+			h.logger.Error("Erro ao entrar com passkey: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao fazer login"})
+		}
+		return
+	}
+
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Informe o código de verificação",
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+		})
+		return
+	}
+
+	h.logger.Info("Login com passkey realizado com sucesso: " + result.User.Email)
+	respondWithTokens(c, http.StatusOK, "Login realizado com sucesso", result)
+}
+
+// passkeyResponse converte a passkey para a resposta da API, sem a chave pública
+func passkeyResponse(credential *models.WebAuthnCredential) PasskeyResponse {
+	return PasskeyResponse{
+		ID:             credential.ID,
+		Name:           credential.Name,
+		Transports:     credential.TransportList(),
+		BackupEligible: credential.BackupEligible,
+		LastUsedAt:     credential.LastUsedAt,
+		CreatedAt:      credential.CreatedAt,
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// ForgotPasswordRequest representa os dados de requisição para recuperação de senha
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// ResetPasswordRequest representa os dados de requisição para redefinição de senha
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6" example:"123456"`
+}
+
+// PasswordResetHandler gerencia as requisições de recuperação de senha
+type PasswordResetHandler struct {
+	passwordResetService services.PasswordResetService
+	logger               logger.Logger
+}
+
+// NewPasswordResetHandler cria uma nova instância de PasswordResetHandler
+func NewPasswordResetHandler(passwordResetService services.PasswordResetService, logger logger.Logger) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+		logger:               logger,
+	}
+}
+
+// ForgotPassword godoc
+// @Summary      Solicitar recuperação de senha
+// @Description  Envia o link de redefinição de senha. Responde com sucesso mesmo se o e-mail não estiver cadastrado
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ForgotPasswordRequest true "E-mail cadastrado"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Router       /auth/forgot-password [post]
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(req.Email, c.ClientIP()); err != nil {
+		if respondLockout(c, err) {
+			h.logger.Warn("Recuperação de senha limitada: " + req.Email + " (IP " + c.ClientIP() + ")")
+			return
+		}
+		// Falhas (ex.: envio do e-mail) não mudam a resposta, que revelaria que o e-mail existe
+		h.logger.Error("Erro ao solicitar recuperação de senha: " + err.Error())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Se o e-mail estiver cadastrado, enviaremos um link para redefinir a senha"})
+}
+
+// ResetPassword godoc
+// @Summary      Redefinir senha
+// @Description  Redefine a senha com o token recebido por e-mail. O token só pode ser usado uma vez e todas as sessões são encerradas
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResetPasswordRequest true "Token recebido no link e nova senha"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Link inválido ou expirado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/reset-password [post]
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(req.Token, req.Password, sessionMeta(c)); err != nil {
+		switch err {
+		case errors.ErrInvalidToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de redefinição inválido ou já utilizado", "code": "invalid_token"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de redefinição expirado", "code": "token_expired"})
+		default:
+			h.logger.Error("Erro ao redefinir senha: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao redefinir senha"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Senha redefinida com sucesso"})
+}
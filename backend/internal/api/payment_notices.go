@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// PaymentNoticeHandler gerencia o envio de cobranças e lembretes de pagamento aos clientes
+type PaymentNoticeHandler struct {
+	noticeService services.PaymentNoticeService
+	logger        logger.Logger
+}
+
+// NewPaymentNoticeHandler cria uma nova instância de PaymentNoticeHandler
+func NewPaymentNoticeHandler(noticeService services.PaymentNoticeService, logger logger.Logger) *PaymentNoticeHandler {
+	return &PaymentNoticeHandler{
+		noticeService: noticeService,
+		logger:        logger,
+	}
+}
+
+// Send godoc
+// @Summary      Enviar cobrança ou lembrete de pagamento
+// @Description  Envia por e-mail a cobrança do pagamento (pendente e dentro do prazo) ou o lembrete (vencido) ao contato de cobrança do cliente, quando ele tiver e-mail, ou ao e-mail do próprio cliente. Pagamentos pagos ou cancelados não podem ser cobrados
+// @Tags         payments
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID do pagamento"
+// @Success      200  {object}  services.PaymentNoticeResult
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Pagamento não encontrado"
+// @Failure      409  {object}  map[string]interface{} "Pagamento não pode ser cobrado ou cliente sem e-mail de cobrança"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /payments/{id}/send [post]
+func (h *PaymentNoticeHandler) Send(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.noticeService.Send(user.ID, id)
+	if err != nil {
+		h.respondError(c, err, "Erro ao enviar aviso de pagamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondError converte os erros do serviço de avisos de pagamento na resposta HTTP
+func (h *PaymentNoticeHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrPaymentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pagamento não encontrado"})
+	case services.ErrClientNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cliente não encontrado"})
+	case services.ErrPaymentNotBillable:
+		c.JSON(http.StatusConflict, gin.H{"error": "Só pagamentos pendentes ou vencidos podem ser cobrados"})
+	case services.ErrBillingRecipientMissing:
+		c.JSON(http.StatusConflict, gin.H{"error": "O cliente não tem contato de cobrança nem e-mail cadastrado"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
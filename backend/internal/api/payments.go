@@ -36,11 +36,11 @@ type CreatePaymentRequest struct {
 
 // CreatePayment handles payment creation requests
 func (h *PaymentHandler) CreatePayment(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	var req CreatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -85,11 +85,11 @@ type UpdatePaymentRequest struct {
 
 // UpdatePayment handles payment update requests
 func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -143,11 +143,11 @@ func (h *PaymentHandler) UpdatePayment(c *gin.Context) {
 
 // ListPayments handles requests to list all payments
 func (h *PaymentHandler) ListPayments(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
@@ -166,11 +166,11 @@ func (h *PaymentHandler) ListPayments(c *gin.Context) {
 
 // GetPaymentByClientID handles requests to get payments by client ID
 func (h *PaymentHandler) GetPaymentByClientID(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	clientID, err := strconv.ParseUint(c.Param("clientId"), 10, 32)
 	if err != nil {
@@ -195,11 +195,11 @@ func (h *PaymentHandler) GetPaymentByClientID(c *gin.Context) {
 
 // GetPayment handles requests to get a specific payment by ID
 func (h *PaymentHandler) GetPayment(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -218,11 +218,11 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 
 // DeletePayment handles payment deletion requests
 func (h *PaymentHandler) DeletePayment(c *gin.Context) {
-	userID := c.GetUint("user_id")
-	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// DeleteAccountRequest representa a reautenticação exigida para excluir a conta
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	MFACode  string `json:"mfa_code" example:"123456"` // obrigatório quando o MFA estiver ativo
+}
+
+// PrivacyHandler gerencia as requisições de exportação e exclusão dos dados do usuário (LGPD)
+type PrivacyHandler struct {
+	privacyService services.PrivacyService
+	logger         logger.Logger
+}
+
+// NewPrivacyHandler cria uma nova instância de PrivacyHandler
+func NewPrivacyHandler(privacyService services.PrivacyService, logger logger.Logger) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		logger:         logger,
+	}
+}
+
+// RequestExport godoc
+// @Summary      Exportar meus dados
+// @Description  Gera um ZIP com o perfil, clientes, contatos, tarefas e pagamentos (inclusive removidos) em JSON e CSV e envia o link de download por e-mail
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      202  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      429  {object}  map[string]interface{} "Limite de exportações atingido"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/export [post]
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privacyService.RequestExport(user.ID, sessionMeta(c)); err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		h.logger.Error("Erro ao exportar dados: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao exportar dados"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Enviamos o link para download dos seus dados ao seu e-mail"})
+}
+
+// DownloadExport godoc
+// @Summary      Baixar exportação de dados
+// @Description  Baixa o ZIP da exportação com o token recebido por e-mail
+// @Tags         user
+// @Produce      application/zip
+// @Param        token  query  string  true  "Token recebido no link"
+// @Success      200  {file}    binary
+// @Failure      400  {object}  map[string]interface{} "Link inválido ou expirado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /exports/download [get]
+func (h *PrivacyHandler) DownloadExport(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token não informado"})
+		return
+	}
+
+	export, err := h.privacyService.DownloadExport(token, sessionMeta(c))
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de download inválido", "code": "invalid_token"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de download expirado. Solicite uma nova exportação", "code": "token_expired"})
+		default:
+			h.logger.Error("Erro ao baixar exportação de dados: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao baixar exportação"})
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("crm-freela-dados-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", export.Archive)
+}
+
+// DeleteAccount godoc
+// @Summary      Excluir minha conta
+// @Description  Após confirmar a senha (e o código MFA, se ativo), agenda a remoção definitiva dos clientes, tarefas e pagamentos e a anonimização do perfil ao fim do prazo de carência
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body DeleteAccountRequest true "Reautenticação"
+// @Success      202  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Senha ou código incorreto"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/account [delete]
+func (h *PrivacyHandler) DeleteAccount(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	scheduledAt, err := h.privacyService.ScheduleDeletion(user.ID, req.Password, req.MFACode)
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidPassword:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha incorreta"})
+		case errors.ErrInvalidMFACode:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Código de verificação inválido", "code": "invalid_mfa_code"})
+		default:
+			h.logger.Error("Erro ao agendar exclusão da conta: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir conta"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":               "Exclusão da conta agendada. Você pode cancelá-la até a data informada",
+		"deletion_scheduled_at": scheduledAt,
+	})
+}
+
+// CancelDeletion godoc
+// @Summary      Cancelar exclusão da conta
+// @Description  Cancela a exclusão agendada, enquanto o prazo de carência não terminar
+// @Tags         user
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      409  {object}  map[string]interface{} "Exclusão não agendada"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/account/cancel-deletion [post]
+func (h *PrivacyHandler) CancelDeletion(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.privacyService.CancelDeletion(user.ID); err != nil {
+		if err == errors.ErrDeletionNotScheduled {
+			c.JSON(http.StatusConflict, gin.H{"error": "A exclusão da conta não está agendada"})
+			return
+		}
+		h.logger.Error("Erro ao cancelar exclusão da conta: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cancelar exclusão da conta"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exclusão da conta cancelada"})
+}
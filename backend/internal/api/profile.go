@@ -0,0 +1,247 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// UpdateProfileRequest representa os dados de requisição para edição do perfil; campos omitidos não são alterados
+type UpdateProfileRequest struct {
+	Name               *string `json:"name" binding:"omitempty,min=3,max=100" example:"John Doe"`
+	Language           *string `json:"language" binding:"omitempty,bcp47_language_tag,max=10" example:"pt-BR"`
+	Timezone           *string `json:"timezone" binding:"omitempty,timezone,max=64" example:"America/Sao_Paulo"`
+	EmailNotifications *bool   `json:"email_notifications" example:"true"`
+	// Com login com senha desativado, o acesso é feito pelo link enviado ao e-mail ou pelo provedor de identidade
+	PasswordLoginDisabled *bool `json:"password_login_disabled" example:"false"`
+}
+
+// ChangePasswordRequest representa os dados de requisição para troca de senha
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,nefield=CurrentPassword"`
+}
+
+// ChangeEmailRequest representa os dados de requisição para troca de e-mail
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email,max=100" example:"john@example.com"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest representa os dados de requisição para confirmação do novo e-mail
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ProfileHandler gerencia as requisições de edição do perfil do usuário autenticado
+type ProfileHandler struct {
+	profileService services.ProfileService
+	logger         logger.Logger
+}
+
+// NewProfileHandler cria uma nova instância de ProfileHandler
+func NewProfileHandler(profileService services.ProfileService, logger logger.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// UpdateProfile godoc
+// @Summary      Editar perfil
+// @Description  Altera o nome, as preferências (idioma, fuso horário e notificações) e a permissão de login com senha do usuário autenticado
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body UpdateProfileRequest true "Campos a alterar"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/profile [put]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, err := h.profileService.UpdateProfile(user.ID, services.ProfileUpdate{
+		Name:                  req.Name,
+		Language:              req.Language,
+		Timezone:              req.Timezone,
+		EmailNotifications:    req.EmailNotifications,
+		PasswordLoginDisabled: req.PasswordLoginDisabled,
+	})
+	if err != nil {
+		if err == errors.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			return
+		}
+		h.logger.Error("Erro ao atualizar perfil: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar perfil"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Perfil atualizado com sucesso",
+		"user":    profileResponse(user),
+	})
+}
+
+// ChangePassword godoc
+// @Summary      Alterar senha
+// @Description  Troca a senha do usuário autenticado após validar a senha atual. As demais sessões são encerradas
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body ChangePasswordRequest true "Senha atual e nova senha"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Senha atual incorreta"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/password [post]
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	claims, exists := currentClaims(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	err := h.profileService.ChangePassword(claims.UserID, req.CurrentPassword, req.NewPassword, claims.SessionID)
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		if err == errors.ErrInvalidPassword {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha atual incorreta"})
+			return
+		}
+		h.logger.Error("Erro ao alterar senha: " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao alterar senha"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Senha alterada com sucesso. As demais sessões foram encerradas"})
+}
+
+// ChangeEmail godoc
+// @Summary      Solicitar troca de e-mail
+// @Description  Envia um link de confirmação para o novo endereço. O e-mail da conta só é alterado após a confirmação
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request body ChangeEmailRequest true "Novo e-mail e senha atual"
+// @Success      202  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Senha incorreta"
+// @Failure      409  {object}  map[string]interface{} "E-mail já está em uso"
+// @Failure      429  {object}  map[string]interface{} "Muitas tentativas"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /user/email [post]
+func (h *ProfileHandler) ChangeEmail(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	err := h.profileService.RequestEmailChange(user.ID, req.Password, req.Email)
+	if err != nil {
+		if respondLockout(c, err) {
+			return
+		}
+		switch err {
+		case errors.ErrInvalidPassword:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Senha incorreta"})
+		case errors.ErrEmailUnchanged:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "O novo e-mail é igual ao atual"})
+		case errors.ErrEmailInUse:
+			c.JSON(http.StatusConflict, gin.H{"error": "E-mail já está em uso", "code": "email_in_use"})
+		default:
+			h.logger.Error("Erro ao solicitar troca de e-mail: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao solicitar troca de e-mail"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Enviamos um link de confirmação para o novo e-mail"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary      Confirmar troca de e-mail
+// @Description  Confirma o novo e-mail a partir do token enviado no link e substitui o e-mail da conta
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ConfirmEmailChangeRequest true "Token recebido no link"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Link inválido ou expirado"
+// @Failure      409  {object}  map[string]interface{} "E-mail já está em uso"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /auth/email-change/confirm [post]
+func (h *ProfileHandler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	user, err := h.profileService.ConfirmEmailChange(req.Token)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de confirmação inválido", "code": "invalid_token"})
+		case errors.ErrTokenExpired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Link de confirmação expirado", "code": "token_expired"})
+		case errors.ErrEmailInUse:
+			c.JSON(http.StatusConflict, gin.H{"error": "E-mail já está em uso", "code": "email_in_use"})
+		default:
+			h.logger.Error("Erro ao confirmar troca de e-mail: " + err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao confirmar troca de e-mail"})
+		}
+		return
+	}
+
+	h.logger.Info("Troca de e-mail confirmada com sucesso: " + user.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "E-mail alterado com sucesso"})
+}
+
+// profileResponse monta os dados do perfil exibidos ao próprio usuário
+func profileResponse(user *models.User) gin.H {
+	return gin.H{
+		"id":                      user.ID,
+		"name":                    user.Name,
+		"email":                   user.Email,
+		"pending_email":           user.PendingEmail,
+		"plan":                    user.Plan,
+		"preferences":             user.Preferences,
+		"password_login_disabled": user.PasswordLoginDisabled,
+		"mfa_enabled":             user.MFAEnabled,
+		"deletion_scheduled_at":   user.DeletionScheduledAt,
+	}
+}
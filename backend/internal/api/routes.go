@@ -4,77 +4,191 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jpcode092/crm-freela/configs"
 	"github.com/jpcode092/crm-freela/internal/middleware"
+	"github.com/jpcode092/crm-freela/internal/models"
 	"github.com/jpcode092/crm-freela/internal/services"
 	"github.com/jpcode092/crm-freela/pkg/logger"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // Router representa o roteador da API
 type Router struct {
-	engine      *gin.Engine
-	config      *configs.Config
-	authService services.AuthService
-	logger      logger.Logger
+	engine          *gin.Engine
+	config          *configs.Config
+	authService     services.AuthService
+	apiTokenService services.APITokenService
+	audit           services.AuditWriter
+	logger          logger.Logger
 }
 
 // NewRouter cria uma nova instância do roteador
-func NewRouter(config *configs.Config, authService services.AuthService, logger logger.Logger) *Router {
+func NewRouter(config *configs.Config, authService services.AuthService, apiTokenService services.APITokenService, audit services.AuditWriter, logger logger.Logger) *Router {
 	return &Router{
-		engine:      gin.Default(),
-		config:      config,
-		authService: authService,
-		logger:      logger,
+		engine:          gin.Default(),
+		config:          config,
+		authService:     authService,
+		apiTokenService: apiTokenService,
+		audit:           audit,
+		logger:          logger,
 	}
 }
 
 // SetupRoutes configura as rotas da API
 func (r *Router) SetupRoutes(
+	keysHandler *KeysHandler,
 	authHandler *AuthHandler,
+	profileHandler *ProfileHandler,
+	passwordResetHandler *PasswordResetHandler,
+	magicLinkHandler *MagicLinkHandler,
+	passkeyHandler *PasskeyHandler,
+	privacyHandler *PrivacyHandler,
+	auditHandler *AuditHandler,
+	mfaHandler *MFAHandler,
+	oidcHandler *OIDCHandler,
+	apiTokenHandler *APITokenHandler,
 	clientHandler *ClientHandler,
+	clientContactHandler *ClientContactHandler,
+	clientActivityHandler *ClientActivityHandler,
+	clientImportHandler *ClientImportHandler,
+	clientExportHandler *ClientExportHandler,
+	clientMergeHandler *ClientMergeHandler,
+	tagHandler *TagHandler,
+	customFieldHandler *CustomFieldHandler,
 	taskHandler *TaskHandler,
 	paymentHandler *PaymentHandler,
+	paymentNoticeHandler *PaymentNoticeHandler,
+	adminHandler *AdminHandler,
 ) {
-	// Middleware global para CORS
-	r.engine.Use(middleware.CORSMiddleware())
+	// Middlewares globais de log e CORS
+	r.engine.Use(middleware.LoggerMiddleware(r.logger))
+	r.engine.Use(middleware.CORSMiddleware(r.config.Email.AppURL))
+
+	// Documentação Swagger
+	r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Chaves públicas para validação dos tokens por outros serviços
+	r.engine.GET("/.well-known/jwks.json", keysHandler.JWKS)
 
 	// Grupo de rotas públicas
-	public := r.engine.Group("/api/v1")
+	public := r.engine.Group("/api")
 	{
 		// Rotas de autenticação
 		public.POST("/auth/register", authHandler.Register)
 		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/refresh", authHandler.RefreshToken)
+		public.POST("/auth/mfa/verify", authHandler.VerifyMFA)
+		public.POST("/auth/verify-email", authHandler.VerifyEmail)
+		public.POST("/auth/verify-email/resend", authHandler.ResendVerification)
+		public.POST("/auth/email-change/confirm", profileHandler.ConfirmEmailChange)
+		public.POST("/auth/forgot-password", passwordResetHandler.ForgotPassword)
+		public.POST("/auth/reset-password", passwordResetHandler.ResetPassword)
+		public.POST("/auth/magic-link", magicLinkHandler.Request)
+		public.POST("/auth/magic-link/consume", magicLinkHandler.Consume)
+		public.POST("/auth/passkey/begin", passkeyHandler.BeginLogin)
+		public.POST("/auth/passkey/finish", passkeyHandler.FinishLogin)
+		public.GET("/exports/download", privacyHandler.DownloadExport)
+		public.GET("/auth/oidc/authorize", oidcHandler.Authorize)
+		public.POST("/auth/oidc/callback", oidcHandler.Callback)
 	}
 
-	// Grupo de rotas protegidas
-	protected := r.engine.Group("/api/v1")
-	protected.Use(middleware.AuthMiddleware(r.config))
+	// Grupo de rotas protegidas (JWT ou token de acesso pessoal)
+	protected := r.engine.Group("/api")
+	protected.Use(middleware.AuthMiddleware(r.authService, r.apiTokenService, r.audit, r.logger))
 	{
-		// Rotas de autenticação
-		protected.POST("/auth/refresh", authHandler.RefreshToken)
-
-		// Rotas de usuário
-		protected.GET("/user/profile", authHandler.GetProfile)
-
 		// Rotas de clientes
-		protected.POST("/clients", clientHandler.Create)
-		protected.GET("/clients", clientHandler.List)
-		protected.GET("/clients/:id", clientHandler.GetByID)
-		protected.PUT("/clients/:id", clientHandler.Update)
-		protected.DELETE("/clients/:id", clientHandler.Delete)
+		protected.POST("/clients", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Create)
+		protected.GET("/clients", middleware.RequireScope(models.ScopeClientsRead), clientHandler.List)
+		protected.POST("/clients/import", middleware.RequireScope(models.ScopeClientsWrite), clientImportHandler.Import)
+		protected.GET("/clients/export", middleware.RequireScope(models.ScopeClientsRead), clientExportHandler.Export)
+		protected.GET("/clients/trash", middleware.RequireScope(models.ScopeClientsRead), clientHandler.Trash)
+		protected.GET("/clients/duplicates", middleware.RequireScope(models.ScopeClientsRead), clientMergeHandler.Duplicates)
+		protected.GET("/clients/:id", middleware.RequireScope(models.ScopeClientsRead), clientHandler.GetByID)
+		protected.PUT("/clients/:id", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Update)
+		protected.DELETE("/clients/:id", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Delete)
+		protected.POST("/clients/:id/merge", middleware.RequireScope(models.ScopeClientsWrite), clientMergeHandler.Merge)
+		protected.POST("/clients/:id/archive", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Archive)
+		protected.POST("/clients/:id/unarchive", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Unarchive)
+		protected.POST("/clients/:id/restore", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Restore)
+		protected.DELETE("/clients/:id/purge", middleware.RequireScope(models.ScopeClientsWrite), clientHandler.Purge)
+		protected.GET("/clients/:id/contacts", middleware.RequireScope(models.ScopeClientsRead), clientContactHandler.List)
+		protected.POST("/clients/:id/contacts", middleware.RequireScope(models.ScopeClientsWrite), clientContactHandler.Create)
+		protected.PUT("/clients/:id/contacts/:contactId", middleware.RequireScope(models.ScopeClientsWrite), clientContactHandler.Update)
+		protected.DELETE("/clients/:id/contacts/:contactId", middleware.RequireScope(models.ScopeClientsWrite), clientContactHandler.Delete)
+		protected.GET("/clients/:id/timeline", middleware.RequireScope(models.ScopeClientsRead), clientActivityHandler.Timeline)
+		protected.POST("/clients/:id/activities", middleware.RequireScope(models.ScopeClientsWrite), clientActivityHandler.Create)
+		protected.PUT("/clients/:id/activities/:activityId", middleware.RequireScope(models.ScopeClientsWrite), clientActivityHandler.Update)
+		protected.DELETE("/clients/:id/activities/:activityId", middleware.RequireScope(models.ScopeClientsWrite), clientActivityHandler.Delete)
+
+		// Rotas de tags e campos personalizados dos clientes
+		protected.GET("/tags", middleware.RequireScope(models.ScopeClientsRead), tagHandler.List)
+		protected.POST("/tags", middleware.RequireScope(models.ScopeClientsWrite), tagHandler.Create)
+		protected.PUT("/tags/:id", middleware.RequireScope(models.ScopeClientsWrite), tagHandler.Update)
+		protected.DELETE("/tags/:id", middleware.RequireScope(models.ScopeClientsWrite), tagHandler.Delete)
+		protected.GET("/custom-fields", middleware.RequireScope(models.ScopeClientsRead), customFieldHandler.List)
+		protected.POST("/custom-fields", middleware.RequireScope(models.ScopeClientsWrite), customFieldHandler.Create)
+		protected.PUT("/custom-fields/:id", middleware.RequireScope(models.ScopeClientsWrite), customFieldHandler.Update)
+		protected.DELETE("/custom-fields/:id", middleware.RequireScope(models.ScopeClientsWrite), customFieldHandler.Delete)
 
 		// Rotas de tarefas
-		protected.POST("/tasks", taskHandler.Create)
-		protected.GET("/tasks", taskHandler.List)
-		protected.GET("/tasks/:id", taskHandler.GetByID)
-		protected.PUT("/tasks/:id", taskHandler.Update)
-		protected.DELETE("/tasks/:id", taskHandler.Delete)
+		protected.POST("/tasks", middleware.RequireScope(models.ScopeTasksWrite), taskHandler.Create)
+		protected.GET("/tasks", middleware.RequireScope(models.ScopeTasksRead), taskHandler.List)
+		protected.GET("/tasks/:id", middleware.RequireScope(models.ScopeTasksRead), taskHandler.GetByID)
+		protected.PUT("/tasks/:id", middleware.RequireScope(models.ScopeTasksWrite), taskHandler.Update)
+		protected.DELETE("/tasks/:id", middleware.RequireScope(models.ScopeTasksWrite), taskHandler.Delete)
 
 		// Rotas de pagamentos
-		protected.POST("/payments", paymentHandler.CreatePayment)
-		protected.GET("/payments", paymentHandler.ListPayments)
-		protected.GET("/payments/:id", paymentHandler.GetPayment)
-		protected.PUT("/payments/:id", paymentHandler.UpdatePayment)
-		protected.DELETE("/payments/:id", paymentHandler.DeletePayment)
-		protected.GET("/payments/client/:clientId", paymentHandler.GetPaymentByClientID)
+		protected.POST("/payments", middleware.RequireScope(models.ScopePaymentsWrite), paymentHandler.CreatePayment)
+		protected.GET("/payments", middleware.RequireScope(models.ScopePaymentsRead), paymentHandler.ListPayments)
+		protected.GET("/payments/:id", middleware.RequireScope(models.ScopePaymentsRead), paymentHandler.GetPayment)
+		protected.PUT("/payments/:id", middleware.RequireScope(models.ScopePaymentsWrite), paymentHandler.UpdatePayment)
+		protected.DELETE("/payments/:id", middleware.RequireScope(models.ScopePaymentsWrite), paymentHandler.DeletePayment)
+		protected.GET("/payments/client/:clientId", middleware.RequireScope(models.ScopePaymentsRead), paymentHandler.GetPaymentByClientID)
+		protected.POST("/payments/:id/send", middleware.RequireScope(models.ScopePaymentsWrite), paymentNoticeHandler.Send)
+	}
+
+	// Grupo de rotas da conta, restritas a sessões de login
+	account := protected.Group("")
+	account.Use(middleware.RequireSession())
+	{
+		// Rotas de autenticação
+		account.POST("/auth/logout", authHandler.Logout)
+
+		// Rotas de usuário
+		account.GET("/user/profile", authHandler.GetProfile)
+		account.PUT("/user/profile", profileHandler.UpdateProfile)
+		account.POST("/user/password", profileHandler.ChangePassword)
+		account.POST("/user/email", profileHandler.ChangeEmail)
+		account.POST("/user/export", privacyHandler.RequestExport)
+		account.DELETE("/user/account", privacyHandler.DeleteAccount)
+		account.POST("/user/account/cancel-deletion", privacyHandler.CancelDeletion)
+		account.GET("/user/security-events", auditHandler.ListMyEvents)
+		account.GET("/user/sessions", authHandler.ListSessions)
+		account.DELETE("/user/sessions/:id", authHandler.RevokeSession)
+		account.POST("/user/passkeys/register/begin", passkeyHandler.BeginRegistration)
+		account.POST("/user/passkeys/register/finish", passkeyHandler.FinishRegistration)
+		account.GET("/user/passkeys", passkeyHandler.List)
+		account.DELETE("/user/passkeys/:id", passkeyHandler.Delete)
+		account.POST("/user/mfa/enroll", mfaHandler.Enroll)
+		account.POST("/user/mfa/confirm", mfaHandler.Confirm)
+		account.POST("/user/mfa/disable", mfaHandler.Disable)
+		account.POST("/user/tokens", apiTokenHandler.Create)
+		account.GET("/user/tokens", apiTokenHandler.List)
+		account.DELETE("/user/tokens/:id", apiTokenHandler.Revoke)
+	}
+
+	// Grupo de rotas administrativas
+	admin := account.Group("/admin")
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.GET("/users", adminHandler.ListUsers)
+		admin.GET("/users/:id", adminHandler.GetUser)
+		admin.POST("/users/:id/block", adminHandler.BlockUser)
+		admin.POST("/users/:id/unblock", adminHandler.UnblockUser)
+		admin.PUT("/users/:id/plan", adminHandler.ChangePlan)
+		admin.PUT("/users/:id/role", adminHandler.ChangeRole)
+		admin.POST("/users/:id/impersonate", adminHandler.Impersonate)
+		admin.POST("/impersonation/end", adminHandler.EndImpersonation)
+		admin.GET("/audit-events", auditHandler.ListEvents)
 	}
 }
 
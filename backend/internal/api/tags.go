@@ -0,0 +1,174 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// TagRequest representa os dados de criação/atualização de uma tag
+type TagRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=50" example:"Agência"`
+	Color string `json:"color" binding:"omitempty,hexcolor,len=7" example:"#2563eb"`
+}
+
+// TagHandler gerencia as requisições das tags dos clientes
+type TagHandler struct {
+	tagService services.TagService
+	logger     logger.Logger
+}
+
+// NewTagHandler cria uma nova instância de TagHandler
+func NewTagHandler(tagService services.TagService, logger logger.Logger) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// List godoc
+// @Summary      Listar tags
+// @Description  Lista as tags do usuário em ordem alfabética
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.List(user.ID)
+	if err != nil {
+		h.respondError(c, err, "Erro ao listar tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// Create godoc
+// @Summary      Criar tag
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        request  body  TagRequest  true  "Dados da tag"
+// @Success      201  {object}  models.Tag
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      409  {object}  map[string]interface{} "Nome já usado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.Create(user.ID, req.Name, req.Color)
+	if err != nil {
+		h.respondError(c, err, "Erro ao criar tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// Update godoc
+// @Summary      Atualizar tag
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Security     Bearer
+// @Param        id       path  int         true  "ID da tag"
+// @Param        request  body  TagRequest  true  "Dados da tag"
+// @Success      200  {object}  models.Tag
+// @Failure      400  {object}  map[string]interface{} "Dados inválidos"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Tag não encontrada"
+// @Failure      409  {object}  map[string]interface{} "Nome já usado"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.Update(user.ID, id, req.Name, req.Color)
+	if err != nil {
+		h.respondError(c, err, "Erro ao atualizar tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Delete godoc
+// @Summary      Remover tag
+// @Description  Remove a tag e a retira de todos os clientes
+// @Tags         clients
+// @Produce      json
+// @Security     Bearer
+// @Param        id  path  int  true  "ID da tag"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "ID inválido"
+// @Failure      401  {object}  map[string]interface{} "Não autorizado"
+// @Failure      404  {object}  map[string]interface{} "Tag não encontrada"
+// @Failure      500  {object}  map[string]interface{} "Erro interno"
+// @Router       /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parsePathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.Delete(user.ID, id); err != nil {
+		h.respondError(c, err, "Erro ao remover tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag removida com sucesso"})
+}
+
+// respondError converte os erros do serviço de tags na resposta HTTP
+func (h *TagHandler) respondError(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrTagNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag não encontrada"})
+	case services.ErrTagNameInUse:
+		c.JSON(http.StatusConflict, gin.H{"error": "Já existe uma tag com este nome"})
+	default:
+		h.logger.Error(message + ": " + err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
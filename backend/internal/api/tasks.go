@@ -44,9 +44,8 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
@@ -58,7 +57,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 	}
 
 	task, err := h.taskService.Create(
-		userID.(uint),
+		user.ID,
 		req.ClientID,
 		req.Title,
 		req.Description,
@@ -84,13 +83,12 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	task, err := h.taskService.GetByID(uint(id), userID.(uint))
+	task, err := h.taskService.GetByID(uint(id), user.ID)
 	if err != nil {
 		if err == services.ErrTaskNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Tarefa não encontrada"})
@@ -105,16 +103,15 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 
 // List processa a requisição de listagem de tarefas
 func (h *TaskHandler) List(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	tasks, total, err := h.taskService.GetByUserID(userID.(uint), page, pageSize)
+	tasks, total, err := h.taskService.GetByUserID(user.ID, page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar tarefas"})
 		return
@@ -144,9 +141,8 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
@@ -159,7 +155,7 @@ func (h *TaskHandler) Update(c *gin.Context) {
 
 	task, err := h.taskService.Update(
 		uint(id),
-		userID.(uint),
+		user.ID,
 		req.ClientID,
 		req.Title,
 		req.Description,
@@ -191,13 +187,12 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+	user, ok := currentUser(c)
+	if !ok {
 		return
 	}
 
-	err = h.taskService.Delete(uint(id), userID.(uint))
+	err = h.taskService.Delete(uint(id), user.ID)
 	if err != nil {
 		if err == services.ErrTaskNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Tarefa não encontrada"})
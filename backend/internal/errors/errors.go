@@ -4,11 +4,32 @@ import "errors"
 
 // Erros comuns da aplicação
 var (
-	ErrUserNotFound     = errors.New("usuário não encontrado")
-	ErrEmailInUse       = errors.New("email já está em uso")
-	ErrInvalidPassword  = errors.New("senha inválida")
-	ErrUserDeactivated  = errors.New("usuário desativado")
-	ErrInvalidToken     = errors.New("token inválido")
-	ErrTokenExpired     = errors.New("token expirado")
-	ErrInvalidCredentials = errors.New("credenciais inválidas")
+	ErrUserNotFound          = errors.New("usuário não encontrado")
+	ErrEmailInUse            = errors.New("email já está em uso")
+	ErrInvalidPassword       = errors.New("senha inválida")
+	ErrUserDeactivated       = errors.New("usuário desativado")
+	ErrInvalidToken          = errors.New("token inválido")
+	ErrTokenExpired          = errors.New("token expirado")
+	ErrInvalidCredentials    = errors.New("credenciais inválidas")
+	ErrRefreshTokenReused    = errors.New("refresh token reutilizado")
+	ErrTokenRevoked          = errors.New("token revogado")
+	ErrSessionNotFound       = errors.New("sessão não encontrada")
+	ErrInvalidMFACode        = errors.New("código de verificação inválido")
+	ErrMFAAlreadyEnabled     = errors.New("autenticação em dois fatores já está ativada")
+	ErrMFANotEnabled         = errors.New("autenticação em dois fatores não está ativada")
+	ErrMFANotEnrolled        = errors.New("cadastro da autenticação em dois fatores não foi iniciado")
+	ErrTooManyAttempts       = errors.New("muitas tentativas de login")
+	ErrSelfAdminAction       = errors.New("administrador não pode alterar a própria conta")
+	ErrEmailNotVerified      = errors.New("e-mail não confirmado")
+	ErrAPITokenNotFound      = errors.New("token de acesso não encontrado")
+	ErrInvalidScope          = errors.New("escopo inválido")
+	ErrOIDCDisabled          = errors.New("login via provedor de identidade não configurado")
+	ErrOIDCEmailNotVerified  = errors.New("provedor de identidade não confirmou o e-mail")
+	ErrEmailUnchanged        = errors.New("novo e-mail igual ao atual")
+	ErrPasswordLoginDisabled = errors.New("login com senha desativado para este usuário")
+	ErrPasskeyNotFound       = errors.New("passkey não encontrada")
+	ErrInvalidPasskey        = errors.New("passkey inválida")
+	ErrDeletionNotScheduled  = errors.New("exclusão da conta não está agendada")
+	ErrCannotImpersonate     = errors.New("não é possível acessar a conta de outro administrador")
+	ErrNotImpersonating      = errors.New("a sessão não é um acesso de suporte")
 )
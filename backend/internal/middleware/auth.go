@@ -1,24 +1,34 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/jpcode092/crm-freela/configs"
 	"github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
 )
 
-// Claims representa os claims do JWT
-type Claims struct {
-	UserID uint `json:"user_id"`
-	jwt.RegisteredClaims
-}
+const (
+	// currentUserKey é a chave do usuário autenticado no contexto da requisição
+	currentUserKey = "currentUser"
+	// impersonatorKey é a chave do administrador que usa um acesso de suporte
+	impersonatorKey = "impersonator"
+)
 
-// AuthMiddleware é o middleware de autenticação
-func AuthMiddleware(config *configs.Config) gin.HandlerFunc {
+// AuthMiddleware é o middleware de autenticação, o único das rotas protegidas.
+// Aceita access tokens JWT e tokens de acesso pessoal (prefixo models.APITokenPrefix),
+// carrega o usuário uma única vez por requisição e recusa usuários bloqueados ou inativos.
+// O usuário fica disponível aos handlers em CurrentUser.
+//
+// Nos tokens de acesso de suporte (claim "act"), o administrador também precisa continuar ativo;
+// sem permissão de escrita, só leituras são aceitas. Cada requisição é registrada no log e na
+// trilha de auditoria com o ID do administrador.
+func AuthMiddleware(authService services.AuthService, apiTokenService services.APITokenService, audit services.AuditWriter, log logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Obtém o token do header Authorization
 		authHeader := c.GetHeader("Authorization")
@@ -31,54 +41,138 @@ func AuthMiddleware(config *configs.Config) gin.HandlerFunc {
 		// Remove o prefixo "Bearer " do token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// Valida o token
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			// Verifica o método de assinatura
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.ErrInvalidToken
+		var userID uint
+		var claims *services.Claims
+		if strings.HasPrefix(tokenString, models.APITokenPrefix) {
+			// Tokens de acesso pessoal só valem para as rotas liberadas pelos seus escopos
+			apiToken, err := apiTokenService.Authenticate(tokenString, c.ClientIP())
+			if err != nil {
+				abortWithTokenError(c, err)
+				return
+			}
+
+			userID = apiToken.UserID
+			c.Set("apiToken", apiToken)
+		} else {
+			// Valida o token (assinatura, expiração e revogação)
+			var err error
+			claims, err = authService.ValidateAccessToken(tokenString)
+			if err != nil {
+				abortWithTokenError(c, err)
+				return
 			}
-			return []byte(config.JWT.Secret), nil
-		})
 
+			userID = claims.UserID
+			c.Set("claims", claims)
+		}
+
+		// O usuário é lido do banco a cada requisição, para que bloqueios e mudanças de papel valham de imediato
+		user, err := authService.GetUserByID(userID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+			if err == errors.ErrUserNotFound {
+				abortWithTokenError(c, errors.ErrInvalidToken)
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao autenticar usuário"})
 			c.Abort()
 			return
 		}
 
-		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
-			c.Abort()
+		if user.Status != models.UserStatusActive {
+			abortWithTokenError(c, errors.ErrUserDeactivated)
 			return
 		}
 
-		// Define o ID do usuário no contexto
-		c.Set("userID", claims.UserID)
-		c.Next()
+		c.Set(currentUserKey, user)
+
+		if claims == nil || claims.Actor == nil {
+			c.Next()
+			return
+		}
+
+		actor, err := authService.GetUserByID(claims.Actor.UserID)
+		if err != nil || actor.Status != models.UserStatusActive || actor.Role != models.RoleAdmin {
+			abortWithTokenError(c, errors.ErrTokenRevoked)
+			return
+		}
+		c.Set(impersonatorKey, actor)
+
+		if !claims.Actor.AllowWrite && !isReadOnlyMethod(c.Request.Method) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Acesso de suporte somente leitura"})
+			c.Abort()
+		} else {
+			c.Next()
+		}
+
+		recordImpersonatedRequest(c, audit, log, actor, user)
 	}
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(userID uint, config *configs.Config) (string, error) {
-	// Create the claims
-	claims := Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.JWT.AccessTokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
+// Impersonator retorna o administrador que usa um acesso de suporte na requisição, se houver
+func Impersonator(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(impersonatorKey)
+	if !exists {
+		return nil, false
 	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+// isReadOnlyMethod indica se o método HTTP não altera dados
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
 
-	// Create the token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// recordImpersonatedRequest registra uma requisição feita com acesso de suporte no log e na trilha de auditoria
+func recordImpersonatedRequest(c *gin.Context, audit services.AuditWriter, log logger.Logger, actor, user *models.User) {
+	status := c.Writer.Status()
+	log.Info(fmt.Sprintf("Acesso de suporte: administrador %d como usuário %d: %s %s (%d)",
+		actor.ID, user.ID, c.Request.Method, c.Request.URL.Path, status))
 
-	// Sign the token
-	tokenString, err := token.SignedString([]byte(config.JWT.Secret))
-	if err != nil {
-		return "", err
+	actorID, userID := actor.ID, user.ID
+	outcome := models.AuditSuccess
+	if status >= http.StatusBadRequest {
+		outcome = models.AuditFailure
 	}
 
-	return tokenString, nil
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+		"status": status,
+	})
+	audit.Record(&models.AuditEvent{
+		ActorID:   &actorID,
+		UserID:    &userID,
+		Action:    models.AuditImpersonatedRequest,
+		Outcome:   outcome,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Metadata:  string(metadata),
+	})
+}
+
+// CurrentUser retorna o usuário autenticado pelo AuthMiddleware.
+// Retorna false em rotas que não passaram pelo middleware.
+func CurrentUser(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(currentUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+// abortWithTokenError interrompe a requisição com a mensagem correspondente ao erro de validação
+func abortWithTokenError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrTokenExpired:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token expirado"})
+	case errors.ErrTokenRevoked:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revogado"})
+	case errors.ErrUserDeactivated:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Usuário desativado"})
+	default:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+	}
+	c.Abort()
 }
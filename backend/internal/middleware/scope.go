@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/internal/models"
+)
+
+// APIToken retorna o token de acesso pessoal usado na requisição, se houver.
+// Requisições autenticadas com JWT não têm token de acesso e não são limitadas por escopo.
+func APIToken(c *gin.Context) (*models.APIToken, bool) {
+	value, exists := c.Get("apiToken")
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*models.APIToken)
+	return token, ok
+}
+
+// HasScope verifica se a requisição pode usar o escopo informado
+func HasScope(c *gin.Context, scope string) bool {
+	token, ok := APIToken(c)
+	if !ok {
+		return true
+	}
+	return token.HasScope(scope)
+}
+
+// RequireScope é o middleware que exige o escopo informado dos tokens de acesso pessoal.
+// Deve ser registrado após o AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasScope(c, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token sem permissão para esta operação", "required_scope": scope})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession é o middleware que restringe a rota a sessões de login (JWT),
+// impedindo que tokens de acesso pessoal gerenciem a conta. Acessos de suporte podem
+// consultar a conta, mas nunca alterá-la, mesmo com permissão de escrita.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIToken(c); ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Operação não permitida com token de acesso"})
+			c.Abort()
+			return
+		}
+		if _, ok := Impersonator(c); ok && !isReadOnlyMethod(c.Request.Method) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Operação não permitida durante o acesso de suporte"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APITokenPrefix identifies personal access tokens, so they can be told apart from JWTs
+const APITokenPrefix = "crm_pat_"
+
+// Scopes available to personal access tokens. A write scope also grants read access to the same resource.
+const (
+	ScopeClientsRead   = "clients:read"
+	ScopeClientsWrite  = "clients:write"
+	ScopeTasksRead     = "tasks:read"
+	ScopeTasksWrite    = "tasks:write"
+	ScopePaymentsRead  = "payments:read"
+	ScopePaymentsWrite = "payments:write"
+)
+
+// APITokenScopes lists every scope that can be granted to a personal access token
+var APITokenScopes = []string{
+	ScopeClientsRead,
+	ScopeClientsWrite,
+	ScopeTasksRead,
+	ScopeTasksWrite,
+	ScopePaymentsRead,
+	ScopePaymentsWrite,
+}
+
+// APIToken represents a named personal access token used by scripts and integrations.
+// Only the SHA-256 hash of the token is stored; the plain value is shown once at creation.
+type APIToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	User       User       `json:"-" gorm:"foreignKey:UserID"`
+	Name       string     `json:"name" gorm:"size:100;not null"`
+	Prefix     string     `json:"prefix" gorm:"size:20;not null"` // início do token, para identificação na listagem
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string     `json:"-" gorm:"size:255;not null"` // escopos separados por espaço
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip" gorm:"size:45"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ScopeList returns the scopes granted to the token
+func (t *APIToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// HasScope checks if the token grants the scope. A write scope also grants read access.
+func (t *APIToken) HasScope(scope string) bool {
+	write := strings.TrimSuffix(scope, ":read") + ":write"
+	for _, granted := range t.ScopeList() {
+		if granted == scope || granted == write {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired checks if the token is past its expiration date
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// IsRevoked checks if the token was revoked
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
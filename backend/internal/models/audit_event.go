@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditAction identifies the kind of event recorded in the audit trail
+type AuditAction string
+
+const (
+	AuditAdminUserBlocked   AuditAction = "admin.user_blocked"
+	AuditAdminUserUnblocked AuditAction = "admin.user_unblocked"
+	AuditAdminPlanChanged   AuditAction = "admin.plan_changed"
+	AuditAdminRoleChanged   AuditAction = "admin.role_changed"
+
+	AuditImpersonationStarted AuditAction = "admin.impersonation_started"
+	AuditImpersonationEnded   AuditAction = "admin.impersonation_ended"
+	AuditImpersonatedRequest  AuditAction = "admin.impersonated_request"
+
+	AuditLogin         AuditAction = "auth.login"
+	AuditTokenRefresh  AuditAction = "auth.token_refresh"
+	AuditPasswordReset AuditAction = "auth.password_reset"
+
+	AuditDataExportRequested  AuditAction = "privacy.data_export_requested"
+	AuditDataExportDownloaded AuditAction = "privacy.data_export_downloaded"
+)
+
+// AuditOutcome represents the result of an audited action
+type AuditOutcome string
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+// AuditEvent represents an append-only entry of the audit trail.
+// ActorID is who performed the action and UserID is the account affected by it.
+type AuditEvent struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	ActorID   *uint        `json:"actor_id" gorm:"index"`
+	UserID    *uint        `json:"user_id" gorm:"index"`
+	Action    AuditAction  `json:"action" gorm:"size:50;not null;index"`
+	Outcome   AuditOutcome `json:"outcome" gorm:"size:20;not null"`
+	IP        string       `json:"ip" gorm:"size:45"`
+	UserAgent string       `json:"user_agent" gorm:"size:255"`
+	Metadata  string       `json:"metadata,omitempty" gorm:"type:text"` // detalhes em JSON
+	CreatedAt time.Time    `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ActivityType represents the kind of interaction recorded in a client's history
+type ActivityType string
+
+const (
+	ActivityNote         ActivityType = "note"
+	ActivityCall         ActivityType = "call"
+	ActivityMeeting      ActivityType = "meeting"
+	ActivityEmail        ActivityType = "email"
+	ActivityStatusChange ActivityType = "status_change" // registrada automaticamente quando o status do cliente muda
+	ActivityMerge        ActivityType = "merge"         // registrada automaticamente quando outro cliente é mesclado a este
+)
+
+// IsSystem reports whether activities of this type are recorded by the system and therefore read-only
+func (t ActivityType) IsSystem() bool {
+	return t == ActivityStatusChange || t == ActivityMerge
+}
+
+// ClientActivity represents an entry in a client's history, such as a call, a meeting or a note.
+// An activity may reference the task or payment it is about.
+type ClientActivity struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	UserID     uint           `json:"user_id" gorm:"not null;index"`
+	User       User           `json:"-" gorm:"foreignKey:UserID"`
+	ClientID   uint           `json:"client_id" gorm:"not null;index:idx_client_activities_timeline,priority:1"`
+	Client     Client         `json:"-" gorm:"foreignKey:ClientID"`
+	Type       ActivityType   `json:"type" gorm:"size:20;not null"`
+	OccurredAt time.Time      `json:"occurred_at" gorm:"not null;index:idx_client_activities_timeline,priority:2"`
+	Body       string         `json:"body" gorm:"type:text"`
+	TaskID     *uint          `json:"task_id" gorm:"index"`
+	Task       *Task          `json:"-" gorm:"foreignKey:TaskID"`
+	PaymentID  *uint          `json:"payment_id" gorm:"index"`
+	Payment    *Payment       `json:"-" gorm:"foreignKey:PaymentID"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
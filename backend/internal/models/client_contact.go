@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClientContact represents a person at a client company, such as the finance contact or the project manager.
+// A client has at most one primary contact and one billing contact, enforced by partial unique indexes.
+type ClientContact struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	UserID    uint           `json:"user_id" gorm:"not null;index"`
+	User      User           `json:"-" gorm:"foreignKey:UserID"`
+	ClientID  uint           `json:"client_id" gorm:"not null;index;uniqueIndex:idx_client_contacts_primary,where:is_primary AND deleted_at IS NULL;uniqueIndex:idx_client_contacts_billing,where:is_billing AND deleted_at IS NULL"`
+	Client    Client         `json:"-" gorm:"foreignKey:ClientID"`
+	Name      string         `json:"name" gorm:"size:100;not null"`
+	Role      string         `json:"role" gorm:"size:100"` // cargo ou função (ex.: financeiro, gerente de projeto)
+	Email     string         `json:"email" gorm:"size:100"`
+	Phone     string         `json:"phone" gorm:"size:20"`
+	IsPrimary bool           `json:"is_primary" gorm:"not null;default:false"`
+	IsBilling bool           `json:"is_billing" gorm:"not null;default:false"` // recebe as cobranças e os lembretes de pagamento
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
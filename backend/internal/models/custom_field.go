@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CustomFieldType represents the type of value accepted by a custom field
+type CustomFieldType string
+
+const (
+	CustomFieldText   CustomFieldType = "text"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldDate   CustomFieldType = "date"
+	CustomFieldSelect CustomFieldType = "select"
+)
+
+// CustomFieldDefinition represents a field the user added to their clients.
+// The key identifies the value in Client.CustomFields and cannot change after creation.
+type CustomFieldDefinition struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	UserID    uint            `json:"user_id" gorm:"not null;uniqueIndex:idx_custom_fields_user_key"`
+	User      User            `json:"-" gorm:"foreignKey:UserID"`
+	Key       string          `json:"key" gorm:"size:50;not null;uniqueIndex:idx_custom_fields_user_key"`
+	Label     string          `json:"label" gorm:"size:100;not null"`
+	Type      CustomFieldType `json:"type" gorm:"size:20;not null"`
+	Options   StringList      `json:"options" gorm:"type:jsonb;not null;default:'[]'"` // valores aceitos pelos campos select
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CustomFieldValues holds the custom field values of a client, indexed by the field key
+type CustomFieldValues map[string]interface{}
+
+// Value implements driver.Valuer, storing the values as JSON
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner, reading the values stored as JSON
+func (v *CustomFieldValues) Scan(src interface{}) error {
+	data, err := jsonBytes(src)
+	if err != nil || data == nil {
+		*v = CustomFieldValues{}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Value implements driver.Valuer, storing the list as JSON
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner, reading the list stored as JSON
+func (l *StringList) Scan(src interface{}) error {
+	data, err := jsonBytes(src)
+	if err != nil || data == nil {
+		*l = StringList{}
+		return err
+	}
+	return json.Unmarshal(data, l)
+}
+
+// jsonBytes returns the raw JSON read from a jsonb column
+func jsonBytes(src interface{}) ([]byte, error) {
+	switch value := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return value, nil
+	case string:
+		return []byte(value), nil
+	default:
+		return nil, errors.New("valor JSON inválido")
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// DataExport represents a ZIP archive with the user's data, requested under the LGPD right of access.
+// The archive is downloaded through a link sent by email; only the SHA-256 hash of the link token is stored.
+type DataExport struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	User         User       `json:"-" gorm:"foreignKey:UserID"`
+	TokenHash    string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Archive      []byte     `json:"-" gorm:"type:bytea;not null"`
+	SizeBytes    int        `json:"size_bytes" gorm:"not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	DownloadedAt *time.Time `json:"downloaded_at"` // primeiro download do arquivo
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsExpired checks if the download link is past its expiration date
+func (e *DataExport) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// MagicLink represents a single-use passwordless login link sent by email.
+// Only the SHA-256 hash of the token is stored; the plain value exists only in the link.
+type MagicLink struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"-" gorm:"foreignKey:UserID"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	IP        string     `json:"ip" gorm:"size:45"` // IP que solicitou o link
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsExpired checks if the link is past its expiration date
+func (l *MagicLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// IsUsed checks if the link was already exchanged for a session
+func (l *MagicLink) IsUsed() bool {
+	return l.UsedAt != nil
+}
@@ -1,10 +1,16 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // ErrRecordNotFound é retornado quando um registro não é encontrado
 var ErrRecordNotFound = errors.New("registro não encontrado")
 
+// ErrDuplicateKey é retornado quando a gravação viola um índice único
+var ErrDuplicateKey = errors.New("registro duplicado")
+
 // UserRepository define a interface para operações de persistência de usuários
 type UserRepository interface {
 	Create(user *User) error
@@ -13,6 +19,8 @@ type UserRepository interface {
 	GetByID(id uint) (*User, error)
 	GetByEmail(email string) (*User, error)
 	List(page, pageSize int) ([]User, int64, error)
+	RegisterFailedLogin(id uint, threshold int, base, max time.Duration) (int, error)
+	ResetFailedLogins(id uint) error
 }
 
 // ClientRepository define a interface para operações de persistência de clientes
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents an issued refresh token.
+// Every rotation creates a new row that shares the FamilyID of the original login,
+// so a replayed (already rotated) token can revoke the whole family.
+// AccessTokenID holds the jti of the access token issued together with the refresh token.
+type Session struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	User          User       `json:"-" gorm:"foreignKey:UserID"`
+	FamilyID      string     `json:"family_id" gorm:"size:64;not null;index"`
+	TokenHash     string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	AccessTokenID string     `json:"-" gorm:"size:64"`
+	UserAgent     string     `json:"user_agent" gorm:"size:255"`
+	IP            string     `json:"ip" gorm:"size:45"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"not null"`
+	RotatedAt     *time.Time `json:"rotated_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// IsExpired checks if the refresh token is past its expiration date
+func (s *Session) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// IsRotated checks if the refresh token was already exchanged for a new one
+func (s *Session) IsRotated() bool {
+	return s.RotatedAt != nil
+}
+
+// IsRevoked checks if the session was revoked
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
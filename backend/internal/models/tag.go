@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Tag represents a user-defined label used to segment clients (industry, source, contract type)
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	User      User      `json:"-" gorm:"foreignKey:UserID"`
+	Name      string    `json:"name" gorm:"size:50;not null;uniqueIndex:idx_tags_user_name"`
+	Color     string    `json:"color" gorm:"size:7"` // cor em hexadecimal (ex.: #2563eb)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -33,20 +33,38 @@ const (
 	RoleUser  UserRole = "user"
 )
 
+// UserPreferences represents the user's display and notification preferences
+type UserPreferences struct {
+	Language           string `json:"language" gorm:"size:10;not null;default:'pt-BR'"`
+	Timezone           string `json:"timezone" gorm:"size:64;not null;default:'America/Sao_Paulo'"`
+	EmailNotifications bool   `json:"email_notifications" gorm:"not null;default:true"`
+}
+
 // User represents a user in the system
 type User struct {
-	ID               uint           `json:"id" gorm:"primaryKey"`
-	Name             string         `json:"name" gorm:"size:100;not null"`
-	Email            string         `json:"email" gorm:"size:100;not null;uniqueIndex"`
-	Password         string         `json:"-" gorm:"size:100;not null"`
-	Role             UserRole       `json:"role" gorm:"size:20;not null;default:'user'"`
-	Plan             PlanType       `json:"plan" gorm:"size:20;not null;default:'free'"`
-	Status           UserStatus     `json:"status" gorm:"size:20;not null;default:'active'"`
-	ResetToken       *string        `json:"-" gorm:"size:100"`
-	ResetTokenExpires time.Time     `json:"-"`
-	CreatedAt        time.Time      `json:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at"`
-	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                    uint            `json:"id" gorm:"primaryKey"`
+	Name                  string          `json:"name" gorm:"size:100;not null"`
+	Email                 string          `json:"email" gorm:"size:100;not null;uniqueIndex"`
+	Password              string          `json:"-" gorm:"size:100;not null"`
+	Role                  UserRole        `json:"role" gorm:"size:20;not null;default:'user'"`
+	Plan                  PlanType        `json:"plan" gorm:"size:20;not null;default:'free'"`
+	Status                UserStatus      `json:"status" gorm:"size:20;not null;default:'active'"`
+	EmailVerifiedAt       *time.Time      `json:"email_verified_at"`
+	PendingEmail          *string         `json:"pending_email" gorm:"size:100"` // novo e-mail aguardando confirmação pelo link enviado
+	Preferences           UserPreferences `json:"preferences" gorm:"embedded;embeddedPrefix:pref_"`
+	PasswordLoginDisabled bool            `json:"password_login_disabled" gorm:"not null;default:false"` // entra apenas por link mágico ou provedor de identidade
+	ResetToken            *string         `json:"-" gorm:"size:100"`                                     // hash SHA-256 do token enviado no link de recuperação
+	ResetTokenExpires     time.Time       `json:"-"`
+	MFAEnabled            bool            `json:"mfa_enabled" gorm:"not null;default:false"`
+	MFASecret             string          `json:"-" gorm:"size:255"` // segredo TOTP cifrado; preenchido ao iniciar o cadastro
+	MFAEnabledAt          *time.Time      `json:"-"`
+	MFALastUsedStep       int64           `json:"-"` // última janela TOTP aceita, impede a reutilização do mesmo código
+	FailedLoginAttempts   int             `json:"-" gorm:"not null;default:0"`
+	LockedUntil           *time.Time      `json:"-"`                     // bloqueio temporário após falhas consecutivas de login
+	DeletionScheduledAt   *time.Time      `json:"deletion_scheduled_at"` // data em que os dados serão removidos, após o pedido de exclusão da conta
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt  `json:"-" gorm:"index"`
 }
 
 // BeforeSave is a GORM hook that hashes the password before saving
@@ -61,6 +79,11 @@ func (u *User) BeforeSave(tx *gorm.DB) error {
 	return nil
 }
 
+// IsLocked checks if the account is temporarily locked after failed login attempts
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
 // CheckPassword checks if the provided password matches the stored hash
 func (u *User) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
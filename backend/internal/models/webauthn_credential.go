@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WebAuthnCredential represents a passkey registered by the user.
+// The public key is kept in the COSE format sent by the authenticator; the private key never leaves it.
+type WebAuthnCredential struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null;index"`
+	User           User       `json:"-" gorm:"foreignKey:UserID"`
+	Name           string     `json:"name" gorm:"size:100;not null"`
+	CredentialID   []byte     `json:"-" gorm:"type:bytea;not null;uniqueIndex"`
+	PublicKey      []byte     `json:"-" gorm:"type:bytea;not null"`
+	Algorithm      int        `json:"algorithm" gorm:"not null"`     // algoritmo COSE da chave (ex.: -7 para ES256)
+	SignCount      uint32     `json:"-" gorm:"type:bigint;not null"` // último contador de assinaturas informado pelo autenticador
+	Transports     string     `json:"-" gorm:"size:100"`             // transportes separados por espaço (usb, nfc, ble, internal, hybrid)
+	AAGUID         []byte     `json:"-" gorm:"type:bytea"`
+	BackupEligible bool       `json:"backup_eligible"` // passkey sincronizável entre dispositivos
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TransportList returns the transports reported by the authenticator
+func (c *WebAuthnCredential) TransportList() []string {
+	return strings.Fields(c.Transports)
+}
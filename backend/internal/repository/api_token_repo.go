@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// APITokenRepository define a interface para operações de repositório de tokens de acesso pessoal
+type APITokenRepository interface {
+	Create(token *models.APIToken) error
+	GetByTokenHash(tokenHash string) (*models.APIToken, error)
+	ListByUser(userID uint) ([]models.APIToken, error)
+	Revoke(userID, id uint) (bool, error)
+	TouchLastUsed(id uint, ip string, usedAt time.Time) error
+}
+
+// apiTokenRepository implementa a interface APITokenRepository
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewAPITokenRepository cria uma nova instância de APITokenRepository
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{
+		db: db,
+	}
+}
+
+// Create cria um novo token de acesso no banco de dados
+func (r *apiTokenRepository) Create(token *models.APIToken) error {
+	result := r.db.Create(token)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao criar token de acesso: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByTokenHash busca um token de acesso pelo hash
+func (r *apiTokenRepository) GetByTokenHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	result := r.db.Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar token de acesso: %w", result.Error)
+	}
+	return &token, nil
+}
+
+// ListByUser retorna os tokens de acesso não revogados do usuário
+func (r *apiTokenRepository) ListByUser(userID uint) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+	result := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).Order("created_at DESC").Find(&tokens)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar tokens de acesso: %w", result.Error)
+	}
+	return tokens, nil
+}
+
+// Revoke revoga um token de acesso do usuário. Retorna false se o token não existir ou já estiver revogado.
+func (r *apiTokenRepository) Revoke(userID, id uint) (bool, error) {
+	result := r.db.Model(&models.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao revogar token de acesso: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchLastUsed registra o último uso do token
+func (r *apiTokenRepository) TouchLastUsed(id uint, ip string, usedAt time.Time) error {
+	result := r.db.Model(&models.APIToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip})
+	if result.Error != nil {
+		return fmt.Errorf("erro ao registrar uso do token de acesso: %w", result.Error)
+	}
+	return nil
+}
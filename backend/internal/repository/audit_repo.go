@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditEventFilter representa os filtros da consulta à trilha de auditoria
+type AuditEventFilter struct {
+	UserID  uint // conta afetada
+	ActorID uint // quem executou a ação
+	Action  models.AuditAction
+	Outcome models.AuditOutcome
+	IP      string
+	From    *time.Time
+	To      *time.Time
+}
+
+// AuditEventRepository define a interface para operações de repositório da trilha de auditoria.
+// A trilha é somente de inserção: não há métodos de atualização ou remoção.
+type AuditEventRepository interface {
+	Create(event *models.AuditEvent) error
+	Search(filter AuditEventFilter, page, pageSize int) ([]models.AuditEvent, int64, error)
+}
+
+// auditEventRepository implementa a interface AuditEventRepository
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository cria uma nova instância de AuditEventRepository
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{
+		db: db,
+	}
+}
+
+// Create registra um novo evento na trilha de auditoria
+func (r *auditEventRepository) Create(event *models.AuditEvent) error {
+	result := r.db.Create(event)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao registrar evento de auditoria: %w", result.Error)
+	}
+	return nil
+}
+
+// Search retorna uma lista paginada de eventos que atendem aos filtros, do mais recente ao mais antigo
+func (r *auditEventRepository) Search(filter AuditEventFilter, page, pageSize int) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var total int64
+
+	query := r.db.Model(&models.AuditEvent{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	// Conta o total de registros
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar eventos de auditoria: %w", err)
+	}
+
+	// Calcula o offset para paginação
+	offset := (page - 1) * pageSize
+
+	result := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&events)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar eventos de auditoria: %w", result.Error)
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// Eventos do sistema que aparecem na linha do tempo junto com as atividades registradas
+const (
+	TimelineTaskCreated     = "task_created"
+	TimelineTaskCompleted   = "task_completed"
+	TimelinePaymentReceived = "payment_received"
+	TimelinePaymentOverdue  = "payment_overdue"
+)
+
+// TimelineEntry representa um item da linha do tempo do cliente: uma atividade registrada
+// (Source "activity") ou um evento derivado das tarefas e dos pagamentos (Source "task" ou "payment")
+type TimelineEntry struct {
+	Source     string    `json:"source"`
+	Kind       string    `json:"kind"` // tipo da atividade ou um dos eventos Timeline*
+	ID         uint      `json:"id"`   // ID da atividade, da tarefa ou do pagamento, conforme Source
+	OccurredAt time.Time `json:"occurred_at"`
+	Title      string    `json:"title,omitempty"`
+	Body       string    `json:"body,omitempty"`
+	TaskID     *uint     `json:"task_id,omitempty"`
+	PaymentID  *uint     `json:"payment_id,omitempty"`
+	Amount     *float64  `json:"amount,omitempty"`
+	Currency   string    `json:"currency,omitempty"`
+}
+
+// TimelineCursor identifica o último item de uma página da linha do tempo; a página seguinte
+// começa no item imediatamente anterior a ele
+type TimelineCursor struct {
+	OccurredAt time.Time
+	Kind       string
+	ID         uint
+}
+
+// ClientActivityRepository define a interface para operações de repositório das atividades dos clientes
+type ClientActivityRepository interface {
+	Create(activity *models.ClientActivity) error
+	GetByID(clientID, id uint) (*models.ClientActivity, error)
+	Update(activity *models.ClientActivity) error
+	Delete(clientID, id uint) (bool, error)
+	LinksBelongToClient(userID, clientID uint, taskID, paymentID *uint) (bool, error)
+	Timeline(userID, clientID uint, cursor *TimelineCursor, limit int) ([]TimelineEntry, error)
+}
+
+// clientActivityRepository implementa a interface ClientActivityRepository
+type clientActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewClientActivityRepository cria uma nova instância de ClientActivityRepository
+func NewClientActivityRepository(db *gorm.DB) ClientActivityRepository {
+	return &clientActivityRepository{
+		db: db,
+	}
+}
+
+// Create registra uma nova atividade
+func (r *clientActivityRepository) Create(activity *models.ClientActivity) error {
+	result := r.db.Create(activity)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao registrar atividade: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByID busca uma atividade do cliente pelo ID
+func (r *clientActivityRepository) GetByID(clientID, id uint) (*models.ClientActivity, error) {
+	var activity models.ClientActivity
+	result := r.db.Where("client_id = ?", clientID).First(&activity, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar atividade: %w", result.Error)
+	}
+	return &activity, nil
+}
+
+// Update atualiza uma atividade
+func (r *clientActivityRepository) Update(activity *models.ClientActivity) error {
+	result := r.db.Save(activity)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao atualizar atividade: %w", result.Error)
+	}
+	return nil
+}
+
+// Delete remove uma atividade do cliente (soft delete). Retorna false se ela não existir.
+func (r *clientActivityRepository) Delete(clientID, id uint) (bool, error) {
+	result := r.db.Where("client_id = ?", clientID).Delete(&models.ClientActivity{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao remover atividade: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LinksBelongToClient verifica se a tarefa e o pagamento informados (quando houver) são do cliente do usuário
+func (r *clientActivityRepository) LinksBelongToClient(userID, clientID uint, taskID, paymentID *uint) (bool, error) {
+	links := []struct {
+		model interface{}
+		id    *uint
+	}{
+		{&models.Task{}, taskID},
+		{&models.Payment{}, paymentID},
+	}
+
+	for _, link := range links {
+		if link.id == nil {
+			continue
+		}
+		var count int64
+		result := r.db.Model(link.model).
+			Where("id = ? AND user_id = ? AND client_id = ?", *link.id, userID, clientID).
+			Count(&count)
+		if result.Error != nil {
+			return false, fmt.Errorf("erro ao verificar vínculos da atividade: %w", result.Error)
+		}
+		if count == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// timelineQuery une as atividades registradas aos eventos das tarefas e dos pagamentos do cliente.
+// Um pagamento aparece como vencido se estiver em atraso ou se tiver sido pago depois do vencimento.
+const timelineQuery = `
+SELECT 'activity' AS source, a.type AS kind, a.id, a.occurred_at, '' AS title, a.body,
+       a.task_id, a.payment_id, NULL::numeric AS amount, '' AS currency
+  FROM client_activities a
+ WHERE a.client_id = @client AND a.user_id = @user AND a.deleted_at IS NULL
+UNION ALL
+SELECT 'task', 'task_created', t.id, t.created_at, t.title, '', t.id, NULL, NULL, ''
+  FROM tasks t
+ WHERE t.client_id = @client AND t.user_id = @user AND t.deleted_at IS NULL
+UNION ALL
+SELECT 'task', 'task_completed', t.id, COALESCE(t.end_date, t.updated_at), t.title, '', t.id, NULL, NULL, ''
+  FROM tasks t
+ WHERE t.client_id = @client AND t.user_id = @user AND t.deleted_at IS NULL AND t.status = 'completed'
+UNION ALL
+SELECT 'payment', 'payment_received', p.id, p.paid_date, COALESCE(NULLIF(p.description, ''), p.invoice_number), '',
+       p.task_id, p.id, p.amount, p.currency
+  FROM payments p
+ WHERE p.client_id = @client AND p.user_id = @user AND p.deleted_at IS NULL
+   AND p.status = 'paid' AND p.paid_date IS NOT NULL
+UNION ALL
+SELECT 'payment', 'payment_overdue', p.id, p.due_date, COALESCE(NULLIF(p.description, ''), p.invoice_number), '',
+       p.task_id, p.id, p.amount, p.currency
+  FROM payments p
+ WHERE p.client_id = @client AND p.user_id = @user AND p.deleted_at IS NULL
+   AND (p.status = 'overdue' OR (p.status = 'pending' AND p.due_date < @now) OR (p.status = 'paid' AND p.paid_date > p.due_date))`
+
+// Timeline retorna a linha do tempo do cliente, do item mais recente para o mais antigo.
+// Com cursor, começa no item seguinte ao que ele identifica.
+func (r *clientActivityRepository) Timeline(userID, clientID uint, cursor *TimelineCursor, limit int) ([]TimelineEntry, error) {
+	args := map[string]interface{}{
+		"client": clientID,
+		"user":   userID,
+		"now":    time.Now(),
+		"limit":  limit,
+	}
+
+	where := ""
+	if cursor != nil {
+		// Desempata pelo tipo e pelo ID para que itens no mesmo instante não se repitam nem se percam entre as páginas
+		where = "WHERE (occurred_at, kind, id) < (@at, @kind, @id)"
+		args["at"] = cursor.OccurredAt
+		args["kind"] = cursor.Kind
+		args["id"] = cursor.ID
+	}
+
+	var entries []TimelineEntry
+	result := r.db.Raw(
+		"SELECT * FROM ("+timelineQuery+") timeline "+where+" ORDER BY occurred_at DESC, kind DESC, id DESC LIMIT @limit",
+		args,
+	).Scan(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao montar linha do tempo: %w", result.Error)
+	}
+	return entries, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// ClientContactRepository define a interface para operações de repositório de contatos de clientes
+type ClientContactRepository interface {
+	Create(contact *models.ClientContact) error
+	GetByID(clientID, id uint) (*models.ClientContact, error)
+	ListByClient(clientID uint) ([]models.ClientContact, error)
+	GetBillingContact(clientID uint) (*models.ClientContact, error)
+	Update(contact *models.ClientContact) error
+	Delete(clientID, id uint) (bool, error)
+}
+
+// clientContactRepository implementa a interface ClientContactRepository
+type clientContactRepository struct {
+	db *gorm.DB
+}
+
+// NewClientContactRepository cria uma nova instância de ClientContactRepository
+func NewClientContactRepository(db *gorm.DB) ClientContactRepository {
+	return &clientContactRepository{
+		db: db,
+	}
+}
+
+// Create cria um novo contato. Se ele for o principal ou o de cobrança, o contato que tinha
+// essa marcação no mesmo cliente deixa de tê-la, na mesma transação.
+func (r *clientContactRepository) Create(contact *models.ClientContact) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearContactFlags(tx, contact); err != nil {
+			return err
+		}
+		if err := tx.Create(contact).Error; err != nil {
+			if isDuplicateKey(err) {
+				return models.ErrDuplicateKey
+			}
+			return fmt.Errorf("erro ao criar contato: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID busca um contato do cliente pelo ID
+func (r *clientContactRepository) GetByID(clientID, id uint) (*models.ClientContact, error) {
+	var contact models.ClientContact
+	result := r.db.Where("client_id = ?", clientID).First(&contact, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar contato: %w", result.Error)
+	}
+	return &contact, nil
+}
+
+// ListByClient retorna os contatos do cliente, com o principal e o de cobrança primeiro
+func (r *clientContactRepository) ListByClient(clientID uint) ([]models.ClientContact, error) {
+	var contacts []models.ClientContact
+	result := r.db.Where("client_id = ?", clientID).
+		Order("is_primary DESC, is_billing DESC, name ASC, id ASC").
+		Find(&contacts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar contatos: %w", result.Error)
+	}
+	return contacts, nil
+}
+
+// GetBillingContact retorna o contato de cobrança do cliente
+func (r *clientContactRepository) GetBillingContact(clientID uint) (*models.ClientContact, error) {
+	var contact models.ClientContact
+	result := r.db.Where("client_id = ? AND is_billing = ?", clientID, true).First(&contact)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar contato de cobrança: %w", result.Error)
+	}
+	return &contact, nil
+}
+
+// Update atualiza um contato, mantendo um único contato principal e um único de cobrança por cliente
+func (r *clientContactRepository) Update(contact *models.ClientContact) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearContactFlags(tx, contact); err != nil {
+			return err
+		}
+		if err := tx.Save(contact).Error; err != nil {
+			if isDuplicateKey(err) {
+				return models.ErrDuplicateKey
+			}
+			return fmt.Errorf("erro ao atualizar contato: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete remove um contato do cliente (soft delete). Retorna false se ele não existir.
+func (r *clientContactRepository) Delete(clientID, id uint) (bool, error) {
+	result := r.db.Where("client_id = ?", clientID).Delete(&models.ClientContact{}, id)
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao remover contato: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// clearContactFlags retira dos demais contatos do cliente as marcações de principal e de cobrança
+// que o contato informado vai assumir. Se outra transação marcar um contato do mesmo cliente ao mesmo
+// tempo, os índices únicos parciais recusam a segunda gravação com models.ErrDuplicateKey.
+func clearContactFlags(tx *gorm.DB, contact *models.ClientContact) error {
+	for column, set := range map[string]bool{"is_primary": contact.IsPrimary, "is_billing": contact.IsBilling} {
+		if !set {
+			continue
+		}
+		result := tx.Model(&models.ClientContact{}).
+			Where("client_id = ? AND id <> ? AND "+column+" = ?", contact.ClientID, contact.ID, true).
+			Update(column, false)
+		if result.Error != nil {
+			return fmt.Errorf("erro ao atualizar contatos do cliente: %w", result.Error)
+		}
+	}
+	return nil
+}
@@ -3,18 +3,83 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jpcode092/crm-freela/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ClientFilter representa os filtros e a ordenação da busca de clientes
+type ClientFilter struct {
+	Query   string // busca textual em nome, e-mail, empresa e observações, sem distinção de acentos
+	Status  models.ClientStatus
+	Company string // busca parcial pela empresa
+	TagIDs  []uint // clientes com todas as tags informadas
+	Sort    string // name, company, created_at, updated_at ou relevance
+	Order   string // asc ou desc
+	// Sem Status, os clientes arquivados só são incluídos se IncludeArchived for verdadeiro
+	IncludeArchived bool
+}
+
+// ClientSearchResult representa um cliente encontrado na busca, com a relevância e os trechos destacados
+type ClientSearchResult struct {
+	models.Client
+	Rank       float64           `json:"rank,omitempty"`
+	Highlights map[string]string `json:"highlights,omitempty"` // campo -> trecho em HTML com os termos em <mark>
+}
+
+// ClientTrashEntry representa um cliente removido, na lixeira
+type ClientTrashEntry struct {
+	models.Client
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// openTaskStatuses são os status das tarefas ainda em aberto, canceladas ao arquivar o cliente
+var openTaskStatuses = []models.TaskStatus{models.TaskTodo, models.TaskInProgress, models.TaskReview}
+
+// clientDependents são os registros que pertencem a um cliente e o acompanham na remoção, na restauração
+// e na exclusão definitiva, na ordem em que podem ser apagados
+var clientDependents = []interface{}{
+	&models.ClientActivity{},
+	&models.Payment{},
+	&models.Task{},
+	&models.ClientContact{},
+}
+
+// ClientMergeCounts representa quantos registros do cliente mesclado foram transferidos para o cliente de destino
+type ClientMergeCounts struct {
+	Tasks      int64 `json:"tasks"`
+	Payments   int64 `json:"payments"`
+	Contacts   int64 `json:"contacts"`
+	Activities int64 `json:"activities"`
+}
+
+// ClientImportPlan escolhe, dentro da transação da importação, os clientes a criar a partir dos clientes
+// já cadastrados com os mesmos e-mails ou empresas
+type ClientImportPlan func(existing []models.Client) ([]*models.Client, error)
+
 // ClientRepository define a interface para operações de repositório de clientes
 type ClientRepository interface {
 	Create(client *models.Client) error
+	Import(userID uint, emails, companies []string, plan ClientImportPlan) ([]*models.Client, error)
 	GetByID(id uint) (*models.Client, error)
 	GetByUserID(userID uint, page, pageSize int) ([]models.Client, int64, error)
+	AllByUser(userID uint) ([]models.Client, error)
+	Search(userID uint, filter ClientFilter, page, pageSize int) ([]ClientSearchResult, int64, error)
+	Export(userID uint, filter ClientFilter, fn func(*models.Client) error) error
 	Update(client *models.Client) error
+	Merge(target *models.Client, sourceID uint, activity *models.ClientActivity) (*ClientMergeCounts, error)
+	UpdateStatus(client *models.Client, cancelOpenTasks bool) (int64, error)
+	CountOpenTasks(clientID uint) (int64, error)
 	Delete(id uint) error
+	Trash(userID uint, page, pageSize int) ([]ClientTrashEntry, int64, error)
+	GetDeletedByID(id uint) (*models.Client, error)
+	Restore(client *models.Client) error
+	Purge(id uint) error
 	List(page, pageSize int) ([]models.Client, int64, error)
 	CountByUser(userID uint) (int64, error)
 }
@@ -40,13 +105,70 @@ func (r *clientRepository) Create(client *models.Client) error {
 	return nil
 }
 
+// Import cria os clientes de uma importação em uma única transação, com a linha do usuário bloqueada:
+// busca os clientes com os e-mails ou as empresas informados, deixa plan escolher os que serão criados
+// e cria todos ou nenhum. Importações simultâneas do mesmo usuário esperam umas pelas outras, de modo que
+// a verificação de duplicados e a do limite do plano, feita por plan, valem para o que é gravado.
+func (r *clientRepository) Import(userID uint, emails, companies []string, plan ClientImportPlan) ([]*models.Client, error) {
+	var clients []*models.Client
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error; err != nil {
+			return fmt.Errorf("erro ao bloquear usuário da importação: %w", err)
+		}
+
+		existing, err := findDuplicates(tx, userID, emails, companies)
+		if err != nil {
+			return err
+		}
+
+		clients, err = plan(existing)
+		if err != nil || len(clients) == 0 {
+			return err
+		}
+
+		if err := tx.CreateInBatches(clients, 100).Error; err != nil {
+			return fmt.Errorf("erro ao importar clientes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// findDuplicates busca os clientes do usuário com algum dos e-mails ou das empresas informados,
+// sem distinção de maiúsculas
+func findDuplicates(db *gorm.DB, userID uint, emails, companies []string) ([]models.Client, error) {
+	var clients []models.Client
+	if len(emails) == 0 && len(companies) == 0 {
+		return clients, nil
+	}
+
+	query := db.Where("user_id = ?", userID)
+	switch {
+	case len(emails) > 0 && len(companies) > 0:
+		query = query.Where("LOWER(email) IN ? OR LOWER(company) IN ?", emails, companies)
+	case len(emails) > 0:
+		query = query.Where("LOWER(email) IN ?", emails)
+	default:
+		query = query.Where("LOWER(company) IN ?", companies)
+	}
+
+	if err := query.Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar clientes duplicados: %w", err)
+	}
+	return clients, nil
+}
+
 // GetByID busca um cliente pelo ID
 func (r *clientRepository) GetByID(id uint) (*models.Client, error) {
 	var client models.Client
-	result := r.db.First(&client, id)
+	result := r.db.Preload("Tags").First(&client, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("cliente com ID %d não encontrado", id)
+			return nil, fmt.Errorf("cliente com ID %d não encontrado: %w", id, models.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("erro ao buscar cliente: %w", result.Error)
 	}
@@ -74,22 +196,475 @@ func (r *clientRepository) GetByUserID(userID uint, page, pageSize int) ([]model
 	return clients, total, nil
 }
 
-// Update atualiza um cliente existente
-func (r *clientRepository) Update(client *models.Client) error {
-	result := r.db.Save(client)
+// clientSortColumns mapeia as ordenações aceitas para as expressões SQL correspondentes
+var clientSortColumns = map[string]string{
+	"name":       "LOWER(name)",
+	"company":    "LOWER(company)",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// Delimitadores dos termos destacados por ts_headline; são trocados por <mark> depois de escapar o texto
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// clientSearchRow recebe o cliente com a relevância e os trechos calculados pelo Postgres
+type clientSearchRow struct {
+	models.Client
+	Rank             float64
+	NameHighlight    string
+	CompanyHighlight string
+	NotesHighlight   string
+}
+
+// AllByUser retorna todos os clientes do usuário, sem tags nem paginação, do mais antigo para o mais recente
+func (r *clientRepository) AllByUser(userID uint) ([]models.Client, error) {
+	var clients []models.Client
+	if err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("erro ao listar clientes do usuário: %w", err)
+	}
+	return clients, nil
+}
+
+// Search busca os clientes do usuário que atendem aos filtros.
+// Com busca textual, os resultados são ordenados por relevância, salvo outra ordenação informada.
+func (r *clientRepository) Search(userID uint, filter ClientFilter, page, pageSize int) ([]ClientSearchResult, int64, error) {
+	var rows []clientSearchRow
+	var total int64
+
+	query, tsQuery := r.filteredQuery(userID, filter)
+
+	// Conta o total de registros
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar clientes: %w", err)
+	}
+
+	if tsQuery != "" {
+		fieldOptions := fmt.Sprintf(`StartSel="%s", StopSel="%s", HighlightAll=true`, highlightStart, highlightStop)
+		notesOptions := fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`, highlightStart, highlightStop)
+		query = query.Select(
+			"clients.*, "+
+				"ts_rank(search_vector, to_tsquery('crm_portuguese', @q)) AS rank, "+
+				"ts_headline('crm_portuguese', name, to_tsquery('crm_portuguese', @q), @field) AS name_highlight, "+
+				"ts_headline('crm_portuguese', company, to_tsquery('crm_portuguese', @q), @field) AS company_highlight, "+
+				"ts_headline('crm_portuguese', notes, to_tsquery('crm_portuguese', @q), @notes) AS notes_highlight",
+			map[string]interface{}{"q": tsQuery, "field": fieldOptions, "notes": notesOptions},
+		)
+	}
+
+	// Calcula o offset para paginação
+	offset := (page - 1) * pageSize
+
+	// Busca os clientes com paginação
+	result := query.Order(clientOrder(filter, tsQuery != "")).Offset(offset).Limit(pageSize).Find(&rows)
 	if result.Error != nil {
-		return fmt.Errorf("erro ao atualizar cliente: %w", result.Error)
+		return nil, 0, fmt.Errorf("erro ao buscar clientes: %w", result.Error)
+	}
+
+	tags, err := r.tagsByClient(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	clients := make([]ClientSearchResult, 0, len(rows))
+	for i := range rows {
+		rows[i].Tags = tags[rows[i].ID]
+		clients = append(clients, rows[i].result())
+	}
+
+	return clients, total, nil
+}
+
+// Export percorre os clientes do usuário que atendem aos filtros, na ordem da busca, chamando fn para cada um.
+// Os registros são lidos um a um do cursor do banco, sem carregar o resultado inteiro em memória.
+func (r *clientRepository) Export(userID uint, filter ClientFilter, fn func(*models.Client) error) error {
+	query, tsQuery := r.filteredQuery(userID, filter)
+	if tsQuery != "" {
+		query = query.Select("clients.*, ts_rank(search_vector, to_tsquery('crm_portuguese', ?)) AS rank", tsQuery)
+	}
+
+	rows, err := query.Order(clientOrder(filter, tsQuery != "")).Rows()
+	if err != nil {
+		return fmt.Errorf("erro ao exportar clientes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var client models.Client
+		if err := r.db.ScanRows(rows, &client); err != nil {
+			return fmt.Errorf("erro ao exportar clientes: %w", err)
+		}
+		if err := fn(&client); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao exportar clientes: %w", err)
 	}
 	return nil
 }
 
-// Delete remove um cliente pelo ID (soft delete)
+// filteredQuery monta a consulta dos clientes do usuário com os filtros da busca e retorna também
+// a consulta textual normalizada (vazia se não houver busca textual)
+func (r *clientRepository) filteredQuery(userID uint, filter ClientFilter) (*gorm.DB, string) {
+	query := r.db.Model(&models.Client{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else if !filter.IncludeArchived {
+		query = query.Where("status <> ?", models.ClientArchived)
+	}
+	if company := strings.TrimSpace(filter.Company); company != "" {
+		query = query.Where("unaccent(company) ILIKE unaccent(?)", "%"+escapeLike(company)+"%")
+	}
+
+	if len(filter.TagIDs) > 0 {
+		query = query.Where(
+			"id IN (SELECT client_id FROM client_tags WHERE tag_id IN ? GROUP BY client_id HAVING COUNT(DISTINCT tag_id) = ?)",
+			filter.TagIDs, len(filter.TagIDs),
+		)
+	}
+
+	tsQuery := clientTSQuery(filter.Query)
+	if tsQuery != "" {
+		query = query.Where("search_vector @@ to_tsquery('crm_portuguese', ?)", tsQuery)
+	}
+	return query, tsQuery
+}
+
+// tagsByClient carrega as tags dos clientes da página em uma única consulta
+func (r *clientRepository) tagsByClient(rows []clientSearchRow) (map[uint][]models.Tag, error) {
+	tags := make(map[uint][]models.Tag, len(rows))
+	if len(rows) == 0 {
+		return tags, nil
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for i := range rows {
+		ids = append(ids, rows[i].ID)
+	}
+
+	var clients []models.Client
+	if err := r.db.Select("id").Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("LOWER(name) ASC")
+	}).Where("id IN ?", ids).Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar tags dos clientes: %w", err)
+	}
+
+	for _, client := range clients {
+		tags[client.ID] = client.Tags
+	}
+	return tags, nil
+}
+
+// result converte a linha da busca, mantendo apenas os trechos em que algum termo foi encontrado
+func (row *clientSearchRow) result() ClientSearchResult {
+	highlights := make(map[string]string)
+	for field, snippet := range map[string]string{
+		"name":    row.NameHighlight,
+		"company": row.CompanyHighlight,
+		"notes":   row.NotesHighlight,
+	} {
+		if strings.Contains(snippet, highlightStart) {
+			highlights[field] = highlightHTML(snippet)
+		}
+	}
+	if len(highlights) == 0 {
+		highlights = nil
+	}
+
+	return ClientSearchResult{Client: row.Client, Rank: row.Rank, Highlights: highlights}
+}
+
+// clientOrder monta a ordenação da busca a partir de valores conhecidos, nunca do texto da requisição.
+// O ID desempata a ordenação, para que a paginação seja estável.
+func clientOrder(filter ClientFilter, textSearch bool) string {
+	sort := filter.Sort
+	if sort == "" || (sort == "relevance" && !textSearch) {
+		if textSearch {
+			sort = "relevance"
+		} else {
+			sort = "created_at"
+		}
+	}
+
+	direction := "DESC"
+	if sort == "name" || sort == "company" {
+		direction = "ASC"
+	}
+	switch strings.ToLower(filter.Order) {
+	case "asc":
+		direction = "ASC"
+	case "desc":
+		direction = "DESC"
+	}
+
+	if sort == "relevance" {
+		return "rank " + direction + ", id DESC"
+	}
+
+	column, ok := clientSortColumns[sort]
+	if !ok {
+		column = "created_at"
+	}
+	return column + " " + direction + ", id " + direction
+}
+
+// clientTSQuery converte a busca do usuário em uma tsquery de prefixos ("joao:* & silva:*"),
+// para que os clientes apareçam enquanto o nome é digitado. Só letras e dígitos são mantidos,
+// então o resultado nunca contém operadores da sintaxe de tsquery.
+func clientTSQuery(search string) string {
+	terms := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > 8 {
+		terms = terms[:8]
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// highlightHTML escapa o trecho e troca os delimitadores de ts_headline por <mark>
+func highlightHTML(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
+
+// escapeLike escapa os curingas do LIKE no texto informado pelo usuário
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// MigrateClientSearch cria a estrutura da busca textual de clientes, que o AutoMigrate não cobre:
+// a configuração crm_portuguese (português sem acentos, via unaccent), a coluna gerada
+// search_vector e o índice GIN. Nome pesa mais que empresa e e-mail, que pesam mais que as observações.
+// O e-mail é quebrado em partes para que "joao" encontre "joao.silva@empresa.com".
+func MigrateClientSearch(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS unaccent",
+		`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'crm_portuguese') THEN
+		CREATE TEXT SEARCH CONFIGURATION crm_portuguese (COPY = portuguese);
+		ALTER TEXT SEARCH CONFIGURATION crm_portuguese
+			ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+	END IF;
+END
+$$`,
+		`ALTER TABLE clients ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('crm_portuguese', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('crm_portuguese', coalesce(company, '')), 'B') ||
+	setweight(to_tsvector('crm_portuguese', regexp_replace(coalesce(email, ''), '[@._+-]+', ' ', 'g')), 'B') ||
+	setweight(to_tsvector('crm_portuguese', coalesce(notes, '')), 'C')
+) STORED`,
+		"CREATE INDEX IF NOT EXISTS idx_clients_search_vector ON clients USING GIN (search_vector)",
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("erro ao criar a busca de clientes: %w", err)
+		}
+	}
+	return nil
+}
+
+// Update atualiza um cliente existente e substitui as suas tags pelas de client.Tags
+func (r *clientRepository) Update(client *models.Client) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags").Save(client).Error; err != nil {
+			return fmt.Errorf("erro ao atualizar cliente: %w", err)
+		}
+		if err := tx.Model(client).Association("Tags").Replace(client.Tags); err != nil {
+			return fmt.Errorf("erro ao atualizar tags do cliente: %w", err)
+		}
+		return nil
+	})
+}
+
+// Merge transfere para target as tarefas, os pagamentos, os contatos e as atividades do cliente sourceID,
+// salva target com as suas tags, registra activity e remove o cliente de origem (soft delete),
+// tudo em uma única transação
+func (r *clientRepository) Merge(target *models.Client, sourceID uint, activity *models.ClientActivity) (*ClientMergeCounts, error) {
+	counts := &ClientMergeCounts{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Um cliente tem no máximo um contato principal e um de cobrança: os do cliente de destino prevalecem
+		for _, column := range []string{"is_primary", "is_billing"} {
+			targetHasFlag := tx.Model(&models.ClientContact{}).Select("1").Where("client_id = ? AND "+column+" = ?", target.ID, true)
+			err := tx.Model(&models.ClientContact{}).
+				Where("client_id = ? AND "+column+" = ?", sourceID, true).
+				Where("EXISTS (?)", targetHasFlag).
+				Update(column, false).Error
+			if err != nil {
+				return fmt.Errorf("erro ao ajustar contatos do cliente mesclado: %w", err)
+			}
+		}
+
+		// Os registros removidos também são transferidos, para que nada fique apontando para o cliente de origem
+		moves := []struct {
+			model interface{}
+			count *int64
+		}{
+			{&models.Task{}, &counts.Tasks},
+			{&models.Payment{}, &counts.Payments},
+			{&models.ClientContact{}, &counts.Contacts},
+			{&models.ClientActivity{}, &counts.Activities},
+		}
+		for _, move := range moves {
+			result := tx.Unscoped().Model(move.model).Where("client_id = ?", sourceID).Update("client_id", target.ID)
+			if result.Error != nil {
+				return fmt.Errorf("erro ao transferir registros do cliente mesclado: %w", result.Error)
+			}
+			*move.count = result.RowsAffected
+		}
+
+		if err := tx.Omit("Tags").Save(target).Error; err != nil {
+			return fmt.Errorf("erro ao atualizar cliente: %w", err)
+		}
+		if err := tx.Model(target).Association("Tags").Replace(target.Tags); err != nil {
+			return fmt.Errorf("erro ao atualizar tags do cliente: %w", err)
+		}
+		if err := tx.Delete(&models.Client{}, sourceID).Error; err != nil {
+			return fmt.Errorf("erro ao remover cliente mesclado: %w", err)
+		}
+		if err := tx.Create(activity).Error; err != nil {
+			return fmt.Errorf("erro ao registrar mesclagem: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// UpdateStatus grava o status do cliente e, se cancelOpenTasks for verdadeiro, cancela as suas tarefas em aberto
+// na mesma transação. Retorna o número de tarefas canceladas.
+func (r *clientRepository) UpdateStatus(client *models.Client, cancelOpenTasks bool) (int64, error) {
+	var cancelled int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if cancelOpenTasks {
+			result := tx.Model(&models.Task{}).
+				Where("client_id = ? AND status IN ?", client.ID, openTaskStatuses).
+				Update("status", models.TaskCancelled)
+			if result.Error != nil {
+				return fmt.Errorf("erro ao cancelar tarefas do cliente: %w", result.Error)
+			}
+			cancelled = result.RowsAffected
+		}
+		if err := tx.Model(client).Update("status", client.Status).Error; err != nil {
+			return fmt.Errorf("erro ao atualizar status do cliente: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return cancelled, nil
+}
+
+// CountOpenTasks conta as tarefas em aberto do cliente
+func (r *clientRepository) CountOpenTasks(clientID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Task{}).Where("client_id = ? AND status IN ?", clientID, openTaskStatuses).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar tarefas em aberto do cliente: %w", err)
+	}
+	return count, nil
+}
+
+// Delete remove um cliente pelo ID (soft delete) junto com as suas tarefas, pagamentos, contatos e atividades.
+// Todos recebem o mesmo instante de remoção, que Restore usa para trazer de volta só o que saiu junto com o cliente.
 func (r *clientRepository) Delete(id uint) error {
-	result := r.db.Delete(&models.Client{}, id)
+	// O Postgres guarda microssegundos; o instante é truncado para coincidir com o lido de volta
+	deletedAt := time.Now().Truncate(time.Microsecond)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range clientDependents {
+			if err := tx.Model(model).Where("client_id = ?", id).Update("deleted_at", deletedAt).Error; err != nil {
+				return fmt.Errorf("erro ao remover registros do cliente: %w", err)
+			}
+		}
+		if err := tx.Model(&models.Client{}).Where("id = ?", id).Update("deleted_at", deletedAt).Error; err != nil {
+			return fmt.Errorf("erro ao remover cliente: %w", err)
+		}
+		return nil
+	})
+}
+
+// Trash retorna os clientes removidos do usuário com paginação, do removido mais recentemente para o mais antigo
+func (r *clientRepository) Trash(userID uint, page, pageSize int) ([]ClientTrashEntry, int64, error) {
+	var clients []models.Client
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Client{}).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar clientes removidos: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("deleted_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&clients).Error; err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar clientes removidos: %w", err)
+	}
+
+	entries := make([]ClientTrashEntry, 0, len(clients))
+	for _, client := range clients {
+		entries = append(entries, ClientTrashEntry{Client: client, DeletedAt: client.DeletedAt.Time})
+	}
+	return entries, total, nil
+}
+
+// GetDeletedByID busca um cliente removido (soft delete) pelo ID
+func (r *clientRepository) GetDeletedByID(id uint) (*models.Client, error) {
+	var client models.Client
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&client, id)
 	if result.Error != nil {
-		return fmt.Errorf("erro ao remover cliente: %w", result.Error)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("cliente removido com ID %d não encontrado: %w", id, models.ErrRecordNotFound)
+		}
+		return nil, fmt.Errorf("erro ao buscar cliente removido: %w", result.Error)
 	}
-	return nil
+	return &client, nil
+}
+
+// Restore traz de volta um cliente removido e os registros removidos junto com ele
+func (r *clientRepository) Restore(client *models.Client) error {
+	deletedAt := client.DeletedAt.Time
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range clientDependents {
+			err := tx.Unscoped().Model(model).
+				Where("client_id = ? AND deleted_at = ?", client.ID, deletedAt).
+				Update("deleted_at", nil).Error
+			if err != nil {
+				return fmt.Errorf("erro ao restaurar registros do cliente: %w", err)
+			}
+		}
+		if err := tx.Unscoped().Model(&models.Client{}).Where("id = ?", client.ID).Update("deleted_at", nil).Error; err != nil {
+			return fmt.Errorf("erro ao restaurar cliente: %w", err)
+		}
+		return nil
+	})
+}
+
+// Purge apaga definitivamente o cliente com as suas tarefas, pagamentos, contatos, atividades e tags
+func (r *clientRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range clientDependents {
+			if err := tx.Unscoped().Where("client_id = ?", id).Delete(model).Error; err != nil {
+				return fmt.Errorf("erro ao apagar registros do cliente: %w", err)
+			}
+		}
+		if err := tx.Exec("DELETE FROM client_tags WHERE client_id = ?", id).Error; err != nil {
+			return fmt.Errorf("erro ao apagar tags do cliente: %w", err)
+		}
+		if err := tx.Unscoped().Delete(&models.Client{}, id).Error; err != nil {
+			return fmt.Errorf("erro ao apagar cliente: %w", err)
+		}
+		return nil
+	})
 }
 
 // List retorna uma lista paginada de clientes
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomFieldRepository define a interface para operações de repositório de campos personalizados
+type CustomFieldRepository interface {
+	Create(field *models.CustomFieldDefinition) error
+	GetByID(userID, id uint) (*models.CustomFieldDefinition, error)
+	ListByUser(userID uint) ([]models.CustomFieldDefinition, error)
+	Update(field *models.CustomFieldDefinition, removedOptions []string) (int64, error)
+	Delete(field *models.CustomFieldDefinition) error
+}
+
+// customFieldRepository implementa a interface CustomFieldRepository
+type customFieldRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository cria uma nova instância de CustomFieldRepository
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepository{
+		db: db,
+	}
+}
+
+// Create cria um novo campo personalizado
+func (r *customFieldRepository) Create(field *models.CustomFieldDefinition) error {
+	result := r.db.Create(field)
+	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return models.ErrDuplicateKey
+		}
+		return fmt.Errorf("erro ao criar campo personalizado: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByID busca um campo personalizado do usuário pelo ID
+func (r *customFieldRepository) GetByID(userID, id uint) (*models.CustomFieldDefinition, error) {
+	var field models.CustomFieldDefinition
+	result := r.db.Where("user_id = ?", userID).First(&field, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar campo personalizado: %w", result.Error)
+	}
+	return &field, nil
+}
+
+// ListByUser retorna os campos personalizados do usuário na ordem de criação
+func (r *customFieldRepository) ListByUser(userID uint) ([]models.CustomFieldDefinition, error) {
+	var fields []models.CustomFieldDefinition
+	result := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&fields)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar campos personalizados: %w", result.Error)
+	}
+	return fields, nil
+}
+
+// Update atualiza um campo personalizado. Com removedOptions, a alteração só é gravada se nenhum cliente
+// do usuário (nem os da lixeira) tiver uma dessas opções como valor; retorna quantos clientes as usam.
+// A contagem e a gravação acontecem na mesma transação, com a definição do campo bloqueada.
+func (r *customFieldRepository) Update(field *models.CustomFieldDefinition, removedOptions []string) (int64, error) {
+	var inUse int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(removedOptions) > 0 {
+			var locked models.CustomFieldDefinition
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, field.ID).Error; err != nil {
+				return fmt.Errorf("erro ao bloquear campo personalizado: %w", err)
+			}
+
+			result := tx.Unscoped().Model(&models.Client{}).
+				Where("user_id = ? AND custom_fields ->> ? IN ?", field.UserID, field.Key, removedOptions).
+				Count(&inUse)
+			if result.Error != nil {
+				return fmt.Errorf("erro ao verificar uso das opções do campo personalizado: %w", result.Error)
+			}
+			if inUse > 0 {
+				return nil
+			}
+		}
+
+		if err := tx.Save(field).Error; err != nil {
+			return fmt.Errorf("erro ao atualizar campo personalizado: %w", err)
+		}
+		return nil
+	})
+	return inUse, err
+}
+
+// Delete remove o campo personalizado e o seu valor de todos os clientes do usuário, na mesma transação
+func (r *customFieldRepository) Delete(field *models.CustomFieldDefinition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(field).Error; err != nil {
+			return fmt.Errorf("erro ao remover campo personalizado: %w", err)
+		}
+
+		result := tx.Unscoped().Model(&models.Client{}).
+			Where("user_id = ?", field.UserID).
+			Update("custom_fields", gorm.Expr("custom_fields - ?", field.Key))
+		if result.Error != nil {
+			return fmt.Errorf("erro ao remover valores do campo personalizado: %w", result.Error)
+		}
+		return nil
+	})
+}
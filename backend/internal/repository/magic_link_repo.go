@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// MagicLinkRepository define a interface para operações de repositório de links de login
+type MagicLinkRepository interface {
+	Create(link *models.MagicLink) error
+	GetByTokenHash(tokenHash string) (*models.MagicLink, error)
+	MarkUsed(id uint) (bool, error)
+}
+
+// magicLinkRepository implementa a interface MagicLinkRepository
+type magicLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewMagicLinkRepository cria uma nova instância de MagicLinkRepository
+func NewMagicLinkRepository(db *gorm.DB) MagicLinkRepository {
+	return &magicLinkRepository{
+		db: db,
+	}
+}
+
+// Create registra um novo link de login
+func (r *magicLinkRepository) Create(link *models.MagicLink) error {
+	result := r.db.Create(link)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao criar link de login: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByTokenHash busca o link pelo hash do token
+func (r *magicLinkRepository) GetByTokenHash(tokenHash string) (*models.MagicLink, error) {
+	var link models.MagicLink
+	result := r.db.Where("token_hash = ?", tokenHash).First(&link)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar link de login: %w", result.Error)
+	}
+	return &link, nil
+}
+
+// MarkUsed marca o link como utilizado.
+// Retorna false se ele já tiver sido usado, o que torna o consumo atômico entre requisições simultâneas.
+func (r *magicLinkRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.MagicLink{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao marcar link de login como usado: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
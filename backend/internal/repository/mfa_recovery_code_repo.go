@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// MFARecoveryCodeRepository define a interface para operações de repositório de códigos de recuperação
+type MFARecoveryCodeRepository interface {
+	ReplaceForUser(userID uint, codeHashes []string) error
+	Consume(userID uint, codeHash string) (bool, error)
+	DeleteByUser(userID uint) error
+}
+
+// mfaRecoveryCodeRepository implementa a interface MFARecoveryCodeRepository
+type mfaRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARecoveryCodeRepository cria uma nova instância de MFARecoveryCodeRepository
+func NewMFARecoveryCodeRepository(db *gorm.DB) MFARecoveryCodeRepository {
+	return &mfaRecoveryCodeRepository{
+		db: db,
+	}
+}
+
+// ReplaceForUser substitui todos os códigos de recuperação do usuário pelos novos hashes
+func (r *mfaRecoveryCodeRepository) ReplaceForUser(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("erro ao remover códigos de recuperação: %w", err)
+		}
+
+		codes := make([]models.MFARecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.MFARecoveryCode{UserID: userID, CodeHash: hash})
+		}
+
+		if err := tx.Create(&codes).Error; err != nil {
+			return fmt.Errorf("erro ao criar códigos de recuperação: %w", err)
+		}
+		return nil
+	})
+}
+
+// Consume marca o código como utilizado. Retorna false se o código não existir ou já tiver sido usado.
+func (r *mfaRecoveryCodeRepository) Consume(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao consumir código de recuperação: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteByUser remove todos os códigos de recuperação do usuário
+func (r *mfaRecoveryCodeRepository) DeleteByUser(userID uint) error {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{})
+	if result.Error != nil {
+		return fmt.Errorf("erro ao remover códigos de recuperação: %w", result.Error)
+	}
+	return nil
+}
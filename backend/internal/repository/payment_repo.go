@@ -22,6 +22,7 @@ type PaymentRepository interface {
 	GetOverdue(userID uint) ([]models.Payment, error)
 	GetByStatus(userID uint, status models.PaymentStatus, page, pageSize int) ([]models.Payment, int64, error)
 	GetSummaryByPeriod(userID uint, startDate, endDate time.Time) (float64, error)
+	CountByUser(userID uint) (int64, error)
 }
 
 // paymentRepository implementa a interface PaymentRepository
@@ -247,3 +248,13 @@ func (r *paymentRepository) GetSummaryByPeriod(userID uint, startDate, endDate t
 
 	return total, nil
 }
+
+// CountByUser conta o número de pagamentos por usuário
+func (r *paymentRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Payment{}).Where("user_id = ?", userID).Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("erro ao contar pagamentos por usuário: %w", result.Error)
+	}
+	return count, nil
+}
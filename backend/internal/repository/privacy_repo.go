@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserData reúne os registros de negócio do usuário, incluindo os removidos (soft delete)
+type UserData struct {
+	Clients    []models.Client
+	Contacts   []models.ClientContact
+	Activities []models.ClientActivity
+	Tasks      []models.Task
+	Payments   []models.Payment
+}
+
+// PrivacyRepository define a interface para as operações de exportação e exclusão dos dados do usuário (LGPD)
+type PrivacyRepository interface {
+	CreateExport(export *models.DataExport) error
+	GetExportByTokenHash(tokenHash string) (*models.DataExport, error)
+	MarkExportDownloaded(id uint) error
+	DeleteExpiredExports(before time.Time) (int64, error)
+	LoadUserData(userID uint) (*UserData, error)
+	ListDueDeletions(before time.Time) ([]models.User, error)
+	EraseUser(userID uint, anonymized *models.User) error
+}
+
+// privacyRepository implementa a interface PrivacyRepository
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository cria uma nova instância de PrivacyRepository
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{
+		db: db,
+	}
+}
+
+// CreateExport grava o arquivo de exportação
+func (r *privacyRepository) CreateExport(export *models.DataExport) error {
+	result := r.db.Create(export)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao gravar exportação de dados: %w", result.Error)
+	}
+	return nil
+}
+
+// GetExportByTokenHash busca a exportação pelo hash do token do link
+func (r *privacyRepository) GetExportByTokenHash(tokenHash string) (*models.DataExport, error) {
+	var export models.DataExport
+	result := r.db.Where("token_hash = ?", tokenHash).First(&export)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação de dados: %w", result.Error)
+	}
+	return &export, nil
+}
+
+// MarkExportDownloaded registra o primeiro download da exportação
+func (r *privacyRepository) MarkExportDownloaded(id uint) error {
+	result := r.db.Model(&models.DataExport{}).
+		Where("id = ? AND downloaded_at IS NULL", id).
+		Update("downloaded_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("erro ao registrar download da exportação: %w", result.Error)
+	}
+	return nil
+}
+
+// DeleteExpiredExports remove os arquivos cujo link expirou antes de before
+func (r *privacyRepository) DeleteExpiredExports(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.DataExport{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("erro ao remover exportações expiradas: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// LoadUserData carrega clientes, contatos, atividades, tarefas e pagamentos do usuário, inclusive os removidos
+func (r *privacyRepository) LoadUserData(userID uint) (*UserData, error) {
+	var data UserData
+
+	if err := r.db.Unscoped().Preload("Tags").Where("user_id = ?", userID).Order("id").Find(&data.Clients).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar clientes: %w", err)
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("id").Find(&data.Contacts).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar contatos: %w", err)
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("id").Find(&data.Activities).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar atividades: %w", err)
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("id").Find(&data.Tasks).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar tarefas: %w", err)
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("id").Find(&data.Payments).Error; err != nil {
+		return nil, fmt.Errorf("erro ao carregar pagamentos: %w", err)
+	}
+
+	return &data, nil
+}
+
+// ListDueDeletions retorna os usuários cuja exclusão estava agendada para antes de before
+func (r *privacyRepository) ListDueDeletions(before time.Time) ([]models.User, error) {
+	var users []models.User
+	result := r.db.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", before).Find(&users)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar exclusões agendadas: %w", result.Error)
+	}
+	return users, nil
+}
+
+// EraseUser remove definitivamente os pagamentos, tarefas e clientes do usuário (inclusive os que
+// estavam em soft delete) e as credenciais de acesso, e substitui os dados pessoais da linha do usuário
+// pelos de anonymized. A linha do usuário é mantida, anonimizada e removida, para preservar as
+// referências da trilha de auditoria. Tudo ocorre em uma única transação.
+func (r *privacyRepository) EraseUser(userID uint, anonymized *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Os vínculos de tags não têm user_id e saem antes dos clientes
+		if err := tx.Exec("DELETE FROM client_tags WHERE client_id IN (SELECT id FROM clients WHERE user_id = ?)", userID).Error; err != nil {
+			return fmt.Errorf("erro ao remover dados do usuário %d: %w", userID, err)
+		}
+
+		// Ordem respeita as chaves estrangeiras: pagamentos referenciam tarefas e clientes
+		for _, model := range []interface{}{
+			&models.ClientActivity{},
+			&models.Payment{},
+			&models.Task{},
+			&models.ClientContact{},
+			&models.Client{},
+			&models.Tag{},
+			&models.CustomFieldDefinition{},
+			&models.Session{},
+			&models.MFARecoveryCode{},
+			&models.APIToken{},
+			&models.UserIdentity{},
+			&models.MagicLink{},
+			&models.WebAuthnCredential{},
+			&models.DataExport{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return fmt.Errorf("erro ao remover dados do usuário %d: %w", userID, err)
+			}
+		}
+
+		result := tx.Unscoped().Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"name":                    anonymized.Name,
+			"email":                   anonymized.Email,
+			"password":                anonymized.Password,
+			"status":                  anonymized.Status,
+			"pending_email":           nil,
+			"reset_token":             nil,
+			"mfa_enabled":             false,
+			"mfa_secret":              "",
+			"mfa_enabled_at":          nil,
+			"locked_until":            nil,
+			"password_login_disabled": true,
+			"deletion_scheduled_at":   nil,
+			"deleted_at":              time.Now(),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("erro ao anonimizar usuário %d: %w", userID, result.Error)
+		}
+		return nil
+	})
+}
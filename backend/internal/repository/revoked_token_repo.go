@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository define a interface para o armazenamento de access tokens revogados
+type RevokedTokenRepository interface {
+	Revoke(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+}
+
+// revokedTokenRepository implementa a interface RevokedTokenRepository sobre o Postgres
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRevokedTokenRepository cria uma nova instância de RevokedTokenRepository
+func NewRevokedTokenRepository(db *gorm.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{
+		db: db,
+	}
+}
+
+// Revoke registra o jti como revogado até a expiração do token
+func (r *revokedTokenRepository) Revoke(jti string, expiresAt time.Time) error {
+	token := &models.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao revogar token: %w", result.Error)
+	}
+
+	// Remove as entradas cujos tokens já expiraram naturalmente
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return fmt.Errorf("erro ao limpar tokens revogados expirados: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked verifica se o jti foi revogado
+func (r *revokedTokenRepository) IsRevoked(jti string) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.RevokedToken{}).
+		Where("jti = ? AND expires_at > ?", jti, time.Now()).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao verificar token revogado: %w", result.Error)
+	}
+	return count > 0, nil
+}
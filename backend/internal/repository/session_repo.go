@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository define a interface para operações de repositório de sessões (refresh tokens)
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByTokenHash(tokenHash string) (*models.Session, error)
+	ListByFamily(familyID string) ([]models.Session, error)
+	ListActiveByUser(userID uint) ([]models.Session, error)
+	ListIssuedSince(userID uint, since time.Time) ([]models.Session, error)
+	MarkRotated(id uint) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllByUser(userID uint) error
+}
+
+// sessionRepository implementa a interface SessionRepository
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository cria uma nova instância de SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+// Create cria uma nova sessão no banco de dados
+func (r *sessionRepository) Create(session *models.Session) error {
+	result := r.db.Create(session)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao criar sessão: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByTokenHash busca uma sessão pelo hash do refresh token
+func (r *sessionRepository) GetByTokenHash(tokenHash string) (*models.Session, error) {
+	var session models.Session
+	result := r.db.Where("token_hash = ?", tokenHash).First(&session)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar sessão: %w", result.Error)
+	}
+	return &session, nil
+}
+
+// ListByFamily retorna todos os refresh tokens de uma família de sessões
+func (r *sessionRepository) ListByFamily(familyID string) ([]models.Session, error) {
+	var sessions []models.Session
+	result := r.db.Where("family_id = ?", familyID).Order("created_at DESC").Find(&sessions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar família de sessões: %w", result.Error)
+	}
+	return sessions, nil
+}
+
+// ListActiveByUser retorna o refresh token vigente de cada sessão ativa do usuário
+func (r *sessionRepository) ListActiveByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	result := r.db.Where("user_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar sessões do usuário: %w", result.Error)
+	}
+	return sessions, nil
+}
+
+// ListIssuedSince retorna os refresh tokens emitidos para o usuário a partir de uma data
+func (r *sessionRepository) ListIssuedSince(userID uint, since time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	result := r.db.Where("user_id = ? AND created_at >= ?", userID, since).Find(&sessions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar sessões recentes do usuário: %w", result.Error)
+	}
+	return sessions, nil
+}
+
+// MarkRotated marca o refresh token como utilizado.
+// Retorna false se outro processo já o tiver rotacionado ou revogado.
+func (r *sessionRepository) MarkRotated(id uint) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao rotacionar sessão: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily revoga todos os refresh tokens de uma família de sessão
+func (r *sessionRepository) RevokeFamily(familyID string) error {
+	result := r.db.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("erro ao revogar família de sessões: %w", result.Error)
+	}
+	return nil
+}
+
+// RevokeAllByUser revoga todas as sessões de um usuário
+func (r *sessionRepository) RevokeAllByUser(userID uint) error {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("erro ao revogar sessões do usuário: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// TagRepository define a interface para operações de repositório de tags
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	GetByID(userID, id uint) (*models.Tag, error)
+	GetByIDs(userID uint, ids []uint) ([]models.Tag, error)
+	ListByUser(userID uint) ([]models.Tag, error)
+	Update(tag *models.Tag) error
+	Delete(userID, id uint) (bool, error)
+}
+
+// tagRepository implementa a interface TagRepository
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository cria uma nova instância de TagRepository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+// Create cria uma nova tag
+func (r *tagRepository) Create(tag *models.Tag) error {
+	result := r.db.Create(tag)
+	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return models.ErrDuplicateKey
+		}
+		return fmt.Errorf("erro ao criar tag: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByID busca uma tag do usuário pelo ID
+func (r *tagRepository) GetByID(userID, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.Where("user_id = ?", userID).First(&tag, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar tag: %w", result.Error)
+	}
+	return &tag, nil
+}
+
+// GetByIDs busca as tags do usuário com os IDs informados; IDs de outros usuários são ignorados
+func (r *tagRepository) GetByIDs(userID uint, ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	result := r.db.Where("user_id = ? AND id IN ?", userID, ids).Find(&tags)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao buscar tags: %w", result.Error)
+	}
+	return tags, nil
+}
+
+// ListByUser retorna as tags do usuário em ordem alfabética
+func (r *tagRepository) ListByUser(userID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	result := r.db.Where("user_id = ?", userID).Order("LOWER(name) ASC").Find(&tags)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar tags: %w", result.Error)
+	}
+	return tags, nil
+}
+
+// Update atualiza uma tag
+func (r *tagRepository) Update(tag *models.Tag) error {
+	result := r.db.Save(tag)
+	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return models.ErrDuplicateKey
+		}
+		return fmt.Errorf("erro ao atualizar tag: %w", result.Error)
+	}
+	return nil
+}
+
+// Delete remove a tag do usuário e os seus vínculos com clientes. Retorna false se ela não existir.
+func (r *tagRepository) Delete(userID, id uint) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("erro ao remover tag: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+
+		if err := tx.Exec("DELETE FROM client_tags WHERE tag_id = ?", id).Error; err != nil {
+			return fmt.Errorf("erro ao remover tag dos clientes: %w", err)
+		}
+		return nil
+	})
+	return deleted, err
+}
@@ -21,6 +21,7 @@ type TaskRepository interface {
 	GetUpcoming(userID uint, days int) ([]models.Task, error)
 	GetByStatus(userID uint, status models.TaskStatus, page, pageSize int) ([]models.Task, int64, error)
 	CountByUserAndStatus(userID uint, status models.TaskStatus) (int64, error)
+	CountByUser(userID uint) (int64, error)
 }
 
 // taskRepository implementa a interface TaskRepository
@@ -202,3 +203,13 @@ func (r *taskRepository) CountByUserAndStatus(userID uint, status models.TaskSta
 	}
 	return count, nil
 }
+
+// CountByUser conta o número de tarefas por usuário
+func (r *taskRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Task{}).Where("user_id = ?", userID).Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("erro ao contar tarefas por usuário: %w", result.Error)
+	}
+	return count, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository define a interface para operações de repositório de identidades externas
+type UserIdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	GetByProviderSubject(provider, subject string) (*models.UserIdentity, error)
+}
+
+// userIdentityRepository implementa a interface UserIdentityRepository
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository cria uma nova instância de UserIdentityRepository
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{
+		db: db,
+	}
+}
+
+// Create vincula uma identidade externa a um usuário
+func (r *userIdentityRepository) Create(identity *models.UserIdentity) error {
+	result := r.db.Create(identity)
+	if result.Error != nil {
+		return fmt.Errorf("erro ao vincular identidade externa: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByProviderSubject busca a identidade pelo emissor e pelo identificador do usuário no provedor
+func (r *userIdentityRepository) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	result := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar identidade externa: %w", result.Error)
+	}
+	return &identity, nil
+}
@@ -3,20 +3,39 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jpcode092/crm-freela/internal/models"
 	"gorm.io/gorm"
 )
 
+// pgUniqueViolation é o código do Postgres para violação de índice único
+const pgUniqueViolation = "23505"
+
+// UserFilter representa os filtros da busca de usuários
+type UserFilter struct {
+	Query  string // busca parcial por nome ou e-mail
+	Status models.UserStatus
+	Role   models.UserRole
+	Plan   models.PlanType
+}
+
 // UserRepository define a interface para operações de repositório de usuários
 type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id uint) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
-	GetByResetToken(token string) (*models.User, error)
+	GetByResetTokenHash(tokenHash string) (*models.User, error)
+	ConsumeResetToken(userID uint, tokenHash string) (bool, error)
+	ClaimMFAStep(userID uint, step int64) (bool, error)
+	RegisterFailedLogin(id uint, threshold int, base, max time.Duration) (int, error)
+	ResetFailedLogins(id uint) error
 	Update(user *models.User) error
 	Delete(id uint) error
 	List(page, pageSize int) ([]models.User, int64, error)
+	Search(filter UserFilter, page, pageSize int) ([]models.User, int64, error)
 	CountByPlan(plan models.PlanType) (int64, error)
 }
 
@@ -36,6 +55,9 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 func (r *userRepository) Create(user *models.User) error {
 	result := r.db.Create(user)
 	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return fmt.Errorf("erro ao criar usuário: %w", models.ErrDuplicateKey)
+		}
 		return fmt.Errorf("erro ao criar usuário: %w", result.Error)
 	}
 	return nil
@@ -47,7 +69,7 @@ func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	result := r.db.First(&user, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("usuário com ID %d não encontrado", id)
+			return nil, fmt.Errorf("usuário com ID %d não encontrado: %w", id, models.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", result.Error)
 	}
@@ -67,23 +89,94 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-// GetByResetToken busca um usuário pelo token de recuperação de senha
-func (r *userRepository) GetByResetToken(token string) (*models.User, error) {
+// GetByResetTokenHash busca um usuário pelo hash do token de recuperação de senha
+func (r *userRepository) GetByResetTokenHash(tokenHash string) (*models.User, error) {
 	var user models.User
-	result := r.db.Where("reset_token = ?", token).First(&user)
+	result := r.db.Where("reset_token = ?", tokenHash).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("token inválido ou expirado")
+			return nil, fmt.Errorf("token de recuperação não encontrado: %w", models.ErrRecordNotFound)
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", result.Error)
 	}
 	return &user, nil
 }
 
+// ConsumeResetToken invalida o token de recuperação do usuário.
+// Retorna false se o token já tiver sido usado ou substituído.
+func (r *userRepository) ConsumeResetToken(userID uint, tokenHash string) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND reset_token = ?", userID, tokenHash).
+		Update("reset_token", nil)
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao invalidar token de recuperação: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ClaimMFAStep registra a janela TOTP usada pelo usuário, desde que seja posterior à última aceita.
+// Retorna false se a janela já tiver sido usada, inclusive por uma verificação simultânea.
+func (r *userRepository) ClaimMFAStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND mfa_last_used_step < ?", userID, step).
+		Update("mfa_last_used_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao registrar janela do código de verificação: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// registerFailedLoginQuery incrementa o contador de falhas e, a partir de @threshold falhas, bloqueia a
+// conta por @base * 2^(falhas - @threshold) milissegundos, limitado a @max (mesma regra de lockoutDelay).
+// O expoente é limitado para que POWER não estoure antes do LEAST.
+const registerFailedLoginQuery = `
+UPDATE users
+   SET failed_login_attempts = failed_login_attempts + 1,
+       locked_until = CASE
+           WHEN @threshold > 0 AND failed_login_attempts + 1 >= @threshold
+           THEN NOW() + LEAST(@base * POWER(2, LEAST(failed_login_attempts + 1 - @threshold, 32)), @max) * INTERVAL '1 millisecond'
+           ELSE locked_until
+       END
+ WHERE id = @id AND deleted_at IS NULL
+RETURNING failed_login_attempts`
+
+// RegisterFailedLogin contabiliza uma falha de login do usuário em um único UPDATE, sem perder
+// incrementos de tentativas simultâneas. Retorna o total de falhas consecutivas após o incremento.
+func (r *userRepository) RegisterFailedLogin(id uint, threshold int, base, max time.Duration) (int, error) {
+	var attempts int
+	result := r.db.Raw(registerFailedLoginQuery, map[string]interface{}{
+		"id":        id,
+		"threshold": threshold,
+		"base":      base.Milliseconds(),
+		"max":       max.Milliseconds(),
+	}).Scan(&attempts)
+	if result.Error != nil {
+		return 0, fmt.Errorf("erro ao registrar falha de login: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, fmt.Errorf("usuário com ID %d não encontrado: %w", id, models.ErrRecordNotFound)
+	}
+	return attempts, nil
+}
+
+// ResetFailedLogins zera o contador de falhas de login e remove o bloqueio temporário do usuário
+func (r *userRepository) ResetFailedLogins(id uint) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"failed_login_attempts": 0, "locked_until": nil})
+	if result.Error != nil {
+		return fmt.Errorf("erro ao zerar falhas de login: %w", result.Error)
+	}
+	return nil
+}
+
 // Update atualiza um usuário existente
 func (r *userRepository) Update(user *models.User) error {
 	result := r.db.Save(user)
 	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return fmt.Errorf("erro ao atualizar usuário: %w", models.ErrDuplicateKey)
+		}
 		return fmt.Errorf("erro ao atualizar usuário: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
@@ -126,6 +219,43 @@ func (r *userRepository) List(page, pageSize int) ([]models.User, int64, error)
 	return users, total, nil
 }
 
+// Search retorna uma lista paginada de usuários que atendem aos filtros
+func (r *userRepository) Search(filter UserFilter, page, pageSize int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+	if filter.Query != "" {
+		like := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", like, like)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Plan != "" {
+		query = query.Where("plan = ?", filter.Plan)
+	}
+
+	// Conta o total de registros
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar usuários: %w", err)
+	}
+
+	// Calcula o offset para paginação
+	offset := (page - 1) * pageSize
+
+	// Busca os usuários com paginação
+	result := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&users)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar usuários: %w", result.Error)
+	}
+
+	return users, total, nil
+}
+
 // CountByPlan conta o número de usuários por tipo de plano
 func (r *userRepository) CountByPlan(plan models.PlanType) (int64, error) {
 	var count int64
@@ -135,3 +265,9 @@ func (r *userRepository) CountByPlan(plan models.PlanType) (int64, error) {
 	}
 	return count, nil
 }
+
+// isDuplicateKey verifica se o erro do banco é uma violação de índice único (ex.: e-mail já cadastrado)
+func isDuplicateKey(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"gorm.io/gorm"
+)
+
+// WebAuthnCredentialRepository define a interface para operações de repositório de passkeys
+type WebAuthnCredentialRepository interface {
+	Create(credential *models.WebAuthnCredential) error
+	GetByCredentialID(credentialID []byte) (*models.WebAuthnCredential, error)
+	ListByUser(userID uint) ([]models.WebAuthnCredential, error)
+	UpdateSignCount(id uint, previous, signCount uint32, usedAt time.Time) (bool, error)
+	Delete(userID, id uint) (bool, error)
+}
+
+// webAuthnCredentialRepository implementa a interface WebAuthnCredentialRepository
+type webAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnCredentialRepository cria uma nova instância de WebAuthnCredentialRepository
+func NewWebAuthnCredentialRepository(db *gorm.DB) WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{
+		db: db,
+	}
+}
+
+// Create registra uma nova passkey
+func (r *webAuthnCredentialRepository) Create(credential *models.WebAuthnCredential) error {
+	result := r.db.Create(credential)
+	if result.Error != nil {
+		if isDuplicateKey(result.Error) {
+			return models.ErrDuplicateKey
+		}
+		return fmt.Errorf("erro ao criar passkey: %w", result.Error)
+	}
+	return nil
+}
+
+// GetByCredentialID busca a passkey pelo ID da credencial gerado pelo autenticador
+func (r *webAuthnCredentialRepository) GetByCredentialID(credentialID []byte) (*models.WebAuthnCredential, error) {
+	var credential models.WebAuthnCredential
+	result := r.db.Where("credential_id = ?", credentialID).First(&credential)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, models.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar passkey: %w", result.Error)
+	}
+	return &credential, nil
+}
+
+// ListByUser retorna as passkeys do usuário
+func (r *webAuthnCredentialRepository) ListByUser(userID uint) ([]models.WebAuthnCredential, error) {
+	var credentials []models.WebAuthnCredential
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&credentials)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao listar passkeys: %w", result.Error)
+	}
+	return credentials, nil
+}
+
+// UpdateSignCount grava o novo contador de assinaturas e o último uso.
+// Retorna false se o contador tiver mudado desde a leitura, o que impede que duas autenticações
+// simultâneas com a mesma assinatura sejam aceitas.
+func (r *webAuthnCredentialRepository) UpdateSignCount(id uint, previous, signCount uint32, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.WebAuthnCredential{}).
+		Where("id = ? AND sign_count = ?", id, previous).
+		Updates(map[string]interface{}{"sign_count": signCount, "last_used_at": usedAt})
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao atualizar passkey: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete remove uma passkey do usuário. Retorna false se ela não existir.
+func (r *webAuthnCredentialRepository) Delete(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.WebAuthnCredential{})
+	if result.Error != nil {
+		return false, fmt.Errorf("erro ao remover passkey: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
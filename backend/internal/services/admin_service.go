@@ -0,0 +1,272 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// UserStats representa um usuário com os totais de registros da sua conta
+type UserStats struct {
+	User     *models.User `json:"user"`
+	Clients  int64        `json:"clients"`
+	Tasks    int64        `json:"tasks"`
+	Payments int64        `json:"payments"`
+}
+
+// AdminService define a interface para as operações administrativas sobre usuários
+type AdminService interface {
+	ListUsers(filter repository.UserFilter, page, pageSize int) ([]models.User, int64, error)
+	GetUserStats(userID uint) (*UserStats, error)
+	SetUserStatus(ctx AuditContext, userID uint, status models.UserStatus) (*models.User, error)
+	ChangePlan(ctx AuditContext, userID uint, plan models.PlanType) (*models.User, error)
+	ChangeRole(ctx AuditContext, userID uint, role models.UserRole) (*models.User, error)
+	// StartImpersonation emite um token de acesso de suporte à conta do usuário, somente leitura salvo allowWrite
+	StartImpersonation(ctx AuditContext, userID uint, allowWrite bool, reason string) (*ImpersonationToken, error)
+	// EndImpersonation revoga um token de acesso de suporte emitido ao próprio administrador
+	EndImpersonation(ctx AuditContext, accessToken string) error
+}
+
+// adminService implementa a interface AdminService
+type adminService struct {
+	userRepo    repository.UserRepository
+	clientRepo  repository.ClientRepository
+	taskRepo    repository.TaskRepository
+	paymentRepo repository.PaymentRepository
+	authService AuthService
+	audit       AuditWriter
+	logger      logger.Logger
+}
+
+// NewAdminService cria uma nova instância de AdminService
+func NewAdminService(
+	userRepo repository.UserRepository,
+	clientRepo repository.ClientRepository,
+	taskRepo repository.TaskRepository,
+	paymentRepo repository.PaymentRepository,
+	authService AuthService,
+	audit AuditWriter,
+	logger logger.Logger,
+) AdminService {
+	return &adminService{
+		userRepo:    userRepo,
+		clientRepo:  clientRepo,
+		taskRepo:    taskRepo,
+		paymentRepo: paymentRepo,
+		authService: authService,
+		audit:       audit,
+		logger:      logger,
+	}
+}
+
+// ListUsers retorna uma lista paginada de usuários que atendem aos filtros
+func (s *adminService) ListUsers(filter repository.UserFilter, page, pageSize int) ([]models.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	return s.userRepo.Search(filter, page, pageSize)
+}
+
+// GetUserStats retorna o usuário com os totais de clientes, tarefas e pagamentos
+func (s *adminService) GetUserStats(userID uint) (*UserStats, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.clientRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserStats{
+		User:     user,
+		Clients:  clients,
+		Tasks:    tasks,
+		Payments: payments,
+	}, nil
+}
+
+// SetUserStatus bloqueia ou desbloqueia um usuário.
+// Ao bloquear, todas as sessões do usuário são revogadas.
+func (s *adminService) SetUserStatus(ctx AuditContext, userID uint, status models.UserStatus) (*models.User, error) {
+	if ctx.ActorID == userID {
+		return nil, apperrors.ErrSelfAdminAction
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := models.AuditAdminUserUnblocked
+	if status == models.UserStatusBlocked {
+		action = models.AuditAdminUserBlocked
+	}
+
+	previous := user.Status
+	user.Status = status
+	if err := s.userRepo.Update(user); err != nil {
+		event := newAuditEvent(ctx, action, userID, nil)
+		event.Outcome = models.AuditFailure
+		s.audit.Record(event)
+		return nil, err
+	}
+
+	if status == models.UserStatusBlocked {
+		if err := s.authService.RevokeAllSessions(userID); err != nil {
+			s.logger.Error(fmt.Sprintf("Erro ao revogar sessões do usuário bloqueado %d: %v", userID, err))
+		}
+	}
+
+	s.audit.Record(newAuditEvent(ctx, action, userID, map[string]interface{}{
+		"previous_status": previous,
+		"status":          status,
+	}))
+
+	return user, nil
+}
+
+// ChangePlan altera o plano de assinatura de um usuário
+func (s *adminService) ChangePlan(ctx AuditContext, userID uint, plan models.PlanType) (*models.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.Plan
+	user.Plan = plan
+	if err := s.userRepo.Update(user); err != nil {
+		event := newAuditEvent(ctx, models.AuditAdminPlanChanged, userID, nil)
+		event.Outcome = models.AuditFailure
+		s.audit.Record(event)
+		return nil, err
+	}
+
+	s.audit.Record(newAuditEvent(ctx, models.AuditAdminPlanChanged, userID, map[string]interface{}{
+		"previous_plan": previous,
+		"plan":          plan,
+	}))
+
+	return user, nil
+}
+
+// ChangeRole altera o papel de um usuário. Um administrador não pode alterar o próprio papel,
+// o que evita que o último administrador perca o acesso por engano.
+func (s *adminService) ChangeRole(ctx AuditContext, userID uint, role models.UserRole) (*models.User, error) {
+	if ctx.ActorID == userID {
+		return nil, apperrors.ErrSelfAdminAction
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := user.Role
+	user.Role = role
+	if err := s.userRepo.Update(user); err != nil {
+		event := newAuditEvent(ctx, models.AuditAdminRoleChanged, userID, nil)
+		event.Outcome = models.AuditFailure
+		s.audit.Record(event)
+		return nil, err
+	}
+
+	s.audit.Record(newAuditEvent(ctx, models.AuditAdminRoleChanged, userID, map[string]interface{}{
+		"previous_role": previous,
+		"role":          role,
+	}))
+
+	return user, nil
+}
+
+// StartImpersonation inicia um acesso de suporte à conta de um usuário ativo.
+// Não é permitido acessar a própria conta nem a de outro administrador.
+func (s *adminService) StartImpersonation(ctx AuditContext, userID uint, allowWrite bool, reason string) (*ImpersonationToken, error) {
+	if ctx.ActorID == userID {
+		return nil, apperrors.ErrSelfAdminAction
+	}
+
+	actor, err := s.getUser(ctx.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == models.RoleAdmin {
+		return nil, apperrors.ErrCannotImpersonate
+	}
+	if user.Status != models.UserStatusActive {
+		return nil, apperrors.ErrUserDeactivated
+	}
+
+	token, err := s.authService.IssueImpersonationToken(actor, user, allowWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn(fmt.Sprintf("Administrador %d iniciou acesso de suporte à conta do usuário %d até %s (somente leitura: %t)",
+		actor.ID, user.ID, token.ExpiresAt.Format(time.RFC3339), token.ReadOnly))
+	s.audit.Record(newAuditEvent(ctx, models.AuditImpersonationStarted, user.ID, map[string]interface{}{
+		"expires_at": token.ExpiresAt,
+		"read_only":  token.ReadOnly,
+		"reason":     reason,
+	}))
+
+	return token, nil
+}
+
+// EndImpersonation encerra o acesso de suporte revogando o token antes de expirar.
+// A chamada é feita com a sessão do administrador, já que o token de suporte pode ser somente leitura.
+func (s *adminService) EndImpersonation(ctx AuditContext, accessToken string) error {
+	claims, err := s.authService.ValidateAccessToken(accessToken)
+	if err != nil {
+		return err
+	}
+	if claims.Actor == nil || claims.Actor.UserID != ctx.ActorID {
+		return apperrors.ErrNotImpersonating
+	}
+
+	if err := s.authService.Logout(claims); err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("Administrador %d encerrou o acesso de suporte à conta do usuário %d", claims.Actor.UserID, claims.UserID))
+	s.audit.Record(newAuditEvent(ctx, models.AuditImpersonationEnded, claims.UserID, nil))
+	return nil
+}
+
+// getUser busca o usuário convertendo a ausência de registro em ErrUserNotFound
+func (s *adminService) getUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// apiTokenTouchInterval evita gravar o último uso a cada requisição de um mesmo token
+const apiTokenTouchInterval = time.Minute
+
+// APITokenService define a interface do serviço de tokens de acesso pessoal
+type APITokenService interface {
+	// Create cria um token e retorna o valor em texto puro, que não pode ser recuperado depois
+	Create(userID uint, name string, scopes []string, ttl time.Duration) (*models.APIToken, string, error)
+	List(userID uint) ([]models.APIToken, error)
+	Revoke(userID, id uint) error
+	// Authenticate valida o token apresentado e registra o seu uso
+	Authenticate(token, ip string) (*models.APIToken, error)
+}
+
+// apiTokenService implementa a interface APITokenService
+type apiTokenService struct {
+	tokenRepo repository.APITokenRepository
+	logger    logger.Logger
+}
+
+// NewAPITokenService cria uma nova instância de APITokenService
+func NewAPITokenService(tokenRepo repository.APITokenRepository, logger logger.Logger) APITokenService {
+	return &apiTokenService{
+		tokenRepo: tokenRepo,
+		logger:    logger,
+	}
+}
+
+// Create cria um token de acesso com os escopos informados.
+// Um ttl igual a zero cria um token sem data de expiração.
+func (s *apiTokenService) Create(userID uint, name string, scopes []string, ttl time.Duration) (*models.APIToken, string, error) {
+	normalized, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	plain := models.APITokenPrefix + secret
+
+	token := &models.APIToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    plain[:len(models.APITokenPrefix)+4],
+		TokenHash: hashToken(plain),
+		Scopes:    strings.Join(normalized, " "),
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, "", err
+	}
+
+	return token, plain, nil
+}
+
+// List retorna os tokens de acesso ativos do usuário
+func (s *apiTokenService) List(userID uint) ([]models.APIToken, error) {
+	return s.tokenRepo.ListByUser(userID)
+}
+
+// Revoke revoga um token de acesso do usuário
+func (s *apiTokenService) Revoke(userID, id uint) error {
+	revoked, err := s.tokenRepo.Revoke(userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// Authenticate valida o token e registra o último uso. O status do dono é verificado pelo AuthMiddleware.
+func (s *apiTokenService) Authenticate(plain, ip string) (*models.APIToken, error) {
+	if !strings.HasPrefix(plain, models.APITokenPrefix) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.GetByTokenHash(hashToken(plain))
+	if err != nil {
+		if err == models.ErrRecordNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if token.IsRevoked() {
+		return nil, apperrors.ErrTokenRevoked
+	}
+	if token.IsExpired() {
+		return nil, apperrors.ErrTokenExpired
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenTouchInterval || token.LastUsedIP != ip {
+		// Uma falha ao registrar o uso não impede a requisição
+		if err := s.tokenRepo.TouchLastUsed(token.ID, ip, now); err != nil {
+			s.logger.Error(fmt.Sprintf("Erro ao registrar uso do token de acesso %d: %v", token.ID, err))
+		}
+	}
+
+	return token, nil
+}
+
+// normalizeScopes valida os escopos e remove duplicados
+func normalizeScopes(scopes []string) ([]string, error) {
+	valid := make(map[string]bool, len(models.APITokenScopes))
+	for _, scope := range models.APITokenScopes {
+		valid[scope] = true
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !valid[scope] {
+			return nil, fmt.Errorf("%w: %s", apperrors.ErrInvalidScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, apperrors.ErrInvalidScope
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// AuditContext identifica quem executou uma ação e de onde
+type AuditContext struct {
+	ActorID   uint
+	IP        string
+	UserAgent string
+}
+
+// AuditWriter registra eventos na trilha de auditoria. Falhas de gravação são
+// registradas no log e não interrompem a ação auditada.
+type AuditWriter interface {
+	Record(event *models.AuditEvent)
+}
+
+// auditWriter implementa AuditWriter persistindo os eventos no banco de dados
+type auditWriter struct {
+	auditRepo repository.AuditEventRepository
+	logger    logger.Logger
+}
+
+// NewAuditWriter cria uma nova instância de AuditWriter
+func NewAuditWriter(auditRepo repository.AuditEventRepository, logger logger.Logger) AuditWriter {
+	return &auditWriter{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Record grava o evento na trilha de auditoria
+func (w *auditWriter) Record(event *models.AuditEvent) {
+	if event.Outcome == "" {
+		event.Outcome = models.AuditSuccess
+	}
+
+	if err := w.auditRepo.Create(event); err != nil {
+		w.logger.Error(fmt.Sprintf("Erro ao registrar evento de auditoria %s: %v", event.Action, err))
+	}
+}
+
+// AuditService define a interface de consulta à trilha de auditoria
+type AuditService interface {
+	// ListUserEvents retorna os eventos que afetaram a conta do usuário (logins, trocas de senha, ações administrativas...)
+	ListUserEvents(userID uint, page, pageSize int) ([]models.AuditEvent, int64, error)
+	// Search retorna os eventos que atendem aos filtros, para a administração
+	Search(filter repository.AuditEventFilter, page, pageSize int) ([]models.AuditEvent, int64, error)
+}
+
+// auditService implementa a interface AuditService
+type auditService struct {
+	auditRepo repository.AuditEventRepository
+	logger    logger.Logger
+}
+
+// NewAuditService cria uma nova instância de AuditService
+func NewAuditService(auditRepo repository.AuditEventRepository, logger logger.Logger) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// ListUserEvents retorna os eventos da conta do usuário
+func (s *auditService) ListUserEvents(userID uint, page, pageSize int) ([]models.AuditEvent, int64, error) {
+	return s.Search(repository.AuditEventFilter{UserID: userID}, page, pageSize)
+}
+
+// Search retorna uma lista paginada de eventos que atendem aos filtros
+func (s *auditService) Search(filter repository.AuditEventFilter, page, pageSize int) ([]models.AuditEvent, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	return s.auditRepo.Search(filter, page, pageSize)
+}
+
+// newAuditEvent monta um evento de auditoria a partir do contexto da requisição
+func newAuditEvent(ctx AuditContext, action models.AuditAction, userID uint, metadata map[string]interface{}) *models.AuditEvent {
+	event := &models.AuditEvent{
+		Action:    action,
+		Outcome:   models.AuditSuccess,
+		UserID:    &userID,
+		IP:        ctx.IP,
+		UserAgent: ctx.UserAgent,
+	}
+
+	if ctx.ActorID != 0 {
+		actorID := ctx.ActorID
+		event.ActorID = &actorID
+	}
+
+	if len(metadata) > 0 {
+		if data, err := json.Marshal(metadata); err == nil {
+			event.Metadata = string(data)
+		}
+	}
+
+	return event
+}
+
+// auditContextFor identifica o próprio usuário como autor de uma ação feita a partir da sessão descrita em meta
+func auditContextFor(userID uint, meta SessionMeta) AuditContext {
+	return AuditContext{
+		ActorID:   userID,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+	}
+}
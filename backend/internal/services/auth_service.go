@@ -2,44 +2,164 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jpcode092/crm-freela/configs"
 	apperrors "github.com/jpcode092/crm-freela/internal/errors"
 	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/email"
 	"github.com/jpcode092/crm-freela/pkg/logger"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// Claims representa os claims do JWT
+// Claims representa os claims do JWT.
+// RegisteredClaims.ID (jti) identifica o token no TokenRevocationStore e
+// SessionID aponta para a família de sessões que o emitiu.
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint        `json:"user_id"`
+	SessionID string      `json:"sid,omitempty"`
+	Purpose   string      `json:"purpose,omitempty"`
+	Email     string      `json:"email,omitempty"`
+	Actor     *ActorClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ActorClaim identifica o administrador que age em nome do usuário do token (claim "act", RFC 8693).
+// Sem AllowWrite, o token de acesso de suporte só vale para leituras.
+type ActorClaim struct {
+	UserID     uint `json:"user_id"`
+	AllowWrite bool `json:"write,omitempty"`
+}
+
+// PurposeMFAPending identifica o token emitido após a senha, enquanto o segundo fator não foi validado.
+// Ele só pode ser trocado em /auth/mfa/verify e nunca é aceito como access token.
+const PurposeMFAPending = "mfa_pending"
+
+// PurposeEmailVerification identifica o token enviado no link de confirmação de e-mail.
+// O claim Email guarda o endereço confirmado, invalidando o link se o e-mail da conta mudar.
+const PurposeEmailVerification = "email_verification"
+
+// LoginMethod identifica o meio usado no primeiro fator do login
+type LoginMethod string
+
+const (
+	LoginMethodPassword  LoginMethod = "password"
+	LoginMethodMagicLink LoginMethod = "magic_link"
+	LoginMethodOIDC      LoginMethod = "oidc"
+	LoginMethodPasskey   LoginMethod = "passkey"
+)
+
+// SessionMeta identifica o dispositivo que abriu ou renovou uma sessão
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+	Method    LoginMethod // preenchido pelo serviço que autenticou o usuário, para a trilha de auditoria
+}
+
+// TokenPair representa o par de tokens entregue ao cliente após a autenticação
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// ImpersonationToken representa o access token de um acesso de suporte. Não há refresh token:
+// ao expirar, o administrador precisa iniciar um novo acesso.
+type ImpersonationToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int64     `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ReadOnly    bool      `json:"read_only"`
+}
+
+// LoginResult representa o resultado de uma autenticação.
+// Quando o usuário tem MFA ativo, Tokens é nil e MFAToken deve ser trocado em VerifyMFA.
+type LoginResult struct {
+	User     *models.User
+	Tokens   *TokenPair
+	MFAToken string
+}
+
+// MFARequired indica se o login aguarda a validação do segundo fator
+func (r *LoginResult) MFARequired() bool {
+	return r.MFAToken != ""
+}
+
 // AuthService define a interface do serviço de autenticação
 type AuthService interface {
 	Register(name, email, password string) (*models.User, error)
-	Login(email, password string) (*models.User, string, error)
-	RefreshToken(token string) (string, error)
+	Login(email, password string, meta SessionMeta) (*LoginResult, error)
+	VerifyMFA(mfaToken, code string, meta SessionMeta) (*LoginResult, error)
+	LoginVerifiedUser(user *models.User, meta SessionMeta) (*LoginResult, error)
+	VerifyEmail(token string) (*models.User, error)
+	ResendVerification(email, ip string) error
+	RefreshToken(refreshToken string, meta SessionMeta) (*TokenPair, error)
+	ValidateAccessToken(token string) (*Claims, error)
+	Logout(claims *Claims) error
+	ListSessions(userID uint) ([]models.Session, error)
+	RevokeSession(userID uint, familyID string) error
+	RevokeAllSessions(userID uint) error
+	RevokeOtherSessions(userID uint, currentSessionID string) error
+	IssueImpersonationToken(actor, user *models.User, allowWrite bool) (*ImpersonationToken, error)
 	GetUserByID(id uint) (*models.User, error)
 }
 
 // authService implementa a interface AuthService
 type authService struct {
-	userRepo models.UserRepository
-	logger   logger.Logger
-	config   *configs.Config
+	userRepo        models.UserRepository
+	sessionRepo     repository.SessionRepository
+	revocationStore TokenRevocationStore
+	signer          TokenSigner
+	mfaService      MFAService
+	ipAttempts      LoginAttemptTracker
+	emailAttempts   LoginAttemptTracker
+	resendByEmail   RateLimiter
+	resendByIP      RateLimiter
+	mailer          email.EmailService
+	audit           AuditWriter
+	dummyHash       []byte
+	logger          logger.Logger
+	config          *configs.Config
 }
 
 // NewAuthService cria uma nova instância de AuthService
-func NewAuthService(userRepo models.UserRepository, logger logger.Logger, config *configs.Config) AuthService {
+func NewAuthService(
+	userRepo models.UserRepository,
+	sessionRepo repository.SessionRepository,
+	revocationStore TokenRevocationStore,
+	signer TokenSigner,
+	mfaService MFAService,
+	ipAttempts LoginAttemptTracker,
+	mailer email.EmailService,
+	audit AuditWriter,
+	logger logger.Logger,
+	config *configs.Config,
+) AuthService {
+	// Hash usado para comparar a senha quando o e-mail não existe, igualando o tempo de resposta
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("crm-freela-dummy-password"), bcrypt.DefaultCost)
+
 	return &authService{
-		userRepo: userRepo,
-		logger:  logger,
-		config:  config,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		revocationStore: revocationStore,
+		signer:          signer,
+		mfaService:      mfaService,
+		ipAttempts:      ipAttempts,
+		emailAttempts:   NewMemoryAttemptTracker(config.Login.MaxFailedAttempts, config.Login.LockoutBase, config.Login.LockoutMax),
+		resendByEmail:   NewMemoryRateLimiter(config.Email.ResendLimit, config.Email.ResendWindow),
+		resendByIP:      NewMemoryRateLimiter(config.Email.ResendLimit*3, config.Email.ResendWindow),
+		mailer:          mailer,
+		audit:           audit,
+		dummyHash:       dummyHash,
+		logger:          logger,
+		config:          config,
 	}
 }
 
@@ -60,105 +180,711 @@ func (s *authService) Register(name, email, password string) (*models.User, erro
 		return nil, err
 	}
 
-	// Cria o usuário
+	// Cria o usuário, que permanece inativo até confirmar o e-mail
 	user := &models.User{
 		Name:     name,
 		Email:    email,
 		Password: string(hashedPassword),
-		Status:   models.UserStatusActive,
+		Status:   models.UserStatusInactive,
 	}
 
 	err = s.userRepo.Create(user)
 	if err != nil {
+		// Cadastro simultâneo com o mesmo e-mail
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, apperrors.ErrEmailInUse
+		}
 		return nil, err
 	}
 
+	// Uma falha no envio não desfaz o cadastro: o usuário pode solicitar o reenvio
+	if err := s.sendVerificationEmail(user); err != nil {
+		s.logger.Error(fmt.Sprintf("Erro ao enviar e-mail de confirmação para %s: %v", user.Email, err))
+	}
+
 	return user, nil
 }
 
-// Login autentica um usuário
-func (s *authService) Login(email, password string) (*models.User, string, error) {
+// VerifyEmail confirma o e-mail do usuário a partir do token enviado no link e ativa a conta
+func (s *authService) VerifyEmail(token string) (*models.User, error) {
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != PurposeEmailVerification {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	user, err := s.GetUserByID(claims.UserID)
+	if err != nil {
+		if err == apperrors.ErrUserNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	// Links repetidos apenas confirmam que o e-mail já foi verificado
+	if user.EmailVerifiedAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if user.Status == models.UserStatusInactive {
+		user.Status = models.UserStatusActive
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ResendVerification reenvia o link de confirmação, com limite por e-mail e por IP.
+// Não informa se o e-mail existe ou já foi confirmado.
+func (s *authService) ResendVerification(email, ip string) error {
+	if err := s.resendByIP.Allow(ip); err != nil {
+		return err
+	}
+	if err := s.resendByEmail.Allow(strings.ToLower(email)); err != nil {
+		return err
+	}
+
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
-		if err == models.ErrRecordNotFound {
-			return nil, "", apperrors.ErrUserNotFound
+		if errors.Is(err, gorm.ErrRecordNotFound) || err == models.ErrRecordNotFound {
+			return nil
 		}
-		return nil, "", err
+		return err
 	}
 
-	if user.Status != models.UserStatusActive {
-		return nil, "", apperrors.ErrUserDeactivated
+	if user.EmailVerifiedAt != nil || user.Status != models.UserStatusInactive {
+		return nil
 	}
 
-	// Verifica a senha
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	return s.sendVerificationEmail(user)
+}
+
+// sendVerificationEmail envia o link assinado de confirmação de e-mail
+func (s *authService) sendVerificationEmail(user *models.User) error {
+	tokenID, err := generateOpaqueToken()
 	if err != nil {
-		return nil, "", apperrors.ErrInvalidPassword
+		return err
 	}
 
-	// Gera o token JWT
-	claims := &Claims{
-		UserID: user.ID,
+	now := time.Now()
+	token, err := s.signToken(&Claims{
+		UserID:  user.ID,
+		Purpose: PurposeEmailVerification,
+		Email:   user.Email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.AccessTokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Email.VerificationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
+	})
+	if err != nil {
+		return err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	link := strings.TrimRight(s.config.Email.AppURL, "/") + "/auth/verify-email?token=" + token
+	return s.mailer.SendEmailVerification(user.Email, user.Name, link)
+}
+
+// Login autentica um usuário.
+// Para não revelar quais e-mails estão cadastrados, e-mail inexistente e senha
+// incorreta retornam o mesmo ErrInvalidCredentials. Falhas consecutivas bloqueiam
+// temporariamente a conta e o IP de origem (LockoutError).
+func (s *authService) Login(email, password string, meta SessionMeta) (*LoginResult, error) {
+	meta.Method = LoginMethodPassword
+
+	if retryAfter := s.ipAttempts.Check(meta.IP); retryAfter > 0 {
+		s.recordLoginFailure(nil, meta, "ip_locked", email)
+		return nil, &LockoutError{RetryAfter: retryAfter}
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
-		return nil, "", err
+		if errors.Is(err, gorm.ErrRecordNotFound) || err == models.ErrRecordNotFound {
+			s.recordLoginFailure(nil, meta, "unknown_email", email)
+			return nil, s.rejectUnknownEmail(email, password, meta)
+		}
+		return nil, err
+	}
+
+	if user.IsLocked() {
+		s.recordLoginFailure(user, meta, "account_locked", "")
+		return nil, &LockoutError{RetryAfter: remaining(*user.LockedUntil)}
+	}
+
+	// Verifica a senha
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	if err != nil {
+		s.recordLoginFailure(user, meta, "invalid_password", "")
+		return nil, s.registerFailedAttempt(user, meta)
+	}
+
+	if user.Status == models.UserStatusInactive && user.EmailVerifiedAt == nil {
+		s.recordLoginFailure(user, meta, "email_not_verified", "")
+		return nil, apperrors.ErrEmailNotVerified
+	}
+
+	if user.Status != models.UserStatusActive {
+		s.recordLoginFailure(user, meta, "user_deactivated", "")
+		return nil, apperrors.ErrUserDeactivated
 	}
 
-	return user, tokenString, nil
+	// Só é informado a quem acertou a senha, para não revelar a configuração da conta
+	if user.PasswordLoginDisabled {
+		s.recordLoginFailure(user, meta, "password_login_disabled", "")
+		return nil, apperrors.ErrPasswordLoginDisabled
+	}
+
+	if err := s.resetFailedAttempts(user); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(user, meta)
 }
 
-// RefreshToken renova um token JWT
-func (s *authService) RefreshToken(tokenString string) (string, error) {
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// rejectUnknownEmail trata a tentativa de login com e-mail não cadastrado da mesma forma que
+// uma senha incorreta (mesmo erro, tempo de resposta e bloqueio), evitando a enumeração de usuários
+func (s *authService) rejectUnknownEmail(email, password string, meta SessionMeta) error {
+	_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+
+	key := strings.ToLower(email)
+	if retryAfter := s.emailAttempts.Check(key); retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	s.emailAttempts.RegisterFailure(key)
+	s.ipAttempts.RegisterFailure(meta.IP)
+	return apperrors.ErrInvalidCredentials
+}
+
+// registerFailedAttempt contabiliza uma falha de autenticação da conta e do IP e,
+// ao atingir o limite, bloqueia a conta com backoff exponencial.
+// O incremento e o bloqueio são feitos pelo banco em uma única instrução, para que
+// tentativas simultâneas não sobrescrevam o contador umas das outras.
+func (s *authService) registerFailedAttempt(user *models.User, meta SessionMeta) error {
+	if delay := s.ipAttempts.RegisterFailure(meta.IP); delay > 0 {
+		s.logger.Warn(fmt.Sprintf("IP %s bloqueado por %s após falhas de login consecutivas", meta.IP, delay))
+	}
+
+	login := s.config.Login
+	attempts, err := s.userRepo.RegisterFailedLogin(user.ID, login.MaxFailedAttempts, login.LockoutBase, login.LockoutMax)
+	if err != nil {
+		return err
+	}
+
+	user.FailedLoginAttempts = attempts
+	if delay := lockoutDelay(attempts, login.MaxFailedAttempts, login.LockoutBase, login.LockoutMax); delay > 0 {
+		lockedUntil := time.Now().Add(delay)
+		user.LockedUntil = &lockedUntil
+		s.logger.Warn(fmt.Sprintf("Conta do usuário %d bloqueada por %s após %d falhas de login (IP %s)",
+			user.ID, delay, attempts, meta.IP))
+	}
+
+	return apperrors.ErrInvalidCredentials
+}
+
+// resetFailedAttempts zera o contador de falhas da conta após uma autenticação bem-sucedida
+func (s *authService) resetFailedAttempts(user *models.User) error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil == nil {
+		return nil
+	}
+
+	if err := s.userRepo.ResetFailedLogins(user.ID); err != nil {
+		return err
+	}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	return nil
+}
+
+// VerifyMFA troca um token "mfa_pending" e um código válido (TOTP ou de recuperação) pelo par de tokens
+func (s *authService) VerifyMFA(mfaToken, code string, meta SessionMeta) (*LoginResult, error) {
+	claims, err := s.parseToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != PurposeMFAPending {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	revoked, err := s.revocationStore.IsRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, apperrors.ErrTokenRevoked
+	}
+
+	user, err := s.GetUserByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != models.UserStatusActive {
+		s.recordLoginFailure(user, meta, "user_deactivated", "")
+		return nil, apperrors.ErrUserDeactivated
+	}
+
+	if user.IsLocked() {
+		s.recordLoginFailure(user, meta, "account_locked", "")
+		return nil, &LockoutError{RetryAfter: remaining(*user.LockedUntil)}
+	}
+
+	// Códigos incorretos contam para o bloqueio da conta, impedindo a força bruta do TOTP
+	if err := s.mfaService.Verify(user, code); err != nil {
+		if err == apperrors.ErrInvalidMFACode {
+			s.recordLoginFailure(user, meta, "invalid_mfa_code", "")
+			if err := s.registerFailedAttempt(user, meta); err != apperrors.ErrInvalidCredentials {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.resetFailedAttempts(user); err != nil {
+		return nil, err
+	}
+
+	// O token "mfa_pending" é de uso único
+	if err := s.revocationStore.Revoke(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.openSession(user, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordLogin(user, meta, map[string]interface{}{"mfa": true})
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// LoginVerifiedUser conclui o login de um usuário já autenticado por outro meio
+// (ex.: provedor OpenID Connect), aplicando as mesmas regras de status e MFA do login com senha
+func (s *authService) LoginVerifiedUser(user *models.User, meta SessionMeta) (*LoginResult, error) {
+	if user.Status != models.UserStatusActive {
+		s.recordLoginFailure(user, meta, "user_deactivated", "")
+		return nil, apperrors.ErrUserDeactivated
+	}
+
+	if user.IsLocked() {
+		s.recordLoginFailure(user, meta, "account_locked", "")
+		return nil, &LockoutError{RetryAfter: remaining(*user.LockedUntil)}
+	}
+
+	return s.completeLogin(user, meta)
+}
+
+// completeLogin finaliza a autenticação pelo primeiro fator: emite o par de tokens ou,
+// se o usuário tiver MFA ativo, um token "mfa_pending" de curta duração.
+// Com MFA, o login só é registrado na trilha de auditoria após a validação do código.
+func (s *authService) completeLogin(user *models.User, meta SessionMeta) (*LoginResult, error) {
+	if user.MFAEnabled {
+		mfaToken, err := s.generateMFAPendingToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, MFAToken: mfaToken}, nil
+	}
+
+	tokens, err := s.openSession(user, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordLogin(user, meta, nil)
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// recordLogin registra um login concluído na trilha de auditoria
+func (s *authService) recordLogin(user *models.User, meta SessionMeta, metadata map[string]interface{}) {
+	if meta.Method != "" {
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadata["method"] = meta.Method
+	}
+
+	s.audit.Record(newAuditEvent(auditContextFor(user.ID, meta), models.AuditLogin, user.ID, metadata))
+}
+
+// recordLoginFailure registra uma tentativa de login recusada. Quando a conta não é conhecida
+// (user nil), o e-mail informado é guardado nos detalhes para permitir a investigação de ataques.
+func (s *authService) recordLoginFailure(user *models.User, meta SessionMeta, reason, email string) {
+	metadata := map[string]interface{}{"reason": reason}
+	if meta.Method != "" {
+		metadata["method"] = meta.Method
+	}
+
+	var event *models.AuditEvent
+	if user != nil {
+		event = newAuditEvent(auditContextFor(user.ID, meta), models.AuditLogin, user.ID, metadata)
+	} else {
+		metadata["email"] = strings.ToLower(email)
+		event = newAuditEvent(auditContextFor(0, meta), models.AuditLogin, 0, metadata)
+		event.UserID = nil
+	}
+	event.Outcome = models.AuditFailure
+	s.audit.Record(event)
+}
+
+// openSession abre uma nova família de sessões para o usuário e emite o primeiro par de tokens
+func (s *authService) openSession(user *models.User, meta SessionMeta) (*TokenPair, error) {
+	familyID, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(user, familyID, meta)
+}
+
+// RefreshToken troca um refresh token válido por um novo par de tokens.
+// O token apresentado é invalidado (rotação); se um token já rotacionado for
+// reapresentado, toda a família de sessões é revogada.
+func (s *authService) RefreshToken(refreshToken string, meta SessionMeta) (*TokenPair, error) {
+	session, err := s.sessionRepo.GetByTokenHash(hashToken(refreshToken))
+	if err != nil {
+		if err == models.ErrRecordNotFound {
 			return nil, apperrors.ErrInvalidToken
 		}
-		return []byte(s.config.JWT.Secret), nil
+		return nil, err
+	}
+
+	if session.IsRevoked() {
+		s.recordRefreshFailure(session, meta, "revoked")
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if session.IsRotated() {
+		s.recordRefreshFailure(session, meta, "reused")
+		return nil, s.revokeReusedFamily(session)
+	}
+
+	if session.IsExpired() {
+		s.recordRefreshFailure(session, meta, "expired")
+		return nil, apperrors.ErrTokenExpired
+	}
+
+	user, err := s.GetUserByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != models.UserStatusActive {
+		s.recordRefreshFailure(session, meta, "user_deactivated")
+		return nil, apperrors.ErrUserDeactivated
+	}
+
+	// Marca o token atual como utilizado; se outra requisição chegou antes, trata como reuso
+	rotated, err := s.sessionRepo.MarkRotated(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		s.recordRefreshFailure(session, meta, "reused")
+		return nil, s.revokeReusedFamily(session)
+	}
+
+	tokens, err := s.issueTokenPair(user, session.FamilyID, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(newAuditEvent(auditContextFor(user.ID, meta), models.AuditTokenRefresh, user.ID, nil))
+	return tokens, nil
+}
+
+// recordRefreshFailure registra a recusa de um refresh token conhecido na trilha de auditoria
+func (s *authService) recordRefreshFailure(session *models.Session, meta SessionMeta, reason string) {
+	event := newAuditEvent(auditContextFor(session.UserID, meta), models.AuditTokenRefresh, session.UserID, map[string]interface{}{
+		"reason": reason,
 	})
+	event.Outcome = models.AuditFailure
+	s.audit.Record(event)
+}
 
+// revokeReusedFamily revoga a família de uma sessão cujo refresh token foi reapresentado
+func (s *authService) revokeReusedFamily(session *models.Session) error {
+	s.logger.Warn(fmt.Sprintf("Reuso de refresh token detectado para o usuário %d; revogando a família de sessões", session.UserID))
+	if err := s.revokeFamily(session.FamilyID); err != nil {
+		return err
+	}
+	return apperrors.ErrRefreshTokenReused
+}
+
+// ValidateAccessToken valida a assinatura e a expiração de um access token e
+// rejeita tokens revogados antes de expirar
+func (s *authService) ValidateAccessToken(tokenString string) (*Claims, error) {
+	claims, err := s.parseToken(tokenString)
 	if err != nil {
-		return "", apperrors.ErrInvalidToken
+		return nil, err
 	}
 
-	if !token.Valid {
-		return "", apperrors.ErrInvalidToken
+	// Tokens com finalidade específica (ex.: "mfa_pending") não dão acesso à API
+	if claims.Purpose != "" {
+		return nil, apperrors.ErrInvalidToken
 	}
 
-	user, err := s.GetUserByID(claims.UserID)
+	revoked, err := s.revocationStore.IsRevoked(claims.ID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if revoked {
+		return nil, apperrors.ErrTokenRevoked
 	}
 
-	if user.Status != models.UserStatusActive {
-		return "", apperrors.ErrUserDeactivated
+	return claims, nil
+}
+
+// parseToken valida a assinatura (pelo kid) e a expiração de um JWT emitido por este serviço
+func (s *authService) parseToken(tokenString string) (*Claims, error) {
+	return parseClaims(s.signer, tokenString)
+}
+
+// parseClaims valida um JWT com as chaves do TokenSigner e traduz os erros para os da aplicação
+func parseClaims(signer TokenSigner, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := signer.Parse(tokenString, claims)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, apperrors.ErrTokenExpired
+		}
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if !token.Valid || claims.ID == "" {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// Logout encerra a sessão do token apresentado: revoga o access token e a família de refresh tokens
+func (s *authService) Logout(claims *Claims) error {
+	if err := s.revocationStore.Revoke(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	return s.revokeFamily(claims.SessionID)
+}
+
+// ListSessions retorna as sessões ativas do usuário (o refresh token vigente de cada família)
+func (s *authService) ListSessions(userID uint) ([]models.Session, error) {
+	return s.sessionRepo.ListActiveByUser(userID)
+}
+
+// RevokeSession revoga uma sessão do usuário e os access tokens ainda válidos emitidos por ela
+func (s *authService) RevokeSession(userID uint, familyID string) error {
+	sessions, err := s.sessionRepo.ListByFamily(familyID)
+	if err != nil {
+		return err
+	}
+
+	// Verifica se a sessão existe e pertence ao usuário
+	if len(sessions) == 0 || sessions[0].UserID != userID {
+		return apperrors.ErrSessionNotFound
 	}
 
-	// Gera um novo token
-	newClaims := &Claims{
+	return s.revokeFamily(familyID)
+}
+
+// RevokeAllSessions revoga todas as sessões do usuário e os access tokens ainda válidos
+func (s *authService) RevokeAllSessions(userID uint) error {
+	sessions, err := s.sessionRepo.ListIssuedSince(userID, time.Now().Add(-s.config.JWT.AccessTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllByUser(userID); err != nil {
+		return err
+	}
+
+	return s.revokeAccessTokens(sessions)
+}
+
+// RevokeOtherSessions revoga todas as sessões do usuário, exceto a informada (ex.: após a troca de senha)
+func (s *authService) RevokeOtherSessions(userID uint, currentSessionID string) error {
+	sessions, err := s.sessionRepo.ListActiveByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.FamilyID == currentSessionID {
+			continue
+		}
+		if err := s.revokeFamily(session.FamilyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeFamily revoga os refresh tokens de uma família e os access tokens emitidos por ela que ainda não expiraram
+func (s *authService) revokeFamily(familyID string) error {
+	sessions, err := s.sessionRepo.ListByFamily(familyID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeFamily(familyID); err != nil {
+		return err
+	}
+
+	return s.revokeAccessTokens(sessions)
+}
+
+// revokeAccessTokens adiciona ao TokenRevocationStore os access tokens ainda válidos das sessões informadas
+func (s *authService) revokeAccessTokens(sessions []models.Session) error {
+	now := time.Now()
+	for _, session := range sessions {
+		expiresAt := session.CreatedAt.Add(s.config.JWT.AccessTokenTTL)
+		if session.AccessTokenID == "" || expiresAt.Before(now) {
+			continue
+		}
+		if err := s.revocationStore.Revoke(session.AccessTokenID, expiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueTokenPair gera um access token JWT e um refresh token opaco persistido na família informada
+func (s *authService) issueTokenPair(user *models.User, familyID string, meta SessionMeta) (*TokenPair, error) {
+	accessTokenID, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateAccessToken(user, accessTokenID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:        user.ID,
+		FamilyID:      familyID,
+		TokenHash:     hashToken(refreshToken),
+		AccessTokenID: accessTokenID,
+		UserAgent:     meta.UserAgent,
+		IP:            meta.IP,
+		ExpiresAt:     time.Now().Add(s.config.JWT.RefreshTokenTTL),
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.config.JWT.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// generateAccessToken gera um access token JWT de curta duração para o usuário
+func (s *authService) generateAccessToken(user *models.User, tokenID, sessionID string) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.JWT.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return s.signToken(claims)
+}
+
+// IssueImpersonationToken emite o access token com que o administrador actor acessa a conta de user.
+// O token não pertence a nenhuma sessão do usuário e é revogado individualmente pelo jti.
+func (s *authService) IssueImpersonationToken(actor, user *models.User, allowWrite bool) (*ImpersonationToken, error) {
+	tokenID, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.JWT.ImpersonationTTL)
+	claims := &Claims{
 		UserID: user.ID,
+		Actor:  &ActorClaim{UserID: actor.ID, AllowWrite: allowWrite},
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.JWT.AccessTokenTTL)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	newToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	return newToken.SignedString([]byte(s.config.JWT.Secret))
+	accessToken, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ImpersonationToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.config.JWT.ImpersonationTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+		ReadOnly:    !allowWrite,
+	}, nil
+}
+
+// generateMFAPendingToken gera o token de curta duração que aguarda a validação do segundo fator
+func (s *authService) generateMFAPendingToken(user *models.User) (string, error) {
+	tokenID, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		UserID:  user.ID,
+		Purpose: PurposeMFAPending,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.MFA.PendingTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return s.signToken(claims)
+}
+
+// signToken assina os claims com a chave de assinatura atual
+func (s *authService) signToken(claims *Claims) (string, error) {
+	return s.signer.Sign(claims)
 }
 
 // GetUserByID busca um usuário por ID
 func (s *authService) GetUserByID(id uint) (*models.User, error) {
 	user, err := s.userRepo.GetByID(id)
 	if err != nil {
-		if err == models.ErrRecordNotFound {
+		if errors.Is(err, models.ErrRecordNotFound) {
 			return nil, apperrors.ErrUserNotFound
 		}
 		return nil, err
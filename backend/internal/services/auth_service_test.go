@@ -0,0 +1,423 @@
+package services_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/middleware"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "senha-de-teste"
+
+// fakeSessionRepo reproduz em memória as consultas de repository.SessionRepository
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions []*models.Session
+}
+
+func (r *fakeSessionRepo) Create(session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session.ID = uint(len(r.sessions) + 1)
+	session.CreatedAt = time.Now()
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *fakeSessionRepo) GetByTokenHash(tokenHash string) (*models.Session, error) {
+	return r.first(func(s *models.Session) bool { return s.TokenHash == tokenHash })
+}
+
+func (r *fakeSessionRepo) ListByFamily(familyID string) ([]models.Session, error) {
+	return r.list(func(s *models.Session) bool { return s.FamilyID == familyID }), nil
+}
+
+func (r *fakeSessionRepo) ListActiveByUser(userID uint) ([]models.Session, error) {
+	now := time.Now()
+	return r.list(func(s *models.Session) bool {
+		return s.UserID == userID && s.RotatedAt == nil && s.RevokedAt == nil && s.ExpiresAt.After(now)
+	}), nil
+}
+
+func (r *fakeSessionRepo) ListIssuedSince(userID uint, since time.Time) ([]models.Session, error) {
+	return r.list(func(s *models.Session) bool { return s.UserID == userID && !s.CreatedAt.Before(since) }), nil
+}
+
+func (r *fakeSessionRepo) MarkRotated(id uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sessions {
+		if s.ID == id && s.RotatedAt == nil && s.RevokedAt == nil {
+			now := time.Now()
+			s.RotatedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeSessionRepo) RevokeFamily(familyID string) error {
+	r.revoke(func(s *models.Session) bool { return s.FamilyID == familyID })
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllByUser(userID uint) error {
+	r.revoke(func(s *models.Session) bool { return s.UserID == userID })
+	return nil
+}
+
+func (r *fakeSessionRepo) first(match func(*models.Session) bool) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sessions {
+		if match(s) {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepo) list(match func(*models.Session) bool) []models.Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sessions []models.Session
+	for _, s := range r.sessions {
+		if match(s) {
+			sessions = append(sessions, *s)
+		}
+	}
+	return sessions
+}
+
+func (r *fakeSessionRepo) revoke(match func(*models.Session) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, s := range r.sessions {
+		if match(s) && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+}
+
+// fakeUserRepo conhece um único usuário
+type fakeUserRepo struct {
+	models.UserRepository
+	mu       sync.Mutex
+	user     *models.User
+	failures int
+	resets   int
+}
+
+func (r *fakeUserRepo) GetByID(id uint) (*models.User, error) {
+	if id != r.user.ID {
+		return nil, models.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (*models.User, error) {
+	if email != r.user.Email {
+		return nil, models.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *fakeUserRepo) RegisterFailedLogin(id uint, threshold int, base, max time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures++
+	return r.failures, nil
+}
+
+func (r *fakeUserRepo) ResetFailedLogins(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = 0
+	r.resets++
+	return nil
+}
+
+// discardAudit descarta os eventos da trilha de auditoria
+type discardAudit struct{}
+
+func (discardAudit) Record(event *models.AuditEvent) {}
+
+type authFixture struct {
+	service services.AuthService
+	store   services.TokenRevocationStore
+	users   *fakeUserRepo
+	user    *models.User
+	router  *gin.Engine
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	config := &configs.Config{
+		JWT: configs.JWTConfig{
+			SigningAlgorithm: jwk.AlgEdDSA,
+			AccessTokenTTL:   15 * time.Minute,
+			RefreshTokenTTL:  24 * time.Hour,
+		},
+		Login: configs.LoginConfig{
+			MaxFailedAttempts:   5,
+			IPMaxFailedAttempts: 20,
+			LockoutBase:         time.Minute,
+			LockoutMax:          time.Hour,
+		},
+	}
+	log := logger.NewLogger()
+
+	signer, err := services.NewTokenSigner(config, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &authFixture{
+		store: services.NewMemoryRevocationStore(),
+		user: &models.User{
+			ID:       1,
+			Name:     "Ana",
+			Email:    "ana@example.com",
+			Password: string(hash),
+			Status:   models.UserStatusActive,
+		},
+	}
+	ipAttempts := services.NewMemoryAttemptTracker(config.Login.IPMaxFailedAttempts, config.Login.LockoutBase, config.Login.LockoutMax)
+	f.users = &fakeUserRepo{user: f.user}
+	f.service = services.NewAuthService(f.users, &fakeSessionRepo{}, f.store, signer, nil, ipAttempts, nil, discardAudit{}, log, config)
+
+	gin.SetMode(gin.TestMode)
+	f.router = gin.New()
+	f.router.GET("/api/me", middleware.AuthMiddleware(f.service, nil, discardAudit{}, log), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return f
+}
+
+// login abre uma nova sessão para o usuário do fixture
+func (f *authFixture) login(t *testing.T) *services.TokenPair {
+	t.Helper()
+
+	result, err := f.service.Login(f.user.Email, testPassword, services.SessionMeta{IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return result.Tokens
+}
+
+// get faz uma requisição autenticada por accessToken a uma rota protegida pelo AuthMiddleware
+func (f *authFixture) get(accessToken string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuthMiddlewareRejectsRevokedJTI(t *testing.T) {
+	f := newAuthFixture(t)
+	tokens := f.login(t)
+
+	if code := f.get(tokens.AccessToken); code != http.StatusOK {
+		t.Fatalf("status = %d antes da revogação, esperado 200", code)
+	}
+
+	claims, err := f.service.ValidateAccessToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.store.Revoke(claims.ID, claims.ExpiresAt.Time); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.service.ValidateAccessToken(tokens.AccessToken); err != apperrors.ErrTokenRevoked {
+		t.Fatalf("erro = %v, esperado ErrTokenRevoked", err)
+	}
+	if code := f.get(tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Fatalf("status = %d com jti revogado, esperado 401", code)
+	}
+}
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	f := newAuthFixture(t)
+	tokens := f.login(t)
+
+	claims, err := f.service.ValidateAccessToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.service.Logout(claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := f.get(tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Fatalf("status = %d após o logout, esperado 401", code)
+	}
+	if _, err := f.service.RefreshToken(tokens.RefreshToken, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken para o refresh token da sessão encerrada", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	original := f.login(t)
+	other := f.login(t)
+
+	rotated, err := f.service.RefreshToken(original.RefreshToken, services.SessionMeta{})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	// Reapresentar o refresh token já rotacionado indica roubo: toda a família é revogada
+	if _, err := f.service.RefreshToken(original.RefreshToken, services.SessionMeta{}); err != apperrors.ErrRefreshTokenReused {
+		t.Fatalf("erro = %v, esperado ErrRefreshTokenReused", err)
+	}
+
+	if _, err := f.service.RefreshToken(rotated.RefreshToken, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken para o refresh token mais recente da família", err)
+	}
+	for _, accessToken := range []string{original.AccessToken, rotated.AccessToken} {
+		if code := f.get(accessToken); code != http.StatusUnauthorized {
+			t.Fatalf("status = %d para access token da família revogada, esperado 401", code)
+		}
+	}
+
+	// As demais sessões do usuário continuam válidas
+	if code := f.get(other.AccessToken); code != http.StatusOK {
+		t.Fatalf("status = %d para outra sessão, esperado 200", code)
+	}
+	if _, err := f.service.RefreshToken(other.RefreshToken, services.SessionMeta{}); err != nil {
+		t.Fatalf("RefreshToken de outra sessão: %v", err)
+	}
+}
+
+func TestRevokeAllSessionsInvalidatesEverySession(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	second := f.login(t)
+	rotated, err := f.service.RefreshToken(second.RefreshToken, services.SessionMeta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.service.RevokeAllSessions(f.user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tokens := range []*services.TokenPair{first, second, rotated} {
+		if code := f.get(tokens.AccessToken); code != http.StatusUnauthorized {
+			t.Fatalf("status = %d após encerrar todas as sessões, esperado 401", code)
+		}
+	}
+	for _, refreshToken := range []string{first.RefreshToken, rotated.RefreshToken} {
+		if _, err := f.service.RefreshToken(refreshToken, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+			t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+		}
+	}
+
+	sessions, err := f.service.ListSessions(f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("%d sessões ativas após encerrar todas, esperado 0", len(sessions))
+	}
+}
+
+func TestRevokeSessionInvalidatesItsTokens(t *testing.T) {
+	f := newAuthFixture(t)
+	revoked := f.login(t)
+	kept := f.login(t)
+
+	sessions, err := f.service.ListSessions(f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("%d sessões ativas, esperado 2", len(sessions))
+	}
+
+	claims, err := f.service.ValidateAccessToken(revoked.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.service.RevokeSession(f.user.ID+1, claims.SessionID); err != apperrors.ErrSessionNotFound {
+		t.Fatalf("erro = %v, esperado ErrSessionNotFound ao revogar sessão de outro usuário", err)
+	}
+	if err := f.service.RevokeSession(f.user.ID, claims.SessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := f.get(revoked.AccessToken); code != http.StatusUnauthorized {
+		t.Fatalf("status = %d para a sessão revogada, esperado 401", code)
+	}
+	if code := f.get(kept.AccessToken); code != http.StatusOK {
+		t.Fatalf("status = %d para a sessão mantida, esperado 200", code)
+	}
+	if sessions, _ := f.service.ListSessions(f.user.ID); len(sessions) != 1 {
+		t.Fatalf("%d sessões ativas após revogar uma, esperado 1", len(sessions))
+	}
+}
+
+func TestLoginLocksAccountAfterConsecutiveFailures(t *testing.T) {
+	f := newAuthFixture(t)
+	meta := services.SessionMeta{IP: "127.0.0.1"}
+
+	for i := 0; i < 5; i++ {
+		if _, err := f.service.Login(f.user.Email, "senha-errada", meta); err != apperrors.ErrInvalidCredentials {
+			t.Fatalf("tentativa %d: erro = %v, esperado ErrInvalidCredentials", i+1, err)
+		}
+	}
+	if f.user.FailedLoginAttempts != 5 || f.user.LockedUntil == nil {
+		t.Fatalf("conta não bloqueada após 5 falhas: %d falhas, bloqueio %v", f.user.FailedLoginAttempts, f.user.LockedUntil)
+	}
+
+	// Com a conta bloqueada, nem a senha correta é aceita
+	var lockout *services.LockoutError
+	if _, err := f.service.Login(f.user.Email, testPassword, meta); !errors.As(err, &lockout) {
+		t.Fatalf("erro = %v, esperado LockoutError", err)
+	}
+}
+
+func TestLoginResetsFailedAttempts(t *testing.T) {
+	f := newAuthFixture(t)
+	meta := services.SessionMeta{IP: "127.0.0.1"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.service.Login(f.user.Email, "senha-errada", meta); err != apperrors.ErrInvalidCredentials {
+			t.Fatalf("erro = %v, esperado ErrInvalidCredentials", err)
+		}
+	}
+	f.login(t)
+
+	if f.users.resets != 1 || f.users.failures != 0 || f.user.FailedLoginAttempts != 0 {
+		t.Fatalf("contador não zerado após o login: %d resets, %d falhas", f.users.resets, f.users.failures)
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Erros do serviço de atividades dos clientes
+var (
+	ErrActivityNotFound    = errors.New("atividade não encontrada")
+	ErrActivityInvalidLink = errors.New("a tarefa ou o pagamento informado não pertence ao cliente")
+	ErrActivityReadOnly    = errors.New("atividades registradas pelo sistema não podem ser alteradas")
+	ErrInvalidCursor       = errors.New("cursor inválido")
+)
+
+// ActivityInput representa os dados de registro ou edição de uma atividade.
+// OccurredAt zero significa o momento do registro.
+type ActivityInput struct {
+	Type       models.ActivityType
+	Body       string
+	OccurredAt time.Time
+	TaskID     *uint
+	PaymentID  *uint
+}
+
+// ClientTimeline representa uma página da linha do tempo do cliente
+type ClientTimeline struct {
+	Entries    []repository.TimelineEntry
+	NextCursor string // vazio na última página
+}
+
+// ClientActivityService define a interface do serviço de atividades e da linha do tempo dos clientes
+type ClientActivityService interface {
+	Timeline(userID, clientID uint, cursor string, limit int) (*ClientTimeline, error)
+	Create(userID, clientID uint, input ActivityInput) (*models.ClientActivity, error)
+	Update(userID, clientID, id uint, input ActivityInput) (*models.ClientActivity, error)
+	Delete(userID, clientID, id uint) error
+}
+
+// clientActivityService implementa a interface ClientActivityService
+type clientActivityService struct {
+	activityRepo  repository.ClientActivityRepository
+	clientService ClientService
+	logger        logger.Logger
+}
+
+// NewClientActivityService cria uma nova instância de ClientActivityService
+func NewClientActivityService(activityRepo repository.ClientActivityRepository, clientService ClientService, logger logger.Logger) ClientActivityService {
+	return &clientActivityService{
+		activityRepo:  activityRepo,
+		clientService: clientService,
+		logger:        logger,
+	}
+}
+
+// Timeline retorna uma página da linha do tempo do cliente, do item mais recente para o mais antigo
+func (s *clientActivityService) Timeline(userID, clientID uint, cursor string, limit int) (*ClientTimeline, error) {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var after *repository.TimelineCursor
+	if cursor != "" {
+		decoded, err := decodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = decoded
+	}
+
+	// Busca um item a mais para saber se existe uma próxima página
+	entries, err := s.activityRepo.Timeline(userID, clientID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &ClientTimeline{Entries: entries}
+	if len(entries) > limit {
+		timeline.Entries = entries[:limit]
+		last := timeline.Entries[limit-1]
+		timeline.NextCursor = encodeTimelineCursor(repository.TimelineCursor{OccurredAt: last.OccurredAt, Kind: last.Kind, ID: last.ID})
+	}
+	if timeline.Entries == nil {
+		timeline.Entries = []repository.TimelineEntry{}
+	}
+
+	return timeline, nil
+}
+
+// Create registra uma atividade no cliente do usuário
+func (s *clientActivityService) Create(userID, clientID uint, input ActivityInput) (*models.ClientActivity, error) {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	activity := &models.ClientActivity{
+		UserID:   userID,
+		ClientID: clientID,
+	}
+	if err := s.applyInput(activity, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.activityRepo.Create(activity); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Atividade %d registrada no cliente %d", activity.ID, clientID))
+	return activity, nil
+}
+
+// Update altera uma atividade registrada pelo usuário
+func (s *clientActivityService) Update(userID, clientID, id uint, input ActivityInput) (*models.ClientActivity, error) {
+	activity, err := s.getActivity(userID, clientID, id)
+	if err != nil {
+		return nil, err
+	}
+	if activity.Type.IsSystem() {
+		return nil, ErrActivityReadOnly
+	}
+
+	if err := s.applyInput(activity, input); err != nil {
+		return nil, err
+	}
+	if err := s.activityRepo.Update(activity); err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// Delete remove uma atividade registrada pelo usuário
+func (s *clientActivityService) Delete(userID, clientID, id uint) error {
+	activity, err := s.getActivity(userID, clientID, id)
+	if err != nil {
+		return err
+	}
+	if activity.Type.IsSystem() {
+		return ErrActivityReadOnly
+	}
+
+	deleted, err := s.activityRepo.Delete(clientID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrActivityNotFound
+	}
+
+	s.logger.Info(fmt.Sprintf("Atividade %d removida do cliente %d", id, clientID))
+	return nil
+}
+
+// getActivity busca a atividade, verificando se o cliente pertence ao usuário
+func (s *clientActivityService) getActivity(userID, clientID, id uint) (*models.ClientActivity, error) {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	activity, err := s.activityRepo.GetByID(clientID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, err
+	}
+	return activity, nil
+}
+
+// applyInput copia os dados informados para a atividade, verificando os vínculos com tarefa e pagamento
+func (s *clientActivityService) applyInput(activity *models.ClientActivity, input ActivityInput) error {
+	ok, err := s.activityRepo.LinksBelongToClient(activity.UserID, activity.ClientID, input.TaskID, input.PaymentID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrActivityInvalidLink
+	}
+
+	activity.Type = input.Type
+	activity.Body = strings.TrimSpace(input.Body)
+	activity.TaskID = input.TaskID
+	activity.PaymentID = input.PaymentID
+	activity.OccurredAt = input.OccurredAt
+	if activity.OccurredAt.IsZero() {
+		activity.OccurredAt = time.Now()
+	}
+	return nil
+}
+
+// encodeTimelineCursor gera o cursor opaco que aponta para o item informado
+func encodeTimelineCursor(cursor repository.TimelineCursor) string {
+	raw := fmt.Sprintf("%d|%s|%d", cursor.OccurredAt.UnixNano(), cursor.Kind, cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTimelineCursor lê o cursor gerado por encodeTimelineCursor
+func decodeTimelineCursor(cursor string) (*repository.TimelineCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 || parts[1] == "" {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repository.TimelineCursor{
+		OccurredAt: time.Unix(0, nanos),
+		Kind:       parts[1],
+		ID:         uint(id),
+	}, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Erros do serviço de contatos dos clientes
+var (
+	ErrContactNotFound = errors.New("contato não encontrado")
+	// ErrContactFlagConflict é retornado quando outro contato do cliente foi marcado como principal ou
+	// de cobrança ao mesmo tempo
+	ErrContactFlagConflict = errors.New("outro contato do cliente acabou de receber a mesma marcação")
+)
+
+// ContactInput representa os dados de criação ou edição de um contato
+type ContactInput struct {
+	Name      string
+	Role      string
+	Email     string
+	Phone     string
+	IsPrimary bool
+	IsBilling bool
+}
+
+// BillingRecipient representa o destinatário das cobranças e dos lembretes de pagamento de um cliente
+type BillingRecipient struct {
+	Name  string
+	Email string
+}
+
+// ClientContactService define a interface do serviço de contatos dos clientes
+type ClientContactService interface {
+	List(userID, clientID uint) ([]models.ClientContact, error)
+	Create(userID, clientID uint, input ContactInput) (*models.ClientContact, error)
+	Update(userID, clientID, id uint, input ContactInput) (*models.ClientContact, error)
+	Delete(userID, clientID, id uint) error
+	// BillingRecipient retorna quem deve receber as cobranças e os lembretes do cliente: o contato
+	// de cobrança, se houver um com e-mail, ou o e-mail do próprio cliente
+	BillingRecipient(userID, clientID uint) (*BillingRecipient, error)
+}
+
+// clientContactService implementa a interface ClientContactService
+type clientContactService struct {
+	contactRepo   repository.ClientContactRepository
+	clientService ClientService
+	logger        logger.Logger
+}
+
+// NewClientContactService cria uma nova instância de ClientContactService
+func NewClientContactService(contactRepo repository.ClientContactRepository, clientService ClientService, logger logger.Logger) ClientContactService {
+	return &clientContactService{
+		contactRepo:   contactRepo,
+		clientService: clientService,
+		logger:        logger,
+	}
+}
+
+// List retorna os contatos do cliente do usuário
+func (s *clientContactService) List(userID, clientID uint) ([]models.ClientContact, error) {
+	// Verifica se o cliente existe e pertence ao usuário
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.contactRepo.ListByClient(clientID)
+}
+
+// Create adiciona um contato ao cliente do usuário
+func (s *clientContactService) Create(userID, clientID uint, input ContactInput) (*models.ClientContact, error) {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	contact := &models.ClientContact{
+		UserID:   userID,
+		ClientID: clientID,
+	}
+	applyContactInput(contact, input)
+
+	if err := s.contactRepo.Create(contact); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, ErrContactFlagConflict
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Contato %d adicionado ao cliente %d", contact.ID, clientID))
+	return contact, nil
+}
+
+// Update altera um contato do cliente do usuário
+func (s *clientContactService) Update(userID, clientID, id uint, input ContactInput) (*models.ClientContact, error) {
+	contact, err := s.getContact(userID, clientID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyContactInput(contact, input)
+	if err := s.contactRepo.Update(contact); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, ErrContactFlagConflict
+		}
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// Delete remove um contato do cliente do usuário
+func (s *clientContactService) Delete(userID, clientID, id uint) error {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.contactRepo.Delete(clientID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrContactNotFound
+	}
+
+	s.logger.Info(fmt.Sprintf("Contato %d removido do cliente %d", id, clientID))
+	return nil
+}
+
+// BillingRecipient escolhe o destinatário das cobranças do cliente
+func (s *clientContactService) BillingRecipient(userID, clientID uint) (*BillingRecipient, error) {
+	client, err := s.clientService.GetByID(clientID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := s.contactRepo.GetBillingContact(client.ID)
+	if err != nil && !errors.Is(err, models.ErrRecordNotFound) {
+		return nil, err
+	}
+	if contact != nil && contact.Email != "" {
+		return &BillingRecipient{Name: contact.Name, Email: contact.Email}, nil
+	}
+
+	return &BillingRecipient{Name: client.Name, Email: client.Email}, nil
+}
+
+// getContact busca o contato, verificando se o cliente pertence ao usuário
+func (s *clientContactService) getContact(userID, clientID, id uint) (*models.ClientContact, error) {
+	if _, err := s.clientService.GetByID(clientID, userID); err != nil {
+		return nil, err
+	}
+
+	contact, err := s.contactRepo.GetByID(clientID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, err
+	}
+	return contact, nil
+}
+
+// applyContactInput copia os dados informados para o contato
+func applyContactInput(contact *models.ClientContact, input ContactInput) {
+	contact.Name = strings.TrimSpace(input.Name)
+	contact.Role = strings.TrimSpace(input.Role)
+	contact.Email = strings.TrimSpace(input.Email)
+	contact.Phone = strings.TrimSpace(input.Phone)
+	contact.IsPrimary = input.IsPrimary
+	contact.IsBilling = input.IsBilling
+}
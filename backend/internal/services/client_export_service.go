@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/vcard"
+)
+
+// Formatos de exportação de clientes
+const (
+	ExportFormatVCard = "vcf"
+	ExportFormatCSV   = "csv"
+)
+
+// ErrExportUnknownFormat é retornado quando o formato de exportação não é suportado
+var ErrExportUnknownFormat = errors.New("formato de exportação não suportado (use vcf ou csv)")
+
+// clientExportColumns são as colunas do CSV exportado; os cabeçalhos coincidem com os nomes aceitos na importação
+var clientExportColumns = []string{"name", "email", "phone", "company", "address", "notes", "status", "created_at"}
+
+// ClientExportService define a interface do serviço de exportação de clientes
+type ClientExportService interface {
+	// Export grava em w os clientes do usuário que atendem aos filtros, na ordem da listagem,
+	// um a um, sem carregar todos em memória
+	Export(userID uint, filter repository.ClientFilter, format string, w io.Writer) error
+}
+
+// clientExportService implementa a interface ClientExportService
+type clientExportService struct {
+	clientRepo repository.ClientRepository
+	logger     logger.Logger
+}
+
+// NewClientExportService cria uma nova instância de ClientExportService
+func NewClientExportService(clientRepo repository.ClientRepository, logger logger.Logger) ClientExportService {
+	return &clientExportService{
+		clientRepo: clientRepo,
+		logger:     logger,
+	}
+}
+
+// Export exporta os clientes no formato informado
+func (s *clientExportService) Export(userID uint, filter repository.ClientFilter, format string, w io.Writer) error {
+	switch format {
+	case ExportFormatVCard:
+		return s.exportVCard(userID, filter, w)
+	case ExportFormatCSV:
+		return s.exportCSV(userID, filter, w)
+	default:
+		return ErrExportUnknownFormat
+	}
+}
+
+// exportVCard grava um contato vCard 3.0 por cliente
+func (s *clientExportService) exportVCard(userID uint, filter repository.ClientFilter, w io.Writer) error {
+	writer := vcard.NewWriter(w)
+	err := s.clientRepo.Export(userID, filter, func(client *models.Client) error {
+		return writer.Write(clientCard(client))
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// exportCSV grava o CSV com cabeçalho e BOM do UTF-8, para que o Excel reconheça os acentos
+func (s *clientExportService) exportCSV(userID uint, filter repository.ClientFilter, w io.Writer) error {
+	// O buffer segura o BOM e o cabeçalho até as primeiras linhas, de modo que um erro na consulta
+	// ainda possa ser respondido como erro
+	buffered := bufio.NewWriter(w)
+	if _, err := buffered.WriteString("\xef\xbb\xbf"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(buffered)
+	if err := writer.Write(clientExportColumns); err != nil {
+		return err
+	}
+
+	err := s.clientRepo.Export(userID, filter, func(client *models.Client) error {
+		return writer.Write([]string{
+			spreadsheetSafe(client.Name),
+			spreadsheetSafe(client.Email),
+			spreadsheetSafe(client.Phone),
+			spreadsheetSafe(client.Company),
+			spreadsheetSafe(client.Address),
+			spreadsheetSafe(client.Notes),
+			string(client.Status),
+			client.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// clientCard converte o cliente em contato. O nome é dividido no último espaço entre nome e sobrenome,
+// para que as agendas ordenem pelo sobrenome.
+func clientCard(client *models.Client) *vcard.Card {
+	card := &vcard.Card{
+		FormattedName: client.Name,
+		Organization:  client.Company,
+		Note:          client.Notes,
+	}
+
+	name := strings.TrimSpace(client.Name)
+	if i := strings.LastIndex(name, " "); i > 0 {
+		card.Name = []string{name[i+1:], strings.TrimSpace(name[:i])}
+	} else {
+		card.Name = []string{"", name}
+	}
+
+	if client.Email != "" {
+		card.Emails = []string{client.Email}
+	}
+	if client.Phone != "" {
+		card.Phones = []string{client.Phone}
+	}
+	if client.Address != "" {
+		card.Addresses = []string{client.Address}
+	}
+	return card
+}
+
+// spreadsheetSafe evita que o valor seja interpretado como fórmula ao abrir o CSV em uma planilha,
+// prefixando-o com apóstrofo. Telefones como "+55 11 99999-0000" são mantidos.
+func spreadsheetSafe(value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if strings.Trim(value, "+-0123456789 ()") != "" {
+			return "'" + value
+		}
+	}
+	return value
+}
@@ -0,0 +1,528 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/vcard"
+)
+
+// Formatos de arquivo aceitos na importação de clientes
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatVCard = "vcard"
+)
+
+// MaxImportRows limita a quantidade de clientes de um arquivo de importação
+const MaxImportRows = 1000
+
+// Situação de cada linha do arquivo na importação
+const (
+	ImportRowValid     = "valid"     // será criada (simulação) ou foi criada
+	ImportRowDuplicate = "duplicate" // já existe um cliente, ou uma linha anterior, com o mesmo e-mail ou empresa
+	ImportRowInvalid   = "invalid"
+)
+
+// importFields são os campos do cliente que podem receber uma coluna do CSV
+var importFields = []string{"name", "email", "phone", "company", "address", "notes"}
+
+// importFieldAliases relaciona os cabeçalhos mais comuns (normalizados por normalizeHeader) a cada campo,
+// incluindo os das exportações do Google Contatos e do Outlook
+var importFieldAliases = map[string][]string{
+	"name":    {"name", "nome", "cliente", "nomecompleto", "fullname", "contato"},
+	"email":   {"email", "emailaddress", "email1value", "enderecodeemail"},
+	"phone":   {"phone", "telefone", "celular", "fone", "whatsapp", "mobile", "phonenumber", "phone1value", "mobilephone"},
+	"company": {"company", "empresa", "organizacao", "organization", "organizationname", "organization1name"},
+	"address": {"address", "endereco", "address1formatted"},
+	"notes":   {"notes", "observacoes", "observacao", "obs", "notas"},
+}
+
+// Erros do serviço de importação de clientes
+var (
+	ErrImportEmpty         = errors.New("o arquivo não tem clientes para importar")
+	ErrImportTooManyRows   = fmt.Errorf("o arquivo tem mais de %d clientes", MaxImportRows)
+	ErrImportUnknownFormat = errors.New("formato de importação não suportado (use csv ou vcard)")
+)
+
+// ImportFileError é retornado quando o arquivo ou o mapeamento de colunas não pode ser usado
+type ImportFileError struct {
+	Message string
+}
+
+// Error implementa a interface error
+func (e *ImportFileError) Error() string {
+	return "arquivo de importação inválido: " + e.Message
+}
+
+// ImportInput representa um pedido de importação. Mapping relaciona cada campo do cliente ao cabeçalho
+// da coluna do CSV; vazio, usa o mapeamento sugerido a partir dos cabeçalhos. O vCard não usa mapeamento.
+type ImportInput struct {
+	Format  string
+	File    io.Reader
+	Mapping map[string]string
+	DryRun  bool
+}
+
+// ImportValues representa os dados de um cliente lidos do arquivo
+type ImportValues struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	Company string `json:"company"`
+	Address string `json:"address"`
+	Notes   string `json:"notes"`
+}
+
+// ImportRowResult representa o resultado de uma linha do arquivo
+type ImportRowResult struct {
+	Row            int          `json:"row"` // linha da planilha (o cabeçalho é a 1) ou posição do contato no vCard
+	Status         string       `json:"status"`
+	Values         ImportValues `json:"values"`
+	Errors         []string     `json:"errors,omitempty"`
+	DuplicateOf    *uint        `json:"duplicate_of,omitempty"`     // cliente já cadastrado
+	DuplicateOfRow int          `json:"duplicate_of_row,omitempty"` // linha anterior do mesmo arquivo
+	ClientID       *uint        `json:"client_id,omitempty"`        // cliente criado
+}
+
+// ImportResult representa o resultado (ou a prévia, na simulação) de uma importação
+type ImportResult struct {
+	DryRun            bool              `json:"dry_run"`
+	Format            string            `json:"format"`
+	Columns           []string          `json:"columns,omitempty"` // cabeçalhos do CSV
+	Mapping           map[string]string `json:"mapping,omitempty"` // mapeamento usado
+	Total             int               `json:"total"`
+	Valid             int               `json:"valid"`
+	Duplicates        int               `json:"duplicates"`
+	Invalid           int               `json:"invalid"`
+	PlanLimitExceeded bool              `json:"plan_limit_exceeded"` // as linhas válidas ultrapassam o limite do plano
+	Rows              []ImportRowResult `json:"rows"`
+}
+
+// ClientImportService define a interface do serviço de importação de clientes
+type ClientImportService interface {
+	// Import lê o arquivo, valida as linhas e cria os clientes válidos que não sejam duplicados, todos
+	// em uma única transação. Com DryRun, apenas retorna a prévia do que seria criado.
+	Import(userID uint, input ImportInput) (*ImportResult, error)
+}
+
+// clientImportService implementa a interface ClientImportService
+type clientImportService struct {
+	clientRepo  repository.ClientRepository
+	planService PlanService
+	logger      logger.Logger
+}
+
+// NewClientImportService cria uma nova instância de ClientImportService
+func NewClientImportService(clientRepo repository.ClientRepository, planService PlanService, logger logger.Logger) ClientImportService {
+	return &clientImportService{
+		clientRepo:  clientRepo,
+		planService: planService,
+		logger:      logger,
+	}
+}
+
+// Import importa os clientes do arquivo
+func (s *clientImportService) Import(userID uint, input ImportInput) (*ImportResult, error) {
+	result := &ImportResult{DryRun: input.DryRun, Format: input.Format}
+
+	var rows []ImportRowResult
+	var err error
+	switch input.Format {
+	case ImportFormatCSV:
+		rows, err = readCSVImport(input.File, input.Mapping, result)
+	case ImportFormatVCard:
+		rows, err = readVCardImport(input.File)
+	default:
+		return nil, ErrImportUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(rows) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	for i := range rows {
+		validateImportRow(&rows[i])
+	}
+	result.Total = len(rows)
+	result.Rows = rows
+
+	// A verificação de duplicados, a do limite do plano e a criação acontecem com a linha do usuário bloqueada pela importação
+	var created []int // índice da linha de cada cliente criado
+	emails, companies := importDuplicateKeys(rows)
+	clients, err := s.clientRepo.Import(userID, emails, companies, func(existing []models.Client) ([]*models.Client, error) {
+		markDuplicates(rows, existing)
+
+		var clients []*models.Client
+		for i, row := range rows {
+			switch row.Status {
+			case ImportRowValid:
+				result.Valid++
+				clients = append(clients, importedClient(userID, row.Values))
+				created = append(created, i)
+			case ImportRowDuplicate:
+				result.Duplicates++
+			default:
+				result.Invalid++
+			}
+		}
+
+		if err := s.planService.CanCreateClient(userID, len(clients)); err != nil {
+			if !errors.Is(err, ErrClientLimitExceeded) || !input.DryRun {
+				return nil, err
+			}
+			result.PlanLimitExceeded = true
+		}
+
+		if input.DryRun {
+			return nil, nil
+		}
+		return clients, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if input.DryRun {
+		return result, nil
+	}
+
+	for i, client := range clients {
+		id := client.ID
+		result.Rows[created[i]].ClientID = &id
+	}
+
+	s.logger.Info(fmt.Sprintf("%d clientes importados (%s) pelo usuário %d", len(clients), input.Format, userID))
+	return result, nil
+}
+
+// importDuplicateKeys retorna os e-mails e as empresas, em minúsculas, das linhas válidas
+func importDuplicateKeys(rows []ImportRowResult) (emails, companies []string) {
+	for _, row := range rows {
+		if row.Status != ImportRowValid {
+			continue
+		}
+		if email := strings.ToLower(row.Values.Email); email != "" {
+			emails = append(emails, email)
+		}
+		if company := strings.ToLower(row.Values.Company); company != "" {
+			companies = append(companies, company)
+		}
+	}
+	return emails, companies
+}
+
+// markDuplicates marca as linhas válidas cujo e-mail ou empresa já pertence a um dos clientes existing
+// ou a uma linha anterior do arquivo
+func markDuplicates(rows []ImportRowResult, existing []models.Client) {
+	clientByEmail := make(map[string]uint)
+	clientByCompany := make(map[string]uint)
+	for _, client := range existing {
+		if client.Email != "" {
+			clientByEmail[strings.ToLower(client.Email)] = client.ID
+		}
+		if client.Company != "" {
+			clientByCompany[strings.ToLower(client.Company)] = client.ID
+		}
+	}
+
+	rowByEmail := make(map[string]int)
+	rowByCompany := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != ImportRowValid {
+			continue
+		}
+		email := strings.ToLower(row.Values.Email)
+		company := strings.ToLower(row.Values.Company)
+
+		switch {
+		case email != "" && clientByEmail[email] != 0:
+			row.markDuplicate(clientByEmail[email], 0, "já existe um cliente com este e-mail")
+		case company != "" && clientByCompany[company] != 0:
+			row.markDuplicate(clientByCompany[company], 0, "já existe um cliente desta empresa")
+		case email != "" && rowByEmail[email] != 0:
+			row.markDuplicate(0, rowByEmail[email], fmt.Sprintf("mesmo e-mail da linha %d", rowByEmail[email]))
+		case company != "" && rowByCompany[company] != 0:
+			row.markDuplicate(0, rowByCompany[company], fmt.Sprintf("mesma empresa da linha %d", rowByCompany[company]))
+		default:
+			if email != "" {
+				rowByEmail[email] = row.Row
+			}
+			if company != "" {
+				rowByCompany[company] = row.Row
+			}
+		}
+	}
+}
+
+// markDuplicate marca a linha como duplicada de um cliente ou de outra linha
+func (row *ImportRowResult) markDuplicate(clientID uint, otherRow int, message string) {
+	row.Status = ImportRowDuplicate
+	row.Errors = append(row.Errors, message)
+	if clientID != 0 {
+		row.DuplicateOf = &clientID
+	}
+	row.DuplicateOfRow = otherRow
+}
+
+// validateImportRow aplica aos dados da linha os limites de tamanho e o formato de e-mail do cadastro de
+// clientes. Ao contrário do cadastro, só o nome é obrigatório: planilhas e vCards costumam vir sem e-mail,
+// telefone ou endereço, que podem ser completados depois.
+func validateImportRow(row *ImportRowResult) {
+	v := &row.Values
+	var errs []string
+
+	switch n := utf8.RuneCountInString(v.Name); {
+	case n == 0:
+		errs = append(errs, "nome é obrigatório")
+	case n < 2 || n > 100:
+		errs = append(errs, "nome deve ter entre 2 e 100 caracteres")
+	}
+	if v.Email != "" {
+		if address, err := mail.ParseAddress(v.Email); err != nil || address.Address != v.Email {
+			errs = append(errs, "e-mail inválido")
+		} else if len(v.Email) > 100 {
+			errs = append(errs, "e-mail deve ter no máximo 100 caracteres")
+		}
+	}
+	if utf8.RuneCountInString(v.Phone) > 20 {
+		errs = append(errs, "telefone deve ter no máximo 20 caracteres")
+	}
+	if utf8.RuneCountInString(v.Company) > 100 {
+		errs = append(errs, "empresa deve ter no máximo 100 caracteres")
+	}
+	if utf8.RuneCountInString(v.Address) > 200 {
+		errs = append(errs, "endereço deve ter no máximo 200 caracteres")
+	}
+
+	row.Errors = errs
+	row.Status = ImportRowValid
+	if len(errs) > 0 {
+		row.Status = ImportRowInvalid
+	}
+}
+
+// importedClient monta o cliente ativo a partir dos dados da linha
+func importedClient(userID uint, v ImportValues) *models.Client {
+	return &models.Client{
+		UserID:       userID,
+		Name:         v.Name,
+		Email:        v.Email,
+		Phone:        v.Phone,
+		Company:      v.Company,
+		Address:      v.Address,
+		Notes:        v.Notes,
+		Status:       models.ClientActive,
+		CustomFields: models.CustomFieldValues{},
+	}
+}
+
+// readCSVImport lê as linhas do CSV aplicando o mapeamento de colunas. Aceita vírgula, ponto e vírgula
+// (padrão do Excel em português) ou tabulação como separador.
+func readCSVImport(file io.Reader, mapping map[string]string, result *ImportResult) ([]ImportRowResult, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")) // BOM do UTF-8, comum no Excel
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = detectCSVDelimiter(content)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, &ImportFileError{Message: err.Error()}
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+	result.Columns = headers
+
+	if len(mapping) == 0 {
+		mapping = suggestImportMapping(headers)
+	}
+	columns, err := importColumns(headers, mapping)
+	if err != nil {
+		return nil, err
+	}
+	result.Mapping = mapping
+
+	var rows []ImportRowResult
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ImportFileError{Message: err.Error()}
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) > MaxImportRows {
+			return nil, ErrImportTooManyRows
+		}
+
+		value := func(field string) string {
+			index, ok := columns[field]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		values := ImportValues{
+			Name:    value("name"),
+			Email:   value("email"),
+			Phone:   value("phone"),
+			Company: value("company"),
+			Address: value("address"),
+			Notes:   value("notes"),
+		}
+		if values == (ImportValues{}) {
+			continue // linha em branco
+		}
+		rows = append(rows, ImportRowResult{Row: line, Values: values})
+	}
+	return rows, nil
+}
+
+// importColumns valida o mapeamento e retorna a posição da coluna de cada campo
+func importColumns(headers []string, mapping map[string]string) (map[string]int, error) {
+	position := make(map[string]int, len(headers))
+	for i, header := range headers {
+		if _, ok := position[header]; !ok {
+			position[header] = i
+		}
+	}
+
+	columns := make(map[string]int, len(mapping))
+	for field, header := range mapping {
+		if !isImportField(field) {
+			return nil, &ImportFileError{Message: fmt.Sprintf("campo %q não pode ser importado (use %s)", field, strings.Join(importFields, ", "))}
+		}
+		if header == "" {
+			continue
+		}
+		index, ok := position[header]
+		if !ok {
+			return nil, &ImportFileError{Message: fmt.Sprintf("coluna %q não encontrada no arquivo", header)}
+		}
+		columns[field] = index
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, &ImportFileError{Message: "informe no mapeamento a coluna do nome do cliente"}
+	}
+	return columns, nil
+}
+
+// suggestImportMapping associa cada campo à primeira coluna cujo cabeçalho é um dos nomes conhecidos
+func suggestImportMapping(headers []string) map[string]string {
+	mapping := make(map[string]string)
+	for _, field := range importFields {
+		for _, header := range headers {
+			if containsString(importFieldAliases[field], normalizeHeader(header)) {
+				mapping[field] = header
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// accentReplacer remove os acentos mais comuns em português dos cabeçalhos
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+// normalizeHeader reduz o cabeçalho a letras e dígitos minúsculos sem acento ("E-mail 1 - Value" vira "email1value")
+func normalizeHeader(header string) string {
+	header = accentReplacer.Replace(strings.ToLower(header))
+	var b strings.Builder
+	for _, r := range header {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// detectCSVDelimiter escolhe o separador mais frequente na primeira linha do arquivo
+func detectCSVDelimiter(content []byte) rune {
+	firstLine, _ := bufio.NewReader(bytes.NewReader(content)).ReadString('\n')
+
+	delimiter, best := ',', strings.Count(firstLine, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if count := strings.Count(firstLine, string(candidate)); count > best {
+			delimiter, best = candidate, count
+		}
+	}
+	return delimiter
+}
+
+// readVCardImport converte os contatos do vCard em linhas de importação
+func readVCardImport(file io.Reader) ([]ImportRowResult, error) {
+	cards, err := vcard.Parse(file)
+	if err != nil {
+		return nil, &ImportFileError{Message: err.Error()}
+	}
+	if len(cards) > MaxImportRows {
+		return nil, ErrImportTooManyRows
+	}
+
+	rows := make([]ImportRowResult, 0, len(cards))
+	for i, card := range cards {
+		rows = append(rows, ImportRowResult{
+			Row: i + 1,
+			Values: ImportValues{
+				Name:    card.DisplayName(),
+				Email:   firstString(card.Emails),
+				Phone:   firstString(card.Phones),
+				Company: card.Organization,
+				Address: firstString(card.Addresses),
+				Notes:   card.Note,
+			},
+		})
+	}
+	return rows, nil
+}
+
+// isImportField indica se o campo pode receber uma coluna do CSV
+func isImportField(field string) bool {
+	return containsString(importFields, field)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func firstString(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Erros do serviço de mesclagem de clientes
+var (
+	ErrMergeSameClient     = errors.New("um cliente não pode ser mesclado com ele mesmo")
+	ErrMergeSourceNotFound = errors.New("cliente de origem não encontrado")
+)
+
+const (
+	// Pontuação mínima para que um par de clientes seja sugerido como duplicado
+	duplicateMinScore = 0.5
+	// Blocos maiores que isto (prefixo de nome ou domínio muito comuns) não são comparados par a par
+	duplicateMaxBlockSize = 50
+	// Quantos vizinhos, em ordem de nome, cada cliente de um bloco grande é comparado
+	duplicateBlockWindow = 10
+)
+
+// Motivos de uma sugestão de duplicidade
+const (
+	DuplicateReasonName        = "name"         // nomes iguais ou muito parecidos
+	DuplicateReasonCompany     = "company"      // mesma empresa
+	DuplicateReasonEmail       = "email"        // mesmo e-mail
+	DuplicateReasonEmailDomain = "email_domain" // mesmo domínio corporativo de e-mail
+	DuplicateReasonPhone       = "phone"        // mesmo telefone
+)
+
+// legalSuffixes são as terminações de razão social ignoradas ao comparar nomes ("Acme Ltda." e "ACME" são o mesmo cliente)
+var legalSuffixes = map[string]bool{
+	"ltda": true, "me": true, "mei": true, "epp": true, "eireli": true, "sa": true, "cia": true,
+	"inc": true, "llc": true, "ltd": true, "corp": true, "co": true, "gmbh": true,
+}
+
+// freeEmailDomains são provedores de e-mail pessoal, cujo domínio não indica que dois clientes são a mesma empresa
+var freeEmailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "hotmail.com": true, "hotmail.com.br": true, "outlook.com": true,
+	"outlook.com.br": true, "live.com": true, "msn.com": true, "yahoo.com": true, "yahoo.com.br": true,
+	"icloud.com": true, "me.com": true, "uol.com.br": true, "bol.com.br": true, "terra.com.br": true,
+	"ig.com.br": true, "protonmail.com": true, "proton.me": true,
+}
+
+// DuplicateSuggestion representa um par de clientes que provavelmente são o mesmo.
+// Client é o mais antigo e o destino sugerido para a mesclagem.
+type DuplicateSuggestion struct {
+	Client    models.Client `json:"client"`
+	Duplicate models.Client `json:"duplicate"`
+	Score     float64       `json:"score"`   // de 0,5 a 1
+	Reasons   []string      `json:"reasons"` // name, company, email, email_domain ou phone
+}
+
+// ClientMergeResult representa o cliente resultante da mesclagem e os registros transferidos
+type ClientMergeResult struct {
+	Client *models.Client               `json:"client"`
+	Moved  repository.ClientMergeCounts `json:"moved"`
+}
+
+// ClientMergeService define a interface do serviço de detecção e mesclagem de clientes duplicados
+type ClientMergeService interface {
+	Duplicates(userID uint, limit int) ([]DuplicateSuggestion, error)
+	Merge(userID, targetID, sourceID uint) (*ClientMergeResult, error)
+}
+
+// clientMergeService implementa a interface ClientMergeService
+type clientMergeService struct {
+	clientRepo    repository.ClientRepository
+	clientService ClientService
+	logger        logger.Logger
+}
+
+// NewClientMergeService cria uma nova instância de ClientMergeService
+func NewClientMergeService(clientRepo repository.ClientRepository, clientService ClientService, logger logger.Logger) ClientMergeService {
+	return &clientMergeService{
+		clientRepo:    clientRepo,
+		clientService: clientService,
+		logger:        logger,
+	}
+}
+
+// clientFingerprint guarda os dados normalizados de um cliente usados na comparação
+type clientFingerprint struct {
+	name    string
+	company string
+	email   string
+	domain  string // vazio para provedores de e-mail pessoal
+	phone   string // últimos 8 dígitos
+}
+
+// Duplicates sugere os pares de clientes do usuário que provavelmente são o mesmo, do mais provável para o menos
+func (s *clientMergeService) Duplicates(userID uint, limit int) ([]DuplicateSuggestion, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	clients, err := s.clientRepo.AllByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prints := make([]clientFingerprint, len(clients))
+	for i := range clients {
+		prints[i] = fingerprint(&clients[i])
+	}
+
+	// Só são comparados os clientes que compartilham alguma chave, em vez de todos os pares
+	blocks := make(map[string][]int)
+	for i, fp := range prints {
+		for _, key := range fp.blockingKeys() {
+			blocks[key] = append(blocks[key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+	suggestions := []DuplicateSuggestion{}
+	for _, members := range blocks {
+		// Num bloco grande, cada cliente é comparado só com os vizinhos em ordem de nome,
+		// o que mantém a busca linear no tamanho do bloco em vez de quadrática
+		window := len(members)
+		if len(members) > duplicateMaxBlockSize {
+			sort.SliceStable(members, func(a, b int) bool {
+				return prints[members[a]].name < prints[members[b]].name
+			})
+			window = duplicateBlockWindow
+		}
+
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members) && y-x <= window; y++ {
+				// O mais antigo (menor índice) fica sempre como cliente do par
+				pair := [2]int{members[x], members[y]}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+
+				score, reasons := duplicateScore(&prints[pair[0]], &prints[pair[1]])
+				if score < duplicateMinScore {
+					continue
+				}
+				suggestions = append(suggestions, DuplicateSuggestion{
+					Client:    clients[pair[0]],
+					Duplicate: clients[pair[1]],
+					Score:     score,
+					Reasons:   reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].Client.ID != suggestions[j].Client.ID {
+			return suggestions[i].Client.ID < suggestions[j].Client.ID
+		}
+		return suggestions[i].Duplicate.ID < suggestions[j].Duplicate.ID
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// Merge mescla o cliente sourceID ao cliente targetID: as tarefas, os pagamentos, os contatos e o histórico
+// passam para o destino, que fica com os dados mais completos dos dois, e o cliente de origem é removido
+func (s *clientMergeService) Merge(userID, targetID, sourceID uint) (*ClientMergeResult, error) {
+	if targetID == sourceID {
+		return nil, ErrMergeSameClient
+	}
+
+	target, err := s.clientService.GetByID(targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.clientService.GetByID(sourceID, userID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return nil, ErrMergeSourceNotFound
+		}
+		return nil, err
+	}
+
+	discarded := mergeClientFields(target, source)
+
+	body := fmt.Sprintf("Cliente %q (#%d) mesclado a este cliente", source.Name, source.ID)
+	if len(discarded) > 0 {
+		body += ". Valores não mantidos: " + strings.Join(discarded, "; ")
+	}
+	activity := &models.ClientActivity{
+		UserID:     userID,
+		ClientID:   target.ID,
+		Type:       models.ActivityMerge,
+		OccurredAt: time.Now(),
+		Body:       body,
+	}
+
+	moved, err := s.clientRepo.Merge(target, source.ID, activity)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mesclar clientes: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Cliente %d mesclado ao cliente %d", source.ID, target.ID))
+	return &ClientMergeResult{Client: target, Moved: *moved}, nil
+}
+
+// mergeClientFields copia para target os dados mais completos de source e retorna, para o histórico,
+// os valores preenchidos de source que não foram mantidos
+func mergeClientFields(target, source *models.Client) []string {
+	var discarded []string
+	keep := func(label string, current *string, other string) {
+		kept := richerValue(*current, other)
+		lost := strings.TrimSpace(other)
+		if kept == lost {
+			lost = strings.TrimSpace(*current)
+		}
+		if lost != "" && !strings.EqualFold(lost, kept) {
+			discarded = append(discarded, label+" "+lost)
+		}
+		*current = kept
+	}
+
+	keep("nome", &target.Name, source.Name)
+	keep("telefone", &target.Phone, source.Phone)
+	keep("empresa", &target.Company, source.Company)
+	keep("endereço", &target.Address, source.Address)
+
+	// O e-mail não fica "mais completo" por ser mais longo: o do destino é mantido se estiver preenchido
+	if strings.TrimSpace(target.Email) == "" {
+		target.Email = source.Email
+	} else if source.Email != "" && !strings.EqualFold(target.Email, source.Email) {
+		discarded = append(discarded, "e-mail "+source.Email)
+	}
+
+	targetNotes, sourceNotes := strings.TrimSpace(target.Notes), strings.TrimSpace(source.Notes)
+	switch {
+	case targetNotes == "":
+		target.Notes = sourceNotes
+	case sourceNotes != "" && !strings.Contains(targetNotes, sourceNotes):
+		target.Notes = targetNotes + "\n\n" + sourceNotes
+	}
+
+	// Um destino arquivado continua arquivado; a reativação passa por Unarchive
+	if target.Status != models.ClientArchived && clientStatusRank(source.Status) > clientStatusRank(target.Status) {
+		target.Status = source.Status
+	}
+
+	// Os campos personalizados do destino prevalecem; os vazios são completados com os da origem
+	if target.CustomFields == nil {
+		target.CustomFields = models.CustomFieldValues{}
+	}
+	for key, value := range source.CustomFields {
+		if current, ok := target.CustomFields[key]; !ok || current == nil || current == "" {
+			target.CustomFields[key] = value
+		}
+	}
+
+	tagIDs := make(map[uint]bool, len(target.Tags))
+	for _, tag := range target.Tags {
+		tagIDs[tag.ID] = true
+	}
+	for _, tag := range source.Tags {
+		if !tagIDs[tag.ID] {
+			target.Tags = append(target.Tags, tag)
+			tagIDs[tag.ID] = true
+		}
+	}
+
+	return discarded
+}
+
+// richerValue escolhe o valor mais completo: o preenchido ou, se os dois estiverem, o mais longo
+func richerValue(current, other string) string {
+	current, other = strings.TrimSpace(current), strings.TrimSpace(other)
+	if len([]rune(other)) > len([]rune(current)) {
+		return other
+	}
+	return current
+}
+
+// clientStatusRank ordena os status na mesclagem: um cliente ativo em qualquer dos registros continua ativo,
+// e uma origem arquivada não arquiva o destino
+func clientStatusRank(status models.ClientStatus) int {
+	switch status {
+	case models.ClientActive:
+		return 2
+	case models.ClientInactive:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// fingerprint normaliza os dados do cliente para a comparação
+func fingerprint(client *models.Client) clientFingerprint {
+	fp := clientFingerprint{
+		name:    normalizeOrganization(client.Name),
+		company: normalizeOrganization(client.Company),
+		email:   strings.ToLower(strings.TrimSpace(client.Email)),
+	}
+
+	if at := strings.LastIndex(fp.email, "@"); at > 0 {
+		if domain := fp.email[at+1:]; !freeEmailDomains[domain] {
+			fp.domain = domain
+		}
+	}
+
+	var digits strings.Builder
+	for _, r := range client.Phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	// Os últimos 8 dígitos ignoram DDI, DDD e o nono dígito dos celulares
+	if phone := digits.String(); len(phone) >= 8 {
+		fp.phone = phone[len(phone)-8:]
+	}
+	return fp
+}
+
+// blockingKeys retorna as chaves que agrupam os clientes candidatos a duplicados
+func (fp *clientFingerprint) blockingKeys() []string {
+	var keys []string
+	for _, name := range []string{fp.name, fp.company} {
+		if name == "" {
+			continue
+		}
+		keys = append(keys, "name:"+name)
+		// O prefixo aproxima nomes com pequenas diferenças de grafia
+		if prefix := []rune(name); len(prefix) >= 3 {
+			keys = append(keys, "prefix:"+string(prefix[:3]))
+		}
+	}
+	if fp.email != "" {
+		keys = append(keys, "email:"+fp.email)
+	}
+	if fp.domain != "" {
+		keys = append(keys, "domain:"+fp.domain)
+	}
+	if fp.phone != "" {
+		keys = append(keys, "phone:"+fp.phone)
+	}
+	return keys
+}
+
+// duplicateScore pontua a semelhança entre dois clientes, de 0 a 1, com os motivos
+func duplicateScore(a, b *clientFingerprint) (float64, []string) {
+	var score float64
+	var reasons []string
+	add := func(points float64, reason string) {
+		score += points
+		reasons = append(reasons, reason)
+	}
+
+	switch {
+	case a.name != "" && a.name == b.name:
+		add(0.6, DuplicateReasonName)
+	case len([]rune(a.name)) >= 4 && len([]rune(b.name)) >= 4 && similarity(a.name, b.name) >= 0.85:
+		add(0.4, DuplicateReasonName)
+	}
+
+	// A empresa de um pode ter sido cadastrada como o nome do outro
+	if (a.company != "" && (a.company == b.company || a.company == b.name)) || (b.company != "" && b.company == a.name) {
+		add(0.3, DuplicateReasonCompany)
+	}
+
+	switch {
+	case a.email != "" && a.email == b.email:
+		add(0.6, DuplicateReasonEmail)
+	case a.domain != "" && a.domain == b.domain:
+		add(0.3, DuplicateReasonEmailDomain)
+	}
+
+	if a.phone != "" && a.phone == b.phone {
+		add(0.5, DuplicateReasonPhone)
+	}
+
+	if score > 1 {
+		score = 1
+	}
+	return score, reasons
+}
+
+// normalizeOrganization reduz o nome a palavras minúsculas sem acento nem pontuação, ignorando as
+// terminações de razão social ("Acme Ltda." vira "acme")
+func normalizeOrganization(name string) string {
+	name = accentReplacer.Replace(strings.ToLower(name))
+	// Pontos e barras unem as siglas ("S.A." e "S/A" viram "sa"); a demais pontuação separa palavras
+	name = strings.NewReplacer(".", "", "/", "").Replace(name)
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// similarity retorna a semelhança entre dois textos, de 0 a 1, pela distância de Levenshtein
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return 1 - float64(previous[len(rb)])/float64(longest)
+}
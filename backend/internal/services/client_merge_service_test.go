@@ -0,0 +1,216 @@
+package services_test
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// fakeMergeClientRepo reproduz as consultas de repository.ClientRepository usadas na mesclagem
+type fakeMergeClientRepo struct {
+	repository.ClientRepository
+	clients  []models.Client
+	merged   *models.Client
+	activity *models.ClientActivity
+}
+
+func (r *fakeMergeClientRepo) AllByUser(userID uint) ([]models.Client, error) {
+	return r.clients, nil
+}
+
+func (r *fakeMergeClientRepo) Merge(target *models.Client, sourceID uint, activity *models.ClientActivity) (*repository.ClientMergeCounts, error) {
+	r.merged = target
+	r.activity = activity
+	return &repository.ClientMergeCounts{}, nil
+}
+
+// fakeMergeClientService devolve cópias dos clientes cadastrados, como o ClientService faria a cada busca
+type fakeMergeClientService struct {
+	services.ClientService
+	clients map[uint]models.Client
+}
+
+func (s *fakeMergeClientService) GetByID(id, userID uint) (*models.Client, error) {
+	client, ok := s.clients[id]
+	if !ok || client.UserID != userID {
+		return nil, services.ErrClientNotFound
+	}
+	return &client, nil
+}
+
+// mergeClients mescla source a target e retorna o cliente e a atividade gravados
+func mergeClients(t *testing.T, target, source models.Client) (*models.Client, *models.ClientActivity) {
+	t.Helper()
+
+	repo := &fakeMergeClientRepo{}
+	clients := &fakeMergeClientService{clients: map[uint]models.Client{target.ID: target, source.ID: source}}
+	service := services.NewClientMergeService(repo, clients, logger.NewLogger())
+
+	if _, err := service.Merge(target.UserID, target.ID, source.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	return repo.merged, repo.activity
+}
+
+func TestMergeKeepsRicherFields(t *testing.T) {
+	target := models.Client{
+		ID: 1, UserID: 1, Name: "Acme", Email: "contato@acme.com.br", Phone: "(11) 98765-4321",
+		Notes: "Prefere contato por e-mail", Status: models.ClientActive,
+		Tags:         []models.Tag{{ID: 1, Name: "vip"}},
+		CustomFields: models.CustomFieldValues{"segmento": "varejo", "cnpj": ""},
+	}
+	source := models.Client{
+		ID: 2, UserID: 1, Name: "Acme Comércio Ltda.", Email: "financeiro@acme.com.br", Phone: "98765-4321",
+		Address: "Rua das Flores, 100", Notes: "Paga no dia 10", Status: models.ClientActive,
+		Tags:         []models.Tag{{ID: 1, Name: "vip"}, {ID: 2, Name: "recorrente"}},
+		CustomFields: models.CustomFieldValues{"segmento": "indústria", "cnpj": "12.345.678/0001-90", "porte": "médio"},
+	}
+
+	merged, activity := mergeClients(t, target, source)
+
+	if merged.Name != "Acme Comércio Ltda." {
+		t.Errorf("nome = %q, esperado o mais completo", merged.Name)
+	}
+	if merged.Email != "contato@acme.com.br" {
+		t.Errorf("e-mail = %q, esperado o do destino", merged.Email)
+	}
+	if merged.Phone != "(11) 98765-4321" || merged.Address != "Rua das Flores, 100" {
+		t.Errorf("telefone = %q, endereço = %q", merged.Phone, merged.Address)
+	}
+	if merged.Notes != "Prefere contato por e-mail\n\nPaga no dia 10" {
+		t.Errorf("observações = %q, esperadas as dos dois clientes", merged.Notes)
+	}
+
+	wantFields := models.CustomFieldValues{"segmento": "varejo", "cnpj": "12.345.678/0001-90", "porte": "médio"}
+	if !reflect.DeepEqual(merged.CustomFields, wantFields) {
+		t.Errorf("campos personalizados = %v, esperado %v", merged.CustomFields, wantFields)
+	}
+	if len(merged.Tags) != 2 || merged.Tags[0].ID != 1 || merged.Tags[1].ID != 2 {
+		t.Errorf("tags = %v, esperada a união sem repetição", merged.Tags)
+	}
+
+	for _, lost := range []string{"nome Acme", "telefone 98765-4321", "e-mail financeiro@acme.com.br"} {
+		if !strings.Contains(activity.Body, lost) {
+			t.Errorf("histórico %q sem o valor descartado %q", activity.Body, lost)
+		}
+	}
+	if activity.ClientID != target.ID || activity.Type != models.ActivityMerge {
+		t.Errorf("atividade registrada no cliente %d com tipo %q", activity.ClientID, activity.Type)
+	}
+}
+
+func TestMergeStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		target models.ClientStatus
+		source models.ClientStatus
+		want   models.ClientStatus
+	}{
+		{"origem ativa reativa destino inativo", models.ClientInactive, models.ClientActive, models.ClientActive},
+		{"origem inativa não altera destino ativo", models.ClientActive, models.ClientInactive, models.ClientActive},
+		{"destino arquivado continua arquivado", models.ClientArchived, models.ClientActive, models.ClientArchived},
+		{"origem arquivada não arquiva o destino", models.ClientInactive, models.ClientArchived, models.ClientInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := models.Client{ID: 1, UserID: 1, Name: "Acme", Status: tt.target}
+			source := models.Client{ID: 2, UserID: 1, Name: "ACME", Status: tt.source}
+
+			merged, _ := mergeClients(t, target, source)
+			if merged.Status != tt.want {
+				t.Fatalf("status = %q, esperado %q", merged.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicatesScoring(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    models.Client
+		score   float64 // zero se o par não deve ser sugerido
+		reasons []string
+	}{
+		{
+			"razão social com e sem sufixo",
+			models.Client{Name: "Acme Ltda."}, models.Client{Name: "ACME"},
+			0.6, []string{services.DuplicateReasonName},
+		},
+		{
+			"mesmo e-mail com caixa diferente",
+			models.Client{Name: "Ana Lima", Email: "ana@acme.com.br"}, models.Client{Name: "Financeiro", Email: "Ana@Acme.com.br"},
+			0.6, []string{services.DuplicateReasonEmail},
+		},
+		{
+			"telefone com DDI e sem o nono dígito",
+			models.Client{Name: "Ana Lima", Phone: "(11) 98765-4321"}, models.Client{Name: "Beto Reis", Phone: "+55 11 8765-4321"},
+			0.5, []string{services.DuplicateReasonPhone},
+		},
+		{
+			"nome parecido e mesmo domínio corporativo",
+			models.Client{Name: "Construtora Horizonte", Email: "ana@horizonte.com.br"}, models.Client{Name: "Construtora Horizonet", Email: "beto@horizonte.com.br"},
+			0.7, []string{services.DuplicateReasonName, services.DuplicateReasonEmailDomain},
+		},
+		{
+			"empresa cadastrada como nome do outro",
+			models.Client{Name: "João Silva", Company: "Acme S.A.", Phone: "11 98765-4321"}, models.Client{Name: "ACME S/A", Phone: "1198765-4321"},
+			0.8, []string{services.DuplicateReasonCompany, services.DuplicateReasonPhone},
+		},
+		{
+			"pontuação limitada a 1",
+			models.Client{Name: "Acme", Email: "contato@acme.com.br", Phone: "11 98765-4321"}, models.Client{Name: "Acme", Email: "contato@acme.com.br", Phone: "11 98765-4321"},
+			1, []string{services.DuplicateReasonName, services.DuplicateReasonEmail, services.DuplicateReasonPhone},
+		},
+		{
+			"domínio de e-mail pessoal não conta",
+			models.Client{Name: "Ana Lima", Email: "ana@gmail.com"}, models.Client{Name: "Beto Reis", Email: "beto@gmail.com"},
+			0, nil,
+		},
+		{
+			"só o domínio corporativo fica abaixo do mínimo",
+			models.Client{Name: "Ana Lima", Email: "ana@acme.com.br"}, models.Client{Name: "Beto Reis", Email: "beto@acme.com.br"},
+			0, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.a.ID, tt.a.UserID = 1, 1
+			tt.b.ID, tt.b.UserID = 2, 1
+			repo := &fakeMergeClientRepo{clients: []models.Client{tt.a, tt.b}}
+			service := services.NewClientMergeService(repo, &fakeMergeClientService{}, logger.NewLogger())
+
+			suggestions, err := service.Duplicates(1, 20)
+			if err != nil {
+				t.Fatalf("Duplicates: %v", err)
+			}
+
+			if tt.score == 0 {
+				if len(suggestions) != 0 {
+					t.Fatalf("sugestões = %+v, esperado nenhuma", suggestions)
+				}
+				return
+			}
+			if len(suggestions) != 1 {
+				t.Fatalf("%d sugestões, esperada uma", len(suggestions))
+			}
+			got := suggestions[0]
+			if got.Client.ID != 1 || got.Duplicate.ID != 2 {
+				t.Errorf("par (%d, %d), esperado o mais antigo como destino", got.Client.ID, got.Duplicate.ID)
+			}
+			if math.Abs(got.Score-tt.score) > 1e-9 {
+				t.Errorf("pontuação = %v, esperado %v", got.Score, tt.score)
+			}
+			if !reflect.DeepEqual(got.Reasons, tt.reasons) {
+				t.Errorf("motivos = %v, esperado %v", got.Reasons, tt.reasons)
+			}
+		})
+	}
+}
@@ -11,49 +11,92 @@ import (
 
 // Erros comuns do serviço de clientes
 var (
-	ErrClientNotFound = errors.New("cliente não encontrado")
+	ErrClientNotFound        = errors.New("cliente não encontrado")
+	ErrClientAlreadyArchived = errors.New("cliente já está arquivado")
+	ErrClientNotArchived     = errors.New("cliente não está arquivado")
+	ErrClientNotInTrash      = errors.New("cliente não encontrado na lixeira")
 )
 
+// ClientOpenTasksError é retornado ao arquivar, sem cancelar as tarefas, um cliente com tarefas em aberto
+type ClientOpenTasksError struct {
+	Count int64
+}
+
+func (e *ClientOpenTasksError) Error() string {
+	return fmt.Sprintf("o cliente tem %d tarefa(s) em aberto", e.Count)
+}
+
+// ClientArchiveResult representa o cliente arquivado e as tarefas canceladas no arquivamento
+type ClientArchiveResult struct {
+	Client         *models.Client `json:"client"`
+	CancelledTasks int64          `json:"cancelled_tasks"`
+}
+
+// ClientInput representa os dados de criação ou edição de um cliente.
+// Na edição, TagIDs e CustomFields nil mantêm as tags e os campos personalizados atuais.
+type ClientInput struct {
+	Name         string
+	Email        string
+	Phone        string
+	Company      string
+	Address      string
+	Notes        string
+	Status       models.ClientStatus
+	TagIDs       []uint
+	CustomFields map[string]interface{}
+}
+
 // ClientService define a interface para o serviço de clientes
 type ClientService interface {
-	Create(userID uint, name, email, phone, address string, status models.ClientStatus) (*models.Client, error)
+	Create(userID uint, input ClientInput) (*models.Client, error)
 	GetByID(id, userID uint) (*models.Client, error)
 	GetByUserID(userID uint, page, pageSize int) ([]models.Client, int64, error)
-	Update(id, userID uint, name, email, phone, address string, status models.ClientStatus) (*models.Client, error)
+	Search(userID uint, filter repository.ClientFilter, page, pageSize int) ([]repository.ClientSearchResult, int64, error)
+	Update(id, userID uint, input ClientInput) (*models.Client, error)
 	Delete(id, userID uint) error
+	Archive(id, userID uint, cancelOpenTasks bool) (*ClientArchiveResult, error)
+	Unarchive(id, userID uint) (*models.Client, error)
+	Trash(userID uint, page, pageSize int) ([]repository.ClientTrashEntry, int64, error)
+	Restore(id, userID uint) (*models.Client, error)
+	Purge(id, userID uint) error
 	CountByUser(userID uint) (int64, error)
 }
 
 // clientService implementa a interface ClientService
 type clientService struct {
-	clientRepo repository.ClientRepository
-	planService PlanService
-	logger     logger.Logger
+	clientRepo         repository.ClientRepository
+	activityRepo       repository.ClientActivityRepository
+	planService        PlanService
+	tagService         TagService
+	customFieldService CustomFieldService
+	logger             logger.Logger
 }
 
 // NewClientService cria uma nova instância de ClientService
-func NewClientService(clientRepo repository.ClientRepository, planService PlanService, logger logger.Logger) ClientService {
+func NewClientService(clientRepo repository.ClientRepository, activityRepo repository.ClientActivityRepository, planService PlanService, tagService TagService, customFieldService CustomFieldService, logger logger.Logger) ClientService {
 	return &clientService{
-		clientRepo: clientRepo,
-		planService: planService,
-		logger:     logger,
+		clientRepo:         clientRepo,
+		activityRepo:       activityRepo,
+		planService:        planService,
+		tagService:         tagService,
+		customFieldService: customFieldService,
+		logger:             logger,
 	}
 }
 
 // Create cria um novo cliente
-func (s *clientService) Create(userID uint, name, email, phone, address string, status models.ClientStatus) (*models.Client, error) {
+func (s *clientService) Create(userID uint, input ClientInput) (*models.Client, error) {
 	// Verifica se o usuário pode criar mais clientes
-	if err := s.planService.CanCreateClient(userID); err != nil {
+	if err := s.planService.CanCreateClient(userID, 1); err != nil {
 		return nil, err
 	}
 
 	client := &models.Client{
-		UserID:  userID,
-		Name:    name,
-		Email:   email,
-		Phone:   phone,
-		Address: address,
-		Status:  status,
+		UserID:       userID,
+		CustomFields: models.CustomFieldValues{},
+	}
+	if err := s.applyInput(client, input); err != nil {
+		return nil, err
 	}
 
 	if err := s.clientRepo.Create(client); err != nil {
@@ -67,6 +110,9 @@ func (s *clientService) Create(userID uint, name, email, phone, address string,
 func (s *clientService) GetByID(id, userID uint) (*models.Client, error) {
 	client, err := s.clientRepo.GetByID(id)
 	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrClientNotFound
+		}
 		return nil, err
 	}
 
@@ -83,28 +129,56 @@ func (s *clientService) GetByUserID(userID uint, page, pageSize int) ([]models.C
 	return s.clientRepo.GetByUserID(userID, page, pageSize)
 }
 
+// NormalizeClientPage ajusta a página e o tamanho de página das listagens de clientes
+// (página mínima 1; tamanho entre 1 e 100, ou 20 fora disso)
+func NormalizeClientPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// Search busca os clientes do usuário com busca textual, filtros e ordenação
+func (s *clientService) Search(userID uint, filter repository.ClientFilter, page, pageSize int) ([]repository.ClientSearchResult, int64, error) {
+	page, pageSize = NormalizeClientPage(page, pageSize)
+
+	return s.clientRepo.Search(userID, filter, page, pageSize)
+}
+
 // Update atualiza um cliente existente
-func (s *clientService) Update(id, userID uint, name, email, phone, address string, status models.ClientStatus) (*models.Client, error) {
+func (s *clientService) Update(id, userID uint, input ClientInput) (*models.Client, error) {
 	client, err := s.GetByID(id, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	previousStatus := client.Status
+
+	// Um cliente arquivado continua arquivado na edição; a reativação passa por Unarchive
+	if previousStatus == models.ClientArchived {
+		input.Status = models.ClientArchived
+	}
+
 	// Atualiza os campos
-	client.Name = name
-	client.Email = email
-	client.Phone = phone
-	client.Address = address
-	client.Status = status
+	if err := s.applyInput(client, input); err != nil {
+		return nil, err
+	}
 
 	if err := s.clientRepo.Update(client); err != nil {
 		return nil, fmt.Errorf("erro ao atualizar cliente: %w", err)
 	}
 
+	if client.Status != previousStatus {
+		s.recordStatusChange(client, previousStatus, "")
+	}
+
 	return client, nil
 }
 
-// Delete remove um cliente
+// Delete remove um cliente, levando para a lixeira junto com ele as suas tarefas, pagamentos, contatos e histórico
 func (s *clientService) Delete(id, userID uint) error {
 	// Verifica se o cliente existe e pertence ao usuário
 	if _, err := s.GetByID(id, userID); err != nil {
@@ -114,7 +188,169 @@ func (s *clientService) Delete(id, userID uint) error {
 	return s.clientRepo.Delete(id)
 }
 
+// Archive arquiva o cliente, que deixa de aparecer nas listagens.
+// Com tarefas em aberto, o arquivamento é recusado, salvo se cancelOpenTasks for verdadeiro, quando elas são canceladas.
+// Os pagamentos não são alterados e os pendentes continuam sendo cobrados.
+func (s *clientService) Archive(id, userID uint, cancelOpenTasks bool) (*ClientArchiveResult, error) {
+	client, err := s.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Status == models.ClientArchived {
+		return nil, ErrClientAlreadyArchived
+	}
+
+	if !cancelOpenTasks {
+		open, err := s.clientRepo.CountOpenTasks(client.ID)
+		if err != nil {
+			return nil, err
+		}
+		if open > 0 {
+			return nil, &ClientOpenTasksError{Count: open}
+		}
+	}
+
+	previousStatus := client.Status
+	client.Status = models.ClientArchived
+	cancelled, err := s.clientRepo.UpdateStatus(client, cancelOpenTasks)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao arquivar cliente: %w", err)
+	}
+
+	detail := ""
+	if cancelled > 0 {
+		detail = fmt.Sprintf("%d tarefa(s) em aberto cancelada(s)", cancelled)
+	}
+	s.recordStatusChange(client, previousStatus, detail)
+
+	s.logger.Info(fmt.Sprintf("Cliente %d arquivado (%d tarefa(s) cancelada(s))", client.ID, cancelled))
+	return &ClientArchiveResult{Client: client, CancelledTasks: cancelled}, nil
+}
+
+// Unarchive reativa um cliente arquivado. As tarefas canceladas no arquivamento continuam canceladas.
+func (s *clientService) Unarchive(id, userID uint) (*models.Client, error) {
+	client, err := s.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Status != models.ClientArchived {
+		return nil, ErrClientNotArchived
+	}
+
+	client.Status = models.ClientActive
+	if _, err := s.clientRepo.UpdateStatus(client, false); err != nil {
+		return nil, fmt.Errorf("erro ao reativar cliente: %w", err)
+	}
+	s.recordStatusChange(client, models.ClientArchived, "")
+
+	return client, nil
+}
+
+// Trash lista os clientes removidos do usuário
+func (s *clientService) Trash(userID uint, page, pageSize int) ([]repository.ClientTrashEntry, int64, error) {
+	page, pageSize = NormalizeClientPage(page, pageSize)
+
+	return s.clientRepo.Trash(userID, page, pageSize)
+}
+
+// Restore traz de volta da lixeira o cliente e os registros removidos junto com ele.
+// Os clientes na lixeira não contam no limite do plano, então o restaurado precisa caber nele.
+func (s *clientService) Restore(id, userID uint) (*models.Client, error) {
+	client, err := s.getDeleted(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.planService.CanCreateClient(userID, 1); err != nil {
+		return nil, err
+	}
+
+	if err := s.clientRepo.Restore(client); err != nil {
+		return nil, fmt.Errorf("erro ao restaurar cliente: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Cliente %d restaurado da lixeira", client.ID))
+	return s.GetByID(client.ID, userID)
+}
+
+// Purge apaga definitivamente um cliente da lixeira, com as suas tarefas, pagamentos, contatos e histórico
+func (s *clientService) Purge(id, userID uint) error {
+	client, err := s.getDeleted(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.clientRepo.Purge(client.ID); err != nil {
+		return fmt.Errorf("erro ao apagar cliente: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Cliente %d apagado definitivamente", client.ID))
+	return nil
+}
+
+// getDeleted busca um cliente da lixeira, verificando se pertence ao usuário
+func (s *clientService) getDeleted(id, userID uint) (*models.Client, error) {
+	client, err := s.clientRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrClientNotInTrash
+		}
+		return nil, err
+	}
+	if client.UserID != userID {
+		return nil, ErrClientNotInTrash
+	}
+	return client, nil
+}
+
+// recordStatusChange registra a mudança de status na linha do tempo; uma falha aqui não desfaz a alteração
+func (s *clientService) recordStatusChange(client *models.Client, previousStatus models.ClientStatus, detail string) {
+	body := fmt.Sprintf("Status alterado de %s para %s", previousStatus, client.Status)
+	if detail != "" {
+		body += "; " + detail
+	}
+
+	activity := &models.ClientActivity{
+		UserID:     client.UserID,
+		ClientID:   client.ID,
+		Type:       models.ActivityStatusChange,
+		OccurredAt: client.UpdatedAt,
+		Body:       body,
+	}
+	if err := s.activityRepo.Create(activity); err != nil {
+		s.logger.Error(fmt.Sprintf("Erro ao registrar mudança de status do cliente %d: %v", client.ID, err))
+	}
+}
+
 // CountByUser conta o número de clientes por usuário
 func (s *clientService) CountByUser(userID uint) (int64, error) {
 	return s.clientRepo.CountByUser(userID)
 }
+
+// applyInput copia os dados informados para o cliente, validando as tags e os campos personalizados
+func (s *clientService) applyInput(client *models.Client, input ClientInput) error {
+	if input.TagIDs != nil {
+		tags, err := s.tagService.Resolve(client.UserID, input.TagIDs)
+		if err != nil {
+			return err
+		}
+		client.Tags = tags
+	}
+
+	if input.CustomFields != nil {
+		values, err := s.customFieldService.Validate(client.UserID, input.CustomFields)
+		if err != nil {
+			return err
+		}
+		client.CustomFields = values
+	}
+
+	client.Name = input.Name
+	client.Email = input.Email
+	client.Phone = input.Phone
+	client.Company = input.Company
+	client.Address = input.Address
+	client.Notes = input.Notes
+	client.Status = input.Status
+	return nil
+}
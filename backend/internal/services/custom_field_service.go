@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Erros comuns do serviço de campos personalizados
+var (
+	ErrCustomFieldNotFound  = errors.New("campo personalizado não encontrado")
+	ErrCustomFieldKeyInUse  = errors.New("já existe um campo personalizado com esta chave")
+	ErrCustomFieldNoOptions = errors.New("campos do tipo select precisam de ao menos uma opção")
+)
+
+// maxCustomTextLength limita o tamanho dos valores de campos do tipo texto
+const maxCustomTextLength = 1000
+
+// CustomFieldError é retornado quando o valor de um campo personalizado de um cliente é inválido
+type CustomFieldError struct {
+	Key     string
+	Message string
+}
+
+// Error implementa a interface error
+func (e *CustomFieldError) Error() string {
+	return fmt.Sprintf("campo personalizado %q: %s", e.Key, e.Message)
+}
+
+// CustomFieldOptionsInUseError é retornado ao remover de um campo select opções que ainda são o valor de algum cliente
+type CustomFieldOptionsInUseError struct {
+	Options []string
+	Clients int64
+}
+
+// Error implementa a interface error
+func (e *CustomFieldOptionsInUseError) Error() string {
+	return fmt.Sprintf("as opções %s estão em uso em %d cliente(s)", strings.Join(e.Options, ", "), e.Clients)
+}
+
+// CustomFieldService define a interface do serviço de campos personalizados dos clientes
+type CustomFieldService interface {
+	List(userID uint) ([]models.CustomFieldDefinition, error)
+	Create(userID uint, key, label string, fieldType models.CustomFieldType, options []string) (*models.CustomFieldDefinition, error)
+	Update(userID, id uint, label string, options []string) (*models.CustomFieldDefinition, error)
+	Delete(userID, id uint) error
+	// Validate confere os valores informados para um cliente contra os campos do usuário e retorna os
+	// valores normalizados. Valores nulos ou vazios removem o campo do cliente.
+	Validate(userID uint, values map[string]interface{}) (models.CustomFieldValues, error)
+}
+
+// customFieldService implementa a interface CustomFieldService
+type customFieldService struct {
+	fieldRepo repository.CustomFieldRepository
+	logger    logger.Logger
+}
+
+// NewCustomFieldService cria uma nova instância de CustomFieldService
+func NewCustomFieldService(fieldRepo repository.CustomFieldRepository, logger logger.Logger) CustomFieldService {
+	return &customFieldService{
+		fieldRepo: fieldRepo,
+		logger:    logger,
+	}
+}
+
+// List retorna os campos personalizados do usuário
+func (s *customFieldService) List(userID uint) ([]models.CustomFieldDefinition, error) {
+	return s.fieldRepo.ListByUser(userID)
+}
+
+// Create define um novo campo personalizado para os clientes do usuário
+func (s *customFieldService) Create(userID uint, key, label string, fieldType models.CustomFieldType, options []string) (*models.CustomFieldDefinition, error) {
+	options, err := normalizeOptions(fieldType, options)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &models.CustomFieldDefinition{
+		UserID:  userID,
+		Key:     key,
+		Label:   strings.TrimSpace(label),
+		Type:    fieldType,
+		Options: options,
+	}
+
+	if err := s.fieldRepo.Create(field); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, ErrCustomFieldKeyInUse
+		}
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// Update altera o rótulo e as opções de um campo. A chave e o tipo não mudam, para não invalidar
+// os valores já gravados nos clientes; pelo mesmo motivo, só é possível remover opções que nenhum cliente usa.
+func (s *customFieldService) Update(userID, id uint, label string, options []string) (*models.CustomFieldDefinition, error) {
+	field, err := s.getField(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err = normalizeOptions(field.Type, options)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(options))
+	for _, option := range options {
+		kept[option] = true
+	}
+	var removed []string
+	for _, option := range field.Options {
+		if !kept[option] {
+			removed = append(removed, option)
+		}
+	}
+
+	field.Label = strings.TrimSpace(label)
+	field.Options = options
+	inUse, err := s.fieldRepo.Update(field, removed)
+	if err != nil {
+		return nil, err
+	}
+	if inUse > 0 {
+		return nil, &CustomFieldOptionsInUseError{Options: removed, Clients: inUse}
+	}
+
+	return field, nil
+}
+
+// Delete remove o campo e o seu valor de todos os clientes
+func (s *customFieldService) Delete(userID, id uint) error {
+	field, err := s.getField(userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.fieldRepo.Delete(field); err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("Campo personalizado %q removido pelo usuário %d", field.Key, userID))
+	return nil
+}
+
+// Validate confere e normaliza os valores dos campos personalizados de um cliente
+func (s *customFieldService) Validate(userID uint, values map[string]interface{}) (models.CustomFieldValues, error) {
+	normalized := models.CustomFieldValues{}
+	if len(values) == 0 {
+		return normalized, nil
+	}
+
+	fields, err := s.fieldRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]*models.CustomFieldDefinition, len(fields))
+	for i := range fields {
+		definitions[fields[i].Key] = &fields[i]
+	}
+
+	for key, value := range values {
+		field, ok := definitions[key]
+		if !ok {
+			return nil, &CustomFieldError{Key: key, Message: "campo não definido"}
+		}
+		if value == nil || value == "" {
+			continue
+		}
+
+		normalizedValue, err := validateCustomValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[key] = normalizedValue
+	}
+
+	return normalized, nil
+}
+
+// getField busca o campo do usuário
+func (s *customFieldService) getField(userID, id uint) (*models.CustomFieldDefinition, error) {
+	field, err := s.fieldRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, err
+	}
+	return field, nil
+}
+
+// validateCustomValue confere o valor contra o tipo do campo. Datas são gravadas como AAAA-MM-DD.
+func validateCustomValue(field *models.CustomFieldDefinition, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case models.CustomFieldText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, &CustomFieldError{Key: field.Key, Message: "deve ser um texto"}
+		}
+		if utf8.RuneCountInString(text) > maxCustomTextLength {
+			return nil, &CustomFieldError{Key: field.Key, Message: fmt.Sprintf("deve ter no máximo %d caracteres", maxCustomTextLength)}
+		}
+		return text, nil
+
+	case models.CustomFieldNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, &CustomFieldError{Key: field.Key, Message: "deve ser um número"}
+		}
+		return number, nil
+
+	case models.CustomFieldDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, &CustomFieldError{Key: field.Key, Message: "deve ser uma data no formato AAAA-MM-DD"}
+		}
+		date, err := time.Parse("2006-01-02", text)
+		if err != nil {
+			return nil, &CustomFieldError{Key: field.Key, Message: "deve ser uma data no formato AAAA-MM-DD"}
+		}
+		return date.Format("2006-01-02"), nil
+
+	case models.CustomFieldSelect:
+		text, ok := value.(string)
+		if ok {
+			for _, option := range field.Options {
+				if option == text {
+					return text, nil
+				}
+			}
+		}
+		return nil, &CustomFieldError{Key: field.Key, Message: "deve ser uma das opções: " + strings.Join(field.Options, ", ")}
+	}
+
+	return nil, &CustomFieldError{Key: field.Key, Message: "tipo de campo desconhecido"}
+}
+
+// normalizeOptions limpa as opções de um campo select; os demais tipos não têm opções
+func normalizeOptions(fieldType models.CustomFieldType, options []string) (models.StringList, error) {
+	if fieldType != models.CustomFieldSelect {
+		return models.StringList{}, nil
+	}
+
+	seen := make(map[string]bool, len(options))
+	normalized := make(models.StringList, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			continue
+		}
+		seen[option] = true
+		normalized = append(normalized, option)
+	}
+
+	if len(normalized) == 0 {
+		return nil, ErrCustomFieldNoOptions
+	}
+	return normalized, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+)
+
+// Registros exportados com a data de remoção, omitida na API (json:"-") mas parte dos dados do titular
+type exportedClient struct {
+	models.Client
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type exportedContact struct {
+	models.ClientContact
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type exportedActivity struct {
+	models.ClientActivity
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type exportedTask struct {
+	models.Task
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type exportedPayment struct {
+	models.Payment
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+// buildDataArchive gera o ZIP da exportação: o perfil em JSON e clientes, contatos, atividades, tarefas e pagamentos
+// (inclusive os removidos) em JSON e CSV
+func buildDataArchive(user *models.User, data *repository.UserData, generatedAt time.Time) ([]byte, error) {
+	clients := make([]exportedClient, 0, len(data.Clients))
+	clientRows := [][]string{{"id", "name", "email", "phone", "company", "address", "notes", "status", "tags", "custom_fields", "created_at", "updated_at", "deleted_at"}}
+	for _, client := range data.Clients {
+		deletedAt := deletedTime(client.DeletedAt.Time, client.DeletedAt.Valid)
+		clients = append(clients, exportedClient{Client: client, DeletedAt: deletedAt})
+		tagNames := make([]string, 0, len(client.Tags))
+		for _, tag := range client.Tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+		customFields, err := json.Marshal(client.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		clientRows = append(clientRows, []string{
+			formatUint(client.ID), client.Name, client.Email, client.Phone, client.Company, client.Address, client.Notes,
+			string(client.Status), strings.Join(tagNames, "; "), string(customFields),
+			formatTime(&client.CreatedAt), formatTime(&client.UpdatedAt), formatTime(deletedAt),
+		})
+	}
+
+	contacts := make([]exportedContact, 0, len(data.Contacts))
+	contactRows := [][]string{{"id", "client_id", "name", "role", "email", "phone", "is_primary", "is_billing", "created_at", "updated_at", "deleted_at"}}
+	for _, contact := range data.Contacts {
+		deletedAt := deletedTime(contact.DeletedAt.Time, contact.DeletedAt.Valid)
+		contacts = append(contacts, exportedContact{ClientContact: contact, DeletedAt: deletedAt})
+		contactRows = append(contactRows, []string{
+			formatUint(contact.ID), formatUint(contact.ClientID), contact.Name, contact.Role, contact.Email, contact.Phone,
+			strconv.FormatBool(contact.IsPrimary), strconv.FormatBool(contact.IsBilling),
+			formatTime(&contact.CreatedAt), formatTime(&contact.UpdatedAt), formatTime(deletedAt),
+		})
+	}
+
+	activities := make([]exportedActivity, 0, len(data.Activities))
+	activityRows := [][]string{{"id", "client_id", "type", "occurred_at", "body", "task_id", "payment_id", "created_at", "updated_at", "deleted_at"}}
+	for _, activity := range data.Activities {
+		deletedAt := deletedTime(activity.DeletedAt.Time, activity.DeletedAt.Valid)
+		activities = append(activities, exportedActivity{ClientActivity: activity, DeletedAt: deletedAt})
+		activityRows = append(activityRows, []string{
+			formatUint(activity.ID), formatUint(activity.ClientID), string(activity.Type), formatTime(&activity.OccurredAt), activity.Body,
+			formatOptionalUint(activity.TaskID), formatOptionalUint(activity.PaymentID),
+			formatTime(&activity.CreatedAt), formatTime(&activity.UpdatedAt), formatTime(deletedAt),
+		})
+	}
+
+	tasks := make([]exportedTask, 0, len(data.Tasks))
+	taskRows := [][]string{{"id", "client_id", "title", "description", "status", "priority", "due_date", "start_date", "end_date", "estimated_hours", "actual_hours", "hourly_rate", "created_at", "updated_at", "deleted_at"}}
+	for _, task := range data.Tasks {
+		deletedAt := deletedTime(task.DeletedAt.Time, task.DeletedAt.Valid)
+		tasks = append(tasks, exportedTask{Task: task, DeletedAt: deletedAt})
+		taskRows = append(taskRows, []string{
+			formatUint(task.ID), formatUint(task.ClientID), task.Title, task.Description, string(task.Status), string(task.Priority),
+			formatTime(task.DueDate), formatTime(task.StartDate), formatTime(task.EndDate),
+			formatFloat(task.EstimatedHours), formatFloat(task.ActualHours), formatFloat(task.HourlyRate),
+			formatTime(&task.CreatedAt), formatTime(&task.UpdatedAt), formatTime(deletedAt),
+		})
+	}
+
+	payments := make([]exportedPayment, 0, len(data.Payments))
+	paymentRows := [][]string{{"id", "client_id", "task_id", "amount", "currency", "status", "method", "description", "invoice_number", "due_date", "paid_date", "created_at", "updated_at", "deleted_at"}}
+	for _, payment := range data.Payments {
+		deletedAt := deletedTime(payment.DeletedAt.Time, payment.DeletedAt.Valid)
+		payments = append(payments, exportedPayment{Payment: payment, DeletedAt: deletedAt})
+		paymentRows = append(paymentRows, []string{
+			formatUint(payment.ID), formatUint(payment.ClientID), formatOptionalUint(payment.TaskID), formatFloat(payment.Amount), payment.Currency,
+			string(payment.Status), string(payment.Method), payment.Description, payment.InvoiceNumber,
+			formatTime(&payment.DueDate), formatTime(payment.PaidDate),
+			formatTime(&payment.CreatedAt), formatTime(&payment.UpdatedAt), formatTime(deletedAt),
+		})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := []struct {
+		name  string
+		write func(*zipFile) error
+	}{
+		{"profile.json", func(f *zipFile) error { return f.json(user) }},
+		{"clients.json", func(f *zipFile) error { return f.json(clients) }},
+		{"clients.csv", func(f *zipFile) error { return f.csv(clientRows) }},
+		{"contacts.json", func(f *zipFile) error { return f.json(contacts) }},
+		{"contacts.csv", func(f *zipFile) error { return f.csv(contactRows) }},
+		{"activities.json", func(f *zipFile) error { return f.json(activities) }},
+		{"activities.csv", func(f *zipFile) error { return f.csv(activityRows) }},
+		{"tasks.json", func(f *zipFile) error { return f.json(tasks) }},
+		{"tasks.csv", func(f *zipFile) error { return f.csv(taskRows) }},
+		{"payments.json", func(f *zipFile) error { return f.json(payments) }},
+		{"payments.csv", func(f *zipFile) error { return f.csv(paymentRows) }},
+	}
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: generatedAt})
+		if err != nil {
+			return nil, err
+		}
+		if err := file.write(&zipFile{writer: writer}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zipFile escreve o conteúdo de um arquivo do ZIP
+type zipFile struct {
+	writer io.Writer
+}
+
+func (f *zipFile) json(value interface{}) error {
+	encoder := json.NewEncoder(f.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func (f *zipFile) csv(rows [][]string) error {
+	writer := csv.NewWriter(f.writer)
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = csvSafe(cell)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe impede que planilhas interpretem como fórmula um texto digitado pelo usuário
+func csvSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return "'" + cell
+		}
+	}
+	return cell
+}
+
+func deletedTime(t time.Time, valid bool) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatUint(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func formatOptionalUint(v *uint) string {
+	if v == nil {
+		return ""
+	}
+	return formatUint(*v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
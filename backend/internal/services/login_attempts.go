@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+)
+
+// LockoutError é retornado quando as tentativas de login estão temporariamente bloqueadas.
+// errors.Is(err, apperrors.ErrTooManyAttempts) é verdadeiro para este erro.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+// Error implementa a interface error
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s; tente novamente em %s", apperrors.ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap permite comparar o erro com apperrors.ErrTooManyAttempts
+func (e *LockoutError) Unwrap() error {
+	return apperrors.ErrTooManyAttempts
+}
+
+// LoginAttemptTracker contabiliza falhas de login por chave (IP ou e-mail) e aplica backoff exponencial
+type LoginAttemptTracker interface {
+	// Check retorna o tempo restante de bloqueio da chave (zero se liberada)
+	Check(key string) time.Duration
+	// RegisterFailure registra uma falha e retorna a duração do bloqueio aplicado (zero se nenhum)
+	RegisterFailure(key string) time.Duration
+	// Reset esquece as falhas registradas para a chave
+	Reset(key string)
+}
+
+// attemptEntry guarda as falhas de uma chave
+type attemptEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// memoryAttemptTracker implementa LoginAttemptTracker em memória
+type memoryAttemptTracker struct {
+	mu        sync.Mutex
+	entries   map[string]*attemptEntry
+	threshold int
+	base      time.Duration
+	max       time.Duration
+}
+
+// NewMemoryAttemptTracker cria um LoginAttemptTracker em memória. A partir de `threshold`
+// falhas, cada nova falha bloqueia a chave por base*2^(falhas-threshold), limitado a max.
+func NewMemoryAttemptTracker(threshold int, base, max time.Duration) LoginAttemptTracker {
+	return &memoryAttemptTracker{
+		entries:   make(map[string]*attemptEntry),
+		threshold: threshold,
+		base:      base,
+		max:       max,
+	}
+}
+
+// Check retorna o tempo restante de bloqueio da chave
+func (t *memoryAttemptTracker) Check(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return 0
+	}
+	return remaining(entry.lockedUntil)
+}
+
+// RegisterFailure registra uma falha para a chave
+func (t *memoryAttemptTracker) RegisterFailure(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.prune(now)
+
+	entry, ok := t.entries[key]
+	if !ok {
+		entry = &attemptEntry{}
+		t.entries[key] = entry
+	}
+
+	entry.failures++
+	entry.lastFailure = now
+
+	delay := lockoutDelay(entry.failures, t.threshold, t.base, t.max)
+	if delay > 0 {
+		entry.lockedUntil = now.Add(delay)
+	}
+	return delay
+}
+
+// Reset esquece as falhas registradas para a chave
+func (t *memoryAttemptTracker) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+}
+
+// prune remove as chaves sem falhas recentes, para que o contador decaia com o tempo
+func (t *memoryAttemptTracker) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if now.Sub(entry.lastFailure) > t.max && now.After(entry.lockedUntil) {
+			delete(t.entries, key)
+		}
+	}
+}
+
+// lockoutDelay calcula o bloqueio exponencial para a quantidade de falhas consecutivas
+func lockoutDelay(failures, threshold int, base, max time.Duration) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+
+	delay := base
+	for i := threshold; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// remaining retorna quanto falta até o instante informado (zero se já passou)
+func remaining(until time.Time) time.Duration {
+	if d := time.Until(until); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// checkPasswordWithBackoff confirma a senha de um usuário já autenticado (reautenticação),
+// bloqueando temporariamente após falhas consecutivas contabilizadas por ID do usuário
+func checkPasswordWithBackoff(attempts LoginAttemptTracker, user *models.User, password string) error {
+	key := strconv.FormatUint(uint64(user.ID), 10)
+	if retryAfter := attempts.Check(key); retryAfter > 0 {
+		return &LockoutError{RetryAfter: retryAfter}
+	}
+
+	if !user.CheckPassword(password) {
+		if lockout := attempts.RegisterFailure(key); lockout > 0 {
+			return &LockoutError{RetryAfter: lockout}
+		}
+		return apperrors.ErrInvalidPassword
+	}
+
+	attempts.Reset(key)
+	return nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/email"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// MagicLinkService define a interface do login sem senha por link enviado ao e-mail
+type MagicLinkService interface {
+	// RequestLink envia um link de login de uso único, com limite por e-mail e por IP.
+	// Não informa se o e-mail existe.
+	RequestLink(email, ip string) error
+	// Consume troca o token do link pelo mesmo resultado do login com senha
+	Consume(token string, meta SessionMeta) (*LoginResult, error)
+}
+
+// magicLinkService implementa a interface MagicLinkService
+type magicLinkService struct {
+	userRepo    models.UserRepository
+	linkRepo    repository.MagicLinkRepository
+	authService AuthService
+	mailer      email.EmailService
+	byEmail     RateLimiter
+	byIP        RateLimiter
+	logger      logger.Logger
+	config      *configs.Config
+}
+
+// NewMagicLinkService cria uma nova instância de MagicLinkService
+func NewMagicLinkService(
+	userRepo models.UserRepository,
+	linkRepo repository.MagicLinkRepository,
+	authService AuthService,
+	mailer email.EmailService,
+	logger logger.Logger,
+	config *configs.Config,
+) MagicLinkService {
+	return &magicLinkService{
+		userRepo:    userRepo,
+		linkRepo:    linkRepo,
+		authService: authService,
+		mailer:      mailer,
+		byEmail:     NewMemoryRateLimiter(config.Email.ResendLimit, config.Email.ResendWindow),
+		byIP:        NewMemoryRateLimiter(config.Email.ResendLimit*3, config.Email.ResendWindow),
+		logger:      logger,
+		config:      config,
+	}
+}
+
+// RequestLink gera o link e o envia ao e-mail informado.
+// Somente o hash SHA-256 do token é gravado; o token em si só existe no link enviado.
+func (s *magicLinkService) RequestLink(email, ip string) error {
+	if err := s.byIP.Allow(ip); err != nil {
+		return err
+	}
+	if err := s.byEmail.Allow(strings.ToLower(email)); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Status == models.UserStatusBlocked {
+		return nil
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.linkRepo.Create(&models.MagicLink{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		IP:        ip,
+		ExpiresAt: time.Now().Add(s.config.Email.MagicLinkTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(s.config.Email.AppURL, "/") + "/auth/magic-link?token=" + token
+	return s.mailer.SendMagicLink(user.Email, user.Name, link)
+}
+
+// Consume valida o link e conclui o login. O link é marcado como usado antes da emissão dos tokens,
+// de modo que requisições simultâneas com o mesmo link não passam as duas.
+func (s *magicLinkService) Consume(token string, meta SessionMeta) (*LoginResult, error) {
+	link, err := s.linkRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if link.IsUsed() {
+		return nil, apperrors.ErrInvalidToken
+	}
+	if link.IsExpired() {
+		return nil, apperrors.ErrTokenExpired
+	}
+
+	used, err := s.linkRepo.MarkUsed(link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	user, err := s.authService.GetUserByID(link.UserID)
+	if err != nil {
+		if err == apperrors.ErrUserNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// O link recebido por e-mail também comprova a posse do endereço
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if user.Status == models.UserStatusInactive {
+			user.Status = models.UserStatusActive
+		}
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info(fmt.Sprintf("Link de login utilizado pelo usuário %d (IP %s)", user.ID, meta.IP))
+	meta.Method = LoginMethodMagicLink
+	return s.authService.LoginVerifiedUser(user, meta)
+}
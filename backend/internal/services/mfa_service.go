@@ -0,0 +1,315 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/totp"
+)
+
+const (
+	// mfaRecoveryCodeCount é a quantidade de códigos de recuperação gerados ao ativar o MFA
+	mfaRecoveryCodeCount = 10
+	// mfaAllowedSkew é a quantidade de janelas TOTP aceitas antes/depois da atual
+	mfaAllowedSkew = 1
+	// devMFAEncryptionKey substitui MFA_ENCRYPTION_KEY fora do modo release (ver configs.Config.Validate)
+	devMFAEncryptionKey = "crm-freela-dev-mfa-encryption-key"
+)
+
+// MFAEnrollment representa os dados para cadastrar o segredo TOTP em um aplicativo autenticador
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAService define a interface do serviço de autenticação em dois fatores (TOTP)
+type MFAService interface {
+	BeginEnrollment(userID uint) (*MFAEnrollment, error)
+	ConfirmEnrollment(userID uint, code string) ([]string, error)
+	Disable(userID uint, password, code string) error
+	Verify(user *models.User, code string) error
+}
+
+// mfaService implementa a interface MFAService
+type mfaService struct {
+	userRepo         repository.UserRepository
+	recoveryCodeRepo repository.MFARecoveryCodeRepository
+	passwordAttempts LoginAttemptTracker
+	encryptionKey    []byte
+	logger           logger.Logger
+	config           *configs.Config
+}
+
+// NewMFAService cria uma nova instância de MFAService
+func NewMFAService(
+	userRepo repository.UserRepository,
+	recoveryCodeRepo repository.MFARecoveryCodeRepository,
+	logger logger.Logger,
+	config *configs.Config,
+) MFAService {
+	// Sem MFA_ENCRYPTION_KEY, usa uma chave fixa de desenvolvimento: os segredos continuam legíveis
+	// após reiniciar, mas não estão protegidos. Em modo release a chave é obrigatória.
+	key := config.MFA.EncryptionKey
+	if key == "" {
+		key = devMFAEncryptionKey
+		logger.Warn("MFA_ENCRYPTION_KEY não definido: usando chave de desenvolvimento para cifrar os segredos TOTP")
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	return &mfaService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		passwordAttempts: NewMemoryAttemptTracker(config.Login.MaxFailedAttempts, config.Login.LockoutBase, config.Login.LockoutMax),
+		encryptionKey:    sum[:],
+		logger:           logger,
+		config:           config,
+	}
+}
+
+// BeginEnrollment gera um novo segredo TOTP para o usuário. O MFA só passa a ser
+// exigido depois que o primeiro código for confirmado em ConfirmEnrollment.
+func (s *mfaService) BeginEnrollment(userID uint) (*MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	if user.MFAEnabled {
+		return nil, apperrors.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFASecret = encrypted
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.config.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment ativa o MFA após validar o primeiro código e retorna os códigos de recuperação.
+// Os códigos são exibidos uma única vez; apenas seus hashes são armazenados.
+func (s *mfaService) ConfirmEnrollment(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	if user.MFAEnabled {
+		return nil, apperrors.ErrMFAAlreadyEnabled
+	}
+
+	if user.MFASecret == "" {
+		return nil, apperrors.ErrMFANotEnrolled
+	}
+
+	if err := s.verifyTOTP(user, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes(mfaRecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.MFAEnabled = true
+	user.MFAEnabledAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Autenticação em dois fatores ativada para o usuário %d", user.ID))
+	return recoveryCodes, nil
+}
+
+// Disable desativa o MFA; exige a senha atual e um código válido (TOTP ou de recuperação).
+// Senhas incorretas consecutivas bloqueiam temporariamente novas tentativas (LockoutError).
+func (s *mfaService) Disable(userID uint, password, code string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return apperrors.ErrUserNotFound
+	}
+
+	if !user.MFAEnabled {
+		return apperrors.ErrMFANotEnabled
+	}
+
+	if err := checkPasswordWithBackoff(s.passwordAttempts, user, password); err != nil {
+		return err
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryCodeRepo.DeleteByUser(user.ID); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	user.MFAEnabledAt = nil
+	user.MFALastUsedStep = 0
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("Autenticação em dois fatores desativada para o usuário %d", user.ID))
+	return nil
+}
+
+// Verify valida o segundo fator de um usuário com MFA ativo.
+// Aceita um código TOTP ou um código de recuperação ainda não utilizado.
+func (s *mfaService) Verify(user *models.User, code string) error {
+	if !user.MFAEnabled {
+		return apperrors.ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(user, code)
+	}
+
+	consumed, err := s.recoveryCodeRepo.Consume(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return apperrors.ErrInvalidMFACode
+	}
+
+	s.logger.Warn(fmt.Sprintf("Código de recuperação de MFA utilizado pelo usuário %d", user.ID))
+	return nil
+}
+
+// verifyTOTP valida um código TOTP e registra a janela utilizada para impedir sua reutilização
+func (s *mfaService) verifyTOTP(user *models.User, code string) error {
+	secret, err := s.decryptSecret(user.MFASecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), mfaAllowedSkew)
+	if !ok {
+		return apperrors.ErrInvalidMFACode
+	}
+
+	// A janela só é aceita se for posterior à última registrada no banco, o que também
+	// impede que duas requisições simultâneas usem o mesmo código
+	claimed, err := s.userRepo.ClaimMFAStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return apperrors.ErrInvalidMFACode
+	}
+
+	user.MFALastUsedStep = step
+	return nil
+}
+
+// encryptSecret cifra o segredo TOTP com AES-256-GCM (nonce + texto cifrado, em base64)
+func (s *mfaService) encryptSecret(secret string) (string, error) {
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret decifra um segredo TOTP gerado por encryptSecret
+func (s *mfaService) decryptSecret(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("segredo TOTP cifrado inválido")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// generateRecoveryCodes gera códigos de recuperação no formato XXXXX-XXXXX e seus hashes
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := encoding.EncodeToString(b)[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode remove separadores e espaços e converte para maiúsculas
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+// OIDCAuthorization representa o início de um login via OpenID Connect.
+// Binding vai em um cookie HttpOnly e precisa voltar no Complete: sem ele, um state obtido por outra
+// pessoa não conclui o login neste navegador (login CSRF).
+type OIDCAuthorization struct {
+	URL       string    `json:"authorization_url"`
+	State     string    `json:"state"`
+	Binding   string    `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+}
+
+// OIDCService define a interface do login via provedor OpenID Connect (authorization code + PKCE)
+type OIDCService interface {
+	// Begin gera state, nonce e code_verifier e retorna a URL de autorização do provedor
+	Begin(ctx context.Context) (*OIDCAuthorization, error)
+	// Complete confere o vínculo com o navegador, troca o código, valida o ID token e conclui o login do usuário vinculado
+	Complete(ctx context.Context, state, binding, code string, meta SessionMeta) (*LoginResult, error)
+}
+
+// oidcFlow guarda os segredos de um login em andamento, indexados pelo state
+type oidcFlow struct {
+	nonce     string
+	verifier  string
+	expiresAt time.Time
+}
+
+// oidcService implementa a interface OIDCService
+type oidcService struct {
+	client       *oidc.Client
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	authService  AuthService
+	logger       logger.Logger
+	config       *configs.Config
+
+	mu    sync.Mutex
+	flows map[string]oidcFlow
+}
+
+// NewOIDCService cria uma nova instância de OIDCService.
+// Com client nil (provedor não configurado), os métodos retornam ErrOIDCDisabled.
+func NewOIDCService(
+	client *oidc.Client,
+	userRepo repository.UserRepository,
+	identityRepo repository.UserIdentityRepository,
+	authService AuthService,
+	logger logger.Logger,
+	config *configs.Config,
+) OIDCService {
+	return &oidcService{
+		client:       client,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		authService:  authService,
+		logger:       logger,
+		config:       config,
+		flows:        make(map[string]oidcFlow),
+	}
+}
+
+// Begin inicia o login e retorna a URL para onde o usuário deve ser redirecionado
+func (s *oidcService) Begin(ctx context.Context) (*OIDCAuthorization, error) {
+	if s.client == nil {
+		return nil, apperrors.ErrOIDCDisabled
+	}
+
+	state, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.client.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, flow := range s.flows {
+		if now.After(flow.expiresAt) {
+			delete(s.flows, key)
+		}
+	}
+	expiresAt := now.Add(s.config.OIDC.StateTTL)
+	s.flows[state] = oidcFlow{
+		nonce:     nonce,
+		verifier:  verifier,
+		expiresAt: expiresAt,
+	}
+
+	return &OIDCAuthorization{
+		URL:       url,
+		State:     state,
+		Binding:   oidcBinding(state, nonce),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Complete conclui o login a partir do código e do state devolvidos pelo provedor e do vínculo
+// guardado no navegador que iniciou o login
+func (s *oidcService) Complete(ctx context.Context, state, binding, code string, meta SessionMeta) (*LoginResult, error) {
+	if s.client == nil {
+		return nil, apperrors.ErrOIDCDisabled
+	}
+
+	flow, ok := s.takeFlow(state)
+	if !ok {
+		return nil, apperrors.ErrInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(binding), []byte(oidcBinding(state, flow.nonce))) != 1 {
+		s.logger.Warn("Retorno OIDC rejeitado: o state não foi iniciado neste navegador")
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	rawIDToken, err := s.client.Exchange(ctx, code, flow.verifier)
+	if err != nil {
+		s.logger.Warn("Falha na troca do código OIDC: " + err.Error())
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	idToken, err := s.client.VerifyIDToken(ctx, rawIDToken, flow.nonce)
+	if err != nil {
+		s.logger.Warn("ID token OIDC rejeitado: " + err.Error())
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	user, err := s.resolveUser(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	meta.Method = LoginMethodOIDC
+	return s.authService.LoginVerifiedUser(user, meta)
+}
+
+// oidcBinding deriva do state e do nonce o valor do cookie que vincula o login ao navegador
+func oidcBinding(state, nonce string) string {
+	sum := sha256.Sum256([]byte(state + "." + nonce))
+	return hex.EncodeToString(sum[:])
+}
+
+// takeFlow remove e retorna o login em andamento do state (uso único)
+func (s *oidcService) takeFlow(state string) (oidcFlow, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flow, ok := s.flows[state]
+	if !ok {
+		return oidcFlow{}, false
+	}
+	delete(s.flows, state)
+
+	if time.Now().After(flow.expiresAt) {
+		return oidcFlow{}, false
+	}
+	return flow, true
+}
+
+// resolveUser encontra o usuário vinculado à identidade ou, na primeira vez, vincula por e-mail
+// verificado a um usuário existente ou cria um novo
+func (s *oidcService) resolveUser(idToken *oidc.IDToken) (*models.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(idToken.Issuer, idToken.Subject)
+	if err == nil {
+		return s.authService.GetUserByID(identity.UserID)
+	}
+	if err != models.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// Sem e-mail verificado pelo provedor não é possível vincular a conta com segurança
+	if idToken.Email == "" || !idToken.EmailVerified {
+		return nil, apperrors.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.GetByEmail(idToken.Email)
+	switch {
+	case err == nil:
+		if err := s.confirmEmail(user); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if user, err = s.createUser(idToken); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: idToken.Issuer,
+		Subject:  idToken.Subject,
+		Email:    idToken.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Identidade OIDC vinculada ao usuário %d (%s)", user.ID, idToken.Issuer))
+	return user, nil
+}
+
+// confirmEmail marca como verificado o e-mail de uma conta local ainda não confirmada.
+// A senha definida no cadastro é descartada, pois não há garantia de que foi criada pelo dono do e-mail.
+func (s *oidcService) confirmEmail(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	password, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.Password = password
+	if user.Status == models.UserStatusInactive {
+		user.Status = models.UserStatusActive
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.authService.RevokeAllSessions(user.ID)
+}
+
+// createUser cria um usuário a partir da identidade do provedor, sem senha utilizável
+func (s *oidcService) createUser(idToken *oidc.IDToken) (*models.User, error) {
+	password, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(idToken.Name)
+	if name == "" {
+		name = strings.Split(idToken.Email, "@")[0]
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Name:            name,
+		Email:           idToken.Email,
+		Password:        password,
+		Status:          models.UserStatusActive,
+		EmailVerifiedAt: &now,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,358 @@
+package services_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+// oidcTestProvider é um provedor de identidade servido por httptest, que emite um ID token
+// para cada código de autorização e só o entrega com o code_verifier correspondente
+type oidcTestProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	codes  map[string]string // code_challenge -> ID token
+}
+
+func newOIDCTestProvider(t *testing.T) *oidcTestProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := jwk.New(&key.PublicKey, "RS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &oidcTestProvider{key: key, kid: public.Kid, codes: make(map[string]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidc.Discovery{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwk.Set{Keys: []jwk.Key{public}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		// O código de teste é o próprio code_challenge
+		challenge := r.FormValue("code")
+		idToken, ok := p.codes[challenge]
+		if !ok || oidc.CodeChallenge(r.FormValue("code_verifier")) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// authorize simula o login do usuário no provedor a partir da URL de autorização e retorna o código.
+// mutate permite alterar os claims do ID token emitido.
+func (p *oidcTestProvider) authorize(t *testing.T, authURL string, mutate func(jwt.MapClaims)) string {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+
+	claims := jwt.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            "user-123",
+		"aud":            query.Get("client_id"),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"nonce":          query.Get("nonce"),
+		"email":          "ana@example.com",
+		"email_verified": true,
+		"name":           "Ana",
+	}
+	if mutate != nil {
+		mutate(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = p.kid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := query.Get("code_challenge")
+	p.codes[challenge] = signed
+	return challenge
+}
+
+// fakeOIDCUserRepo guarda os usuários em memória
+type fakeOIDCUserRepo struct {
+	repository.UserRepository
+	users []*models.User
+}
+
+func (r *fakeOIDCUserRepo) Create(user *models.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *fakeOIDCUserRepo) GetByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOIDCUserRepo) Update(user *models.User) error {
+	return nil
+}
+
+// fakeIdentityRepo guarda as identidades vinculadas em memória
+type fakeIdentityRepo struct {
+	identities []models.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(identity *models.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			return &r.identities[i], nil
+		}
+	}
+	return nil, models.ErrRecordNotFound
+}
+
+// fakeOIDCAuthService conclui o login sem emitir tokens, registrando o usuário autenticado
+type fakeOIDCAuthService struct {
+	services.AuthService
+	loggedIn []*models.User
+}
+
+func (s *fakeOIDCAuthService) LoginVerifiedUser(user *models.User, meta services.SessionMeta) (*services.LoginResult, error) {
+	s.loggedIn = append(s.loggedIn, user)
+	return &services.LoginResult{User: user}, nil
+}
+
+type oidcServiceFixture struct {
+	provider   *oidcTestProvider
+	service    services.OIDCService
+	users      *fakeOIDCUserRepo
+	identities *fakeIdentityRepo
+	auth       *fakeOIDCAuthService
+}
+
+func newOIDCServiceFixture(t *testing.T, stateTTL time.Duration) *oidcServiceFixture {
+	t.Helper()
+
+	f := &oidcServiceFixture{
+		provider:   newOIDCTestProvider(t),
+		users:      &fakeOIDCUserRepo{},
+		identities: &fakeIdentityRepo{},
+		auth:       &fakeOIDCAuthService{},
+	}
+
+	config := &configs.Config{OIDC: configs.OIDCConfig{
+		Issuer:      f.provider.server.URL,
+		ClientID:    "crm-freela",
+		RedirectURL: "http://localhost:3000/auth/oidc/callback",
+		StateTTL:    stateTTL,
+	}}
+	client := oidc.NewClient(oidc.Config{
+		Issuer:      config.OIDC.Issuer,
+		ClientID:    config.OIDC.ClientID,
+		RedirectURL: config.OIDC.RedirectURL,
+	}, f.provider.server.Client())
+
+	f.service = services.NewOIDCService(client, f.users, f.identities, f.auth, logger.NewLogger(), config)
+	return f
+}
+
+func TestOIDCServiceCompleteCreatesAndLinksUser(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	auth, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	code := f.provider.authorize(t, auth.URL, nil)
+
+	result, err := f.service.Complete(ctx, auth.State, auth.Binding, code, services.SessionMeta{})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result.User.Email != "ana@example.com" || result.User.EmailVerifiedAt == nil {
+		t.Fatalf("usuário inesperado: %+v", result.User)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].Subject != "user-123" {
+		t.Fatalf("identidade não vinculada: %+v", f.identities.identities)
+	}
+}
+
+func TestOIDCServiceRejectsUnknownState(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	auth, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := f.provider.authorize(t, auth.URL, nil)
+
+	if _, err := f.service.Complete(ctx, "state-forjado", auth.Binding, code, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+	}
+	if len(f.auth.loggedIn) != 0 {
+		t.Fatal("login concluído com state desconhecido")
+	}
+}
+
+func TestOIDCServiceStateIsSingleUse(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	auth, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := f.provider.authorize(t, auth.URL, nil)
+
+	if _, err := f.service.Complete(ctx, auth.State, auth.Binding, code, services.SessionMeta{}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if _, err := f.service.Complete(ctx, auth.State, auth.Binding, code, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken ao reutilizar o state", err)
+	}
+}
+
+func TestOIDCServiceRejectsExpiredState(t *testing.T) {
+	f := newOIDCServiceFixture(t, -time.Second)
+	ctx := context.Background()
+
+	auth, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := f.provider.authorize(t, auth.URL, nil)
+
+	if _, err := f.service.Complete(ctx, auth.State, auth.Binding, code, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+	}
+}
+
+func TestOIDCServiceRejectsNonceFromAnotherFlow(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	first, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ID token emitido com o nonce do outro login em andamento (replay)
+	otherNonce, _ := url.Parse(second.URL)
+	code := f.provider.authorize(t, first.URL, func(claims jwt.MapClaims) {
+		claims["nonce"] = otherNonce.Query().Get("nonce")
+	})
+
+	if _, err := f.service.Complete(ctx, first.State, first.Binding, code, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+		t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+	}
+}
+
+func TestOIDCServiceRequiresBrowserBinding(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	// Login CSRF: o atacante inicia o próprio login, autentica-se no provedor e leva a vítima ao retorno
+	// com o state e o código dele; o navegador da vítima só tem o vínculo do login que ela iniciou
+	victim, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		binding func(attacker *services.OIDCAuthorization) string
+	}{
+		{"sem cookie", func(*services.OIDCAuthorization) string { return "" }},
+		{"vínculo de outro login", func(*services.OIDCAuthorization) string { return victim.Binding }},
+		{"vínculo adulterado", func(attacker *services.OIDCAuthorization) string { return strings.ToUpper(attacker.Binding) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attacker, err := f.service.Begin(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code := f.provider.authorize(t, attacker.URL, nil)
+
+			if _, err := f.service.Complete(ctx, attacker.State, tt.binding(attacker), code, services.SessionMeta{}); err != apperrors.ErrInvalidToken {
+				t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+			}
+			if len(f.auth.loggedIn) != 0 {
+				t.Fatal("login concluído sem o vínculo do navegador")
+			}
+		})
+	}
+}
+
+func TestOIDCServiceRequiresVerifiedEmailToLink(t *testing.T) {
+	f := newOIDCServiceFixture(t, 10*time.Minute)
+	ctx := context.Background()
+
+	auth, err := f.service.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := f.provider.authorize(t, auth.URL, func(claims jwt.MapClaims) {
+		claims["email_verified"] = false
+	})
+
+	if _, err := f.service.Complete(ctx, auth.State, auth.Binding, code, services.SessionMeta{}); err != apperrors.ErrOIDCEmailNotVerified {
+		t.Fatalf("erro = %v, esperado ErrOIDCEmailNotVerified", err)
+	}
+	if len(f.users.users) != 0 || len(f.identities.identities) != 0 {
+		t.Fatal("conta criada sem e-mail verificado")
+	}
+}
@@ -1,104 +1,166 @@
 package services
 
 import (
-	"crypto/rand"
-	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
 	"github.com/jpcode092/crm-freela/internal/models"
 	"github.com/jpcode092/crm-freela/internal/repository"
 	"github.com/jpcode092/crm-freela/pkg/email"
 	"github.com/jpcode092/crm-freela/pkg/logger"
-)
-
-var (
-	ErrInvalidToken = errors.New("token inválido ou expirado")
-	ErrUserNotFound = errors.New("usuário não encontrado")
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // PasswordResetService define a interface para o serviço de recuperação de senha
 type PasswordResetService interface {
-	RequestReset(email string) error
+	// RequestReset envia o link de redefinição, com limite por e-mail e por IP.
+	// Não informa se o e-mail existe.
+	RequestReset(email, ip string) error
+	// ValidateToken verifica se o token do link ainda pode ser usado
 	ValidateToken(token string) (*models.User, error)
-	ResetPassword(token, newPassword string) error
+	// ResetPassword redefine a senha, invalida o token e encerra todas as sessões do usuário
+	ResetPassword(token, newPassword string, meta SessionMeta) error
 }
 
 type passwordResetService struct {
 	userRepo     repository.UserRepository
+	authService  AuthService
 	emailService email.EmailService
+	audit        AuditWriter
+	byEmail      RateLimiter
+	byIP         RateLimiter
 	logger       logger.Logger
+	config       *configs.Config
 }
 
 // NewPasswordResetService cria uma nova instância de PasswordResetService
-func NewPasswordResetService(userRepo repository.UserRepository, emailService email.EmailService, logger logger.Logger) PasswordResetService {
+func NewPasswordResetService(
+	userRepo repository.UserRepository,
+	authService AuthService,
+	emailService email.EmailService,
+	audit AuditWriter,
+	logger logger.Logger,
+	config *configs.Config,
+) PasswordResetService {
 	return &passwordResetService{
 		userRepo:     userRepo,
+		authService:  authService,
 		emailService: emailService,
-		logger:      logger,
+		audit:        audit,
+		byEmail:      NewMemoryRateLimiter(config.Email.ResendLimit, config.Email.ResendWindow),
+		byIP:         NewMemoryRateLimiter(config.Email.ResendLimit*3, config.Email.ResendWindow),
+		logger:       logger,
+		config:       config,
 	}
 }
 
-// generateToken gera um token aleatório para recuperação de senha
-func (s *passwordResetService) generateToken() (string, error) {
-	b := make([]byte, 32)
-	_, err := rand.Read(b)
-	if err != nil {
-		return "", err
+// RequestReset inicia o processo de recuperação de senha.
+// Somente o hash SHA-256 do token é gravado; o token em si só existe no link enviado.
+func (s *passwordResetService) RequestReset(email, ip string) error {
+	if err := s.byIP.Allow(ip); err != nil {
+		return err
+	}
+	if err := s.byEmail.Allow(strings.ToLower(email)); err != nil {
+		return err
 	}
-	return base64.URLEncoding.EncodeToString(b), nil
-}
 
-// RequestReset inicia o processo de recuperação de senha
-func (s *passwordResetService) RequestReset(email string) error {
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
-		return ErrUserNotFound
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.Status == models.UserStatusBlocked {
+		return nil
 	}
 
-	token, err := s.generateToken()
+	token, err := generateOpaqueToken()
 	if err != nil {
 		return err
 	}
 
-	// Salva o token e a data de expiração no usuário
-	user.ResetToken = &token
-	user.ResetTokenExpires = time.Now().Add(1 * time.Hour)
-	
+	// Uma nova solicitação substitui o token anterior
+	tokenHash := hashToken(token)
+	user.ResetToken = &tokenHash
+	user.ResetTokenExpires = time.Now().Add(s.config.Email.PasswordResetTokenTTL)
+
 	err = s.userRepo.Update(user)
 	if err != nil {
 		return err
 	}
 
-	// Envia o email com o token
-	return s.emailService.SendPasswordReset(user.Email, token)
+	link := strings.TrimRight(s.config.Email.AppURL, "/") + "/auth/reset-password?token=" + token
+	if err := s.emailService.SendPasswordReset(user.Email, user.Name, link); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // ValidateToken verifica se o token é válido e não expirou
 func (s *passwordResetService) ValidateToken(token string) (*models.User, error) {
-	user, err := s.userRepo.GetByResetToken(token)
+	user, err := s.userRepo.GetByResetTokenHash(hashToken(token))
 	if err != nil {
-		return nil, ErrInvalidToken
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
 	}
 
 	if user.ResetTokenExpires.Before(time.Now()) {
-		return nil, ErrInvalidToken
+		return nil, apperrors.ErrTokenExpired
 	}
 
 	return user, nil
 }
 
-// ResetPassword redefine a senha do usuário
-func (s *passwordResetService) ResetPassword(token, newPassword string) error {
+// ResetPassword redefine a senha do usuário.
+// O token é consumido antes da troca, de modo que requisições simultâneas com o mesmo link não passam as duas.
+func (s *passwordResetService) ResetPassword(token, newPassword string, meta SessionMeta) error {
 	user, err := s.ValidateToken(token)
 	if err != nil {
 		return err
 	}
 
-	// Atualiza a senha e limpa o token
-	user.Password = newPassword
+	consumed, err := s.userRepo.ConsumeResetToken(user.ID, *user.ResetToken)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return apperrors.ErrInvalidToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	// O link recebido por e-mail também comprova a posse do endereço
+	now := time.Now()
+	user.Password = string(hashedPassword)
 	user.ResetToken = nil
 	user.ResetTokenExpires = time.Time{}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+		if user.Status == models.UserStatusInactive {
+			user.Status = models.UserStatusActive
+		}
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
 
-	return s.userRepo.Update(user)
+	s.logger.Info(fmt.Sprintf("Senha redefinida para o usuário %d; sessões encerradas", user.ID))
+	s.audit.Record(newAuditEvent(auditContextFor(user.ID, meta), models.AuditPasswordReset, user.ID, nil))
+	return s.authService.RevokeAllSessions(user.ID)
 }
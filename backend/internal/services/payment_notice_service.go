@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/email"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Tipos de aviso de pagamento enviados ao cliente
+const (
+	PaymentNoticeInvoice  = "invoice"  // cobrança de um pagamento pendente
+	PaymentNoticeReminder = "reminder" // lembrete de um pagamento vencido
+)
+
+// Erros do serviço de avisos de pagamento
+var (
+	ErrPaymentNotBillable      = errors.New("só pagamentos pendentes ou vencidos podem ser cobrados")
+	ErrBillingRecipientMissing = errors.New("o cliente não tem contato de cobrança nem e-mail cadastrado")
+)
+
+// headerReplacer impede que quebras de linha no nome do usuário cheguem ao assunto do e-mail
+var headerReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+// PaymentNoticeResult representa o aviso enviado: o tipo e o destinatário
+type PaymentNoticeResult struct {
+	Kind           string `json:"kind"`
+	RecipientName  string `json:"recipient_name"`
+	RecipientEmail string `json:"recipient_email"`
+}
+
+// PaymentNoticeService define a interface do serviço de cobranças e lembretes de pagamento
+type PaymentNoticeService interface {
+	// Send envia ao destinatário de cobrança do cliente (ver ClientContactService.BillingRecipient) a
+	// cobrança do pagamento, se ele estiver pendente e dentro do prazo, ou o lembrete, se estiver vencido
+	Send(userID, paymentID uint) (*PaymentNoticeResult, error)
+}
+
+// paymentNoticeService implementa a interface PaymentNoticeService
+type paymentNoticeService struct {
+	paymentService PaymentService
+	contactService ClientContactService
+	userRepo       repository.UserRepository
+	emailService   email.EmailService
+	logger         logger.Logger
+}
+
+// NewPaymentNoticeService cria uma nova instância de PaymentNoticeService
+func NewPaymentNoticeService(paymentService PaymentService, contactService ClientContactService, userRepo repository.UserRepository, emailService email.EmailService, logger logger.Logger) PaymentNoticeService {
+	return &paymentNoticeService{
+		paymentService: paymentService,
+		contactService: contactService,
+		userRepo:       userRepo,
+		emailService:   emailService,
+		logger:         logger,
+	}
+}
+
+// Send envia a cobrança ou o lembrete do pagamento
+func (s *paymentNoticeService) Send(userID, paymentID uint) (*PaymentNoticeResult, error) {
+	payment, err := s.paymentService.GetByID(paymentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := paymentNoticeKind(payment, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.contactService.BillingRecipient(userID, payment.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.Email == "" {
+		return nil, ErrBillingRecipientMissing
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notice := email.PaymentNotice{
+		SenderName:    headerReplacer.Replace(user.Name),
+		InvoiceNumber: payment.InvoiceNumber,
+		Description:   payment.Description,
+		Amount:        fmt.Sprintf("%s %.2f", payment.Currency, payment.Amount),
+		DueDate:       payment.DueDate,
+	}
+	if kind == PaymentNoticeReminder {
+		err = s.emailService.SendPaymentReminder(recipient.Email, recipient.Name, notice)
+	} else {
+		err = s.emailService.SendPaymentInvoice(recipient.Email, recipient.Name, notice)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao enviar aviso de pagamento: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Aviso de pagamento (%s) do pagamento %d enviado ao cliente %d", kind, payment.ID, payment.ClientID))
+	return &PaymentNoticeResult{Kind: kind, RecipientName: recipient.Name, RecipientEmail: recipient.Email}, nil
+}
+
+// paymentNoticeKind escolhe entre a cobrança e o lembrete; pagamentos pagos ou cancelados não são cobrados
+func paymentNoticeKind(payment *models.Payment, now time.Time) (string, error) {
+	switch payment.Status {
+	case models.PaymentOverdue:
+		return PaymentNoticeReminder, nil
+	case models.PaymentPending:
+		if now.After(payment.DueDate) {
+			return PaymentNoticeReminder, nil
+		}
+		return PaymentNoticeInvoice, nil
+	default:
+		return "", ErrPaymentNotBillable
+	}
+}
@@ -20,7 +20,7 @@ var (
 
 // PlanService define a interface para o serviço de planos
 type PlanService interface {
-	CanCreateClient(userID uint) error
+	CanCreateClient(userID uint, count int) error
 	CanCreateTask(userID uint) error
 }
 
@@ -39,16 +39,16 @@ func NewPlanService(clientRepo repository.ClientRepository, taskRepo repository.
 	}
 }
 
-// CanCreateClient verifica se o usuário pode criar mais clientes
-func (s *planService) CanCreateClient(userID uint) error {
+// CanCreateClient verifica se o usuário pode criar mais count clientes
+func (s *planService) CanCreateClient(userID uint, count int) error {
 	// TODO: Implementar verificação de plano premium
 	// Por enquanto, assume que todos os usuários estão no plano gratuito
-	count, err := s.clientRepo.CountByUser(userID)
+	existing, err := s.clientRepo.CountByUser(userID)
 	if err != nil {
 		return err
 	}
 
-	if count >= FreePlanClientLimit {
+	if existing+int64(count) > FreePlanClientLimit {
 		return ErrClientLimitExceeded
 	}
 
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/email"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PrivacyService define a interface dos direitos do titular previstos na LGPD: acesso aos dados
+// (exportação) e eliminação da conta
+type PrivacyService interface {
+	// RequestExport gera o ZIP com os dados do usuário e envia o link de download por e-mail
+	RequestExport(userID uint, meta SessionMeta) error
+	// DownloadExport retorna a exportação do link, enquanto ele não expirar
+	DownloadExport(token string, meta SessionMeta) (*models.DataExport, error)
+	// ScheduleDeletion confirma a senha (e o código MFA, se ativo) e agenda a exclusão da conta
+	ScheduleDeletion(userID uint, password, mfaCode string) (time.Time, error)
+	// CancelDeletion cancela a exclusão agendada, enquanto o prazo de carência não terminar
+	CancelDeletion(userID uint) error
+	// PurgeDueAccounts remove os dados das contas cujo prazo de carência terminou e as exportações expiradas
+	PurgeDueAccounts() (int, error)
+}
+
+// privacyService implementa a interface PrivacyService
+type privacyService struct {
+	userRepo         models.UserRepository
+	privacyRepo      repository.PrivacyRepository
+	authService      AuthService
+	mfaService       MFAService
+	mailer           email.EmailService
+	audit            AuditWriter
+	exports          RateLimiter
+	passwordAttempts LoginAttemptTracker
+	logger           logger.Logger
+	config           *configs.Config
+}
+
+// NewPrivacyService cria uma nova instância de PrivacyService
+func NewPrivacyService(
+	userRepo models.UserRepository,
+	privacyRepo repository.PrivacyRepository,
+	authService AuthService,
+	mfaService MFAService,
+	mailer email.EmailService,
+	audit AuditWriter,
+	logger logger.Logger,
+	config *configs.Config,
+) PrivacyService {
+	return &privacyService{
+		userRepo:         userRepo,
+		privacyRepo:      privacyRepo,
+		authService:      authService,
+		mfaService:       mfaService,
+		mailer:           mailer,
+		audit:            audit,
+		exports:          NewMemoryRateLimiter(config.Privacy.ExportLimit, config.Privacy.ExportWindow),
+		passwordAttempts: NewMemoryAttemptTracker(config.Login.MaxFailedAttempts, config.Login.LockoutBase, config.Login.LockoutMax),
+		logger:           logger,
+		config:           config,
+	}
+}
+
+// RequestExport gera a exportação e envia o link ao e-mail da conta.
+// Somente o hash SHA-256 do token é gravado; o token em si só existe no link enviado.
+func (s *privacyService) RequestExport(userID uint, meta SessionMeta) error {
+	if err := s.exports.Allow(strconv.FormatUint(uint64(userID), 10)); err != nil {
+		return err
+	}
+
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.privacyRepo.LoadUserData(user.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	archive, err := buildDataArchive(user, data, now)
+	if err != nil {
+		return fmt.Errorf("erro ao gerar arquivo de exportação: %w", err)
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.privacyRepo.CreateExport(&models.DataExport{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		Archive:   archive,
+		SizeBytes: len(archive),
+		ExpiresAt: now.Add(s.config.Privacy.ExportTTL),
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("Exportação de dados gerada para o usuário %d (%d bytes)", user.ID, len(archive)))
+	s.audit.Record(newAuditEvent(auditContextFor(user.ID, meta), models.AuditDataExportRequested, user.ID, map[string]interface{}{
+		"size_bytes": len(archive),
+	}))
+
+	link := strings.TrimRight(s.config.Email.AppURL, "/") + "/data-export?token=" + token
+	return s.mailer.SendDataExportReady(user.Email, user.Name, link)
+}
+
+// DownloadExport retorna a exportação do link. O link pode ser usado mais de uma vez até expirar.
+// Cada download é registrado na trilha de auditoria sem autor, pois quem usa o link não está autenticado.
+func (s *privacyService) DownloadExport(token string, meta SessionMeta) (*models.DataExport, error) {
+	export, err := s.privacyRepo.GetExportByTokenHash(hashToken(token))
+	if err != nil {
+		if err == models.ErrRecordNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if export.IsExpired() {
+		return nil, apperrors.ErrTokenExpired
+	}
+
+	if err := s.privacyRepo.MarkExportDownloaded(export.ID); err != nil {
+		s.logger.Error(err.Error())
+	}
+
+	s.audit.Record(newAuditEvent(auditContextFor(0, meta), models.AuditDataExportDownloaded, export.UserID, map[string]interface{}{
+		"export_id": export.ID,
+	}))
+
+	return export, nil
+}
+
+// ScheduleDeletion agenda a exclusão para depois do prazo de carência. Durante o prazo a conta
+// continua acessível, para que o pedido possa ser cancelado.
+func (s *privacyService) ScheduleDeletion(userID uint, password, mfaCode string) (time.Time, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := checkPasswordWithBackoff(s.passwordAttempts, user, password); err != nil {
+		return time.Time{}, err
+	}
+
+	if user.MFAEnabled {
+		if strings.TrimSpace(mfaCode) == "" {
+			return time.Time{}, apperrors.ErrInvalidMFACode
+		}
+		if err := s.mfaService.Verify(user, mfaCode); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// Um novo pedido não adia a exclusão já agendada
+	if user.DeletionScheduledAt != nil {
+		return *user.DeletionScheduledAt, nil
+	}
+
+	scheduledAt := time.Now().Add(s.config.Privacy.DeletionGracePeriod)
+	user.DeletionScheduledAt = &scheduledAt
+	if err := s.userRepo.Update(user); err != nil {
+		return time.Time{}, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Exclusão da conta do usuário %d agendada para %s", user.ID, scheduledAt.Format(time.RFC3339)))
+
+	// O aviso por e-mail não impede o agendamento
+	if err := s.mailer.SendAccountDeletionScheduled(user.Email, user.Name, scheduledAt); err != nil {
+		s.logger.Error(fmt.Sprintf("Erro ao avisar %s sobre a exclusão da conta: %v", user.Email, err))
+	}
+
+	return scheduledAt, nil
+}
+
+// CancelDeletion cancela a exclusão agendada da conta
+func (s *privacyService) CancelDeletion(userID uint) error {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.DeletionScheduledAt == nil {
+		return apperrors.ErrDeletionNotScheduled
+	}
+
+	user.DeletionScheduledAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("Exclusão da conta do usuário %d cancelada", user.ID))
+	return nil
+}
+
+// PurgeDueAccounts remove definitivamente clientes, tarefas e pagamentos das contas vencidas e
+// anonimiza o usuário. As sessões são revogadas antes, para que os access tokens emitidos deixem de valer.
+// Retorna o número de contas removidas.
+func (s *privacyService) PurgeDueAccounts() (int, error) {
+	now := time.Now()
+
+	if removed, err := s.privacyRepo.DeleteExpiredExports(now); err != nil {
+		s.logger.Error(err.Error())
+	} else if removed > 0 {
+		s.logger.Info(fmt.Sprintf("%d exportações de dados expiradas removidas", removed))
+	}
+
+	users, err := s.privacyRepo.ListDueDeletions(now)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range users {
+		user := &users[i]
+
+		if err := s.authService.RevokeAllSessions(user.ID); err != nil {
+			return purged, err
+		}
+
+		anonymized, err := anonymizedUser(user.ID)
+		if err != nil {
+			return purged, err
+		}
+
+		if err := s.privacyRepo.EraseUser(user.ID, anonymized); err != nil {
+			return purged, err
+		}
+
+		purged++
+		s.logger.Info(fmt.Sprintf("Dados da conta do usuário %d removidos conforme pedido de exclusão", user.ID))
+	}
+
+	return purged, nil
+}
+
+// anonymizedUser gera os dados que substituem os do titular na linha do usuário.
+// A senha é um hash de um valor aleatório descartado, impedindo qualquer login.
+func anonymizedUser(userID uint) (*models.User, error) {
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		Name:     "Conta excluída",
+		Email:    fmt.Sprintf("excluido-%d@anonimo.invalid", userID),
+		Password: string(hashedPassword),
+		Status:   models.UserStatusBlocked,
+	}, nil
+}
+
+// StartAccountPurge executa PurgeDueAccounts periodicamente em segundo plano
+func StartAccountPurge(service PrivacyService, interval time.Duration, logger logger.Logger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := service.PurgeDueAccounts(); err != nil {
+				logger.Error("Erro ao remover contas com exclusão agendada: " + err.Error())
+			}
+		}
+	}()
+}
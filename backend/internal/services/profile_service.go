@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/pkg/email"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PurposeEmailChange identifica o token enviado ao novo endereço na troca de e-mail.
+// O claim Email guarda o novo endereço, que precisa coincidir com o PendingEmail do usuário.
+const PurposeEmailChange = "email_change"
+
+// ProfileUpdate representa a edição do perfil; campos nil permanecem inalterados
+type ProfileUpdate struct {
+	Name                  *string
+	Language              *string
+	Timezone              *string
+	EmailNotifications    *bool
+	PasswordLoginDisabled *bool
+}
+
+// ProfileService define a interface do serviço de edição do perfil do usuário autenticado
+type ProfileService interface {
+	// UpdateProfile altera o nome, as preferências e a permissão de login com senha do usuário
+	UpdateProfile(userID uint, update ProfileUpdate) (*models.User, error)
+	// ChangePassword troca a senha após validar a atual e encerra as demais sessões do usuário
+	ChangePassword(userID uint, currentPassword, newPassword, currentSessionID string) error
+	// RequestEmailChange envia o link de confirmação para o novo endereço, sem alterar o e-mail da conta
+	RequestEmailChange(userID uint, password, newEmail string) error
+	// ConfirmEmailChange substitui o e-mail da conta pelo endereço confirmado no link
+	ConfirmEmailChange(token string) (*models.User, error)
+}
+
+// profileService implementa a interface ProfileService
+type profileService struct {
+	userRepo         models.UserRepository
+	authService      AuthService
+	signer           TokenSigner
+	mailer           email.EmailService
+	passwordAttempts LoginAttemptTracker
+	emailChanges     RateLimiter
+	logger           logger.Logger
+	config           *configs.Config
+}
+
+// NewProfileService cria uma nova instância de ProfileService
+func NewProfileService(
+	userRepo models.UserRepository,
+	authService AuthService,
+	signer TokenSigner,
+	mailer email.EmailService,
+	logger logger.Logger,
+	config *configs.Config,
+) ProfileService {
+	return &profileService{
+		userRepo:         userRepo,
+		authService:      authService,
+		signer:           signer,
+		mailer:           mailer,
+		passwordAttempts: NewMemoryAttemptTracker(config.Login.MaxFailedAttempts, config.Login.LockoutBase, config.Login.LockoutMax),
+		emailChanges:     NewMemoryRateLimiter(config.Email.ResendLimit, config.Email.ResendWindow),
+		logger:           logger,
+		config:           config,
+	}
+}
+
+// UpdateProfile altera o nome, as preferências e a permissão de login com senha do usuário
+func (s *profileService) UpdateProfile(userID uint, update ProfileUpdate) (*models.User, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		user.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Language != nil {
+		user.Preferences.Language = *update.Language
+	}
+	if update.Timezone != nil {
+		user.Preferences.Timezone = *update.Timezone
+	}
+	if update.EmailNotifications != nil {
+		user.Preferences.EmailNotifications = *update.EmailNotifications
+	}
+	if update.PasswordLoginDisabled != nil {
+		user.PasswordLoginDisabled = *update.PasswordLoginDisabled
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ChangePassword troca a senha do usuário. As demais sessões e os access tokens emitidos
+// por elas são revogados; a sessão atual (currentSessionID) continua válida.
+func (s *profileService) ChangePassword(userID uint, currentPassword, newPassword, currentSessionID string) error {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPasswordWithBackoff(s.passwordAttempts, user, currentPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.Password = string(hashedPassword)
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.authService.RevokeOtherSessions(user.ID, currentSessionID)
+}
+
+// RequestEmailChange registra o novo e-mail como pendente e envia o link de confirmação para ele.
+// O endereço atual é avisado da solicitação.
+func (s *profileService) RequestEmailChange(userID uint, password, newEmail string) error {
+	if err := s.emailChanges.Allow(strconv.FormatUint(uint64(userID), 10)); err != nil {
+		return err
+	}
+
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPasswordWithBackoff(s.passwordAttempts, user, password); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return apperrors.ErrEmailUnchanged
+	}
+
+	if err := s.ensureEmailAvailable(newEmail); err != nil {
+		return err
+	}
+
+	user.PendingEmail = &newEmail
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	token, err := s.generateEmailChangeToken(user, newEmail)
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(s.config.Email.AppURL, "/") + "/auth/confirm-email-change?token=" + token
+	if err := s.mailer.SendEmailChangeConfirmation(newEmail, user.Name, link); err != nil {
+		return err
+	}
+
+	// O aviso ao endereço atual não impede a troca
+	if err := s.mailer.SendEmailChangeRequested(user.Email, user.Name, newEmail); err != nil {
+		s.logger.Error(fmt.Sprintf("Erro ao avisar %s sobre a troca de e-mail: %v", user.Email, err))
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange valida o link enviado ao novo endereço e substitui o e-mail da conta.
+// Links de solicitações anteriores deixam de valer quando uma nova troca é solicitada.
+func (s *profileService) ConfirmEmailChange(token string) (*models.User, error) {
+	claims, err := parseClaims(s.signer, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != PurposeEmailChange {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	user, err := s.authService.GetUserByID(claims.UserID)
+	if err != nil {
+		if err == apperrors.ErrUserNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Links repetidos apenas confirmam que a troca já foi feita
+	if user.PendingEmail == nil && strings.EqualFold(user.Email, claims.Email) {
+		return user, nil
+	}
+
+	if user.PendingEmail == nil || !strings.EqualFold(*user.PendingEmail, claims.Email) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	previousEmail := user.Email
+	now := time.Now()
+	user.Email = *user.PendingEmail
+	user.PendingEmail = nil
+	user.EmailVerifiedAt = &now
+
+	if err := s.userRepo.Update(user); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, apperrors.ErrEmailInUse
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("E-mail do usuário %d alterado de %s para %s", user.ID, previousEmail, user.Email))
+	return user, nil
+}
+
+// ensureEmailAvailable verifica se o e-mail não pertence a outra conta
+func (s *profileService) ensureEmailAvailable(email string) error {
+	_, err := s.userRepo.GetByEmail(email)
+	if err == nil {
+		return apperrors.ErrEmailInUse
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, models.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+// generateEmailChangeToken gera o token do link de confirmação do novo e-mail
+func (s *profileService) generateEmailChangeToken(user *models.User, newEmail string) (string, error) {
+	tokenID, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	return s.signer.Sign(&Claims{
+		UserID:  user.ID,
+		Purpose: PurposeEmailChange,
+		Email:   newEmail,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.Email.VerificationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	})
+}
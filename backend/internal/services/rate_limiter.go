@@ -0,0 +1,66 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limita a quantidade de operações por chave (e-mail, IP) dentro de uma janela de tempo
+type RateLimiter interface {
+	// Allow registra uma operação para a chave. Retorna *LockoutError quando o limite foi atingido.
+	Allow(key string) error
+}
+
+// memoryRateLimiter implementa RateLimiter em memória com janela deslizante
+type memoryRateLimiter struct {
+	mu     sync.Mutex
+	hits   map[string][]time.Time
+	limit  int
+	window time.Duration
+}
+
+// NewMemoryRateLimiter cria um RateLimiter em memória que aceita `limit` operações por chave a cada `window`.
+// Um limite menor ou igual a zero desativa a limitação.
+func NewMemoryRateLimiter(limit int, window time.Duration) RateLimiter {
+	return &memoryRateLimiter{
+		hits:   make(map[string][]time.Time),
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow registra uma operação para a chave se o limite ainda não foi atingido
+func (l *memoryRateLimiter) Allow(key string) error {
+	if l.limit <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	hits := l.hits[key]
+	if len(hits) >= l.limit {
+		return &LockoutError{RetryAfter: remaining(hits[0].Add(l.window))}
+	}
+
+	l.hits[key] = append(hits, now)
+	return nil
+}
+
+// prune descarta as operações que já saíram da janela
+func (l *memoryRateLimiter) prune(now time.Time) {
+	for key, hits := range l.hits {
+		i := 0
+		for i < len(hits) && now.Sub(hits[i]) >= l.window {
+			i++
+		}
+		if i == len(hits) {
+			delete(l.hits, key)
+		} else if i > 0 {
+			l.hits[key] = hits[i:]
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenRevocationStore guarda os access tokens revogados antes de expirar, indexados pelo claim jti.
+// A implementação de produção é repository.NewRevokedTokenRepository (Postgres).
+type TokenRevocationStore interface {
+	Revoke(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+}
+
+// memoryRevocationStore implementa TokenRevocationStore em memória (testes e desenvolvimento)
+type memoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationStore cria um TokenRevocationStore em memória
+func NewMemoryRevocationStore() TokenRevocationStore {
+	return &memoryRevocationStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke registra o jti como revogado até a expiração do token
+func (s *memoryRevocationStore) Revoke(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Remove as entradas cujos tokens já expiraram naturalmente
+	now := time.Now()
+	for id, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, id)
+		}
+	}
+
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+// IsRevoked verifica se o jti foi revogado
+func (s *memoryRevocationStore) IsRevoked(jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exp, ok := s.revoked[jti]
+	return ok && time.Now().Before(exp), nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// Erros comuns do serviço de tags
+var (
+	ErrTagNotFound  = errors.New("tag não encontrada")
+	ErrTagNameInUse = errors.New("já existe uma tag com este nome")
+)
+
+// TagService define a interface do serviço de tags dos clientes
+type TagService interface {
+	List(userID uint) ([]models.Tag, error)
+	Create(userID uint, name, color string) (*models.Tag, error)
+	Update(userID, id uint, name, color string) (*models.Tag, error)
+	Delete(userID, id uint) error
+	// Resolve retorna as tags do usuário com os IDs informados, ou ErrTagNotFound se alguma não existir
+	Resolve(userID uint, ids []uint) ([]models.Tag, error)
+}
+
+// tagService implementa a interface TagService
+type tagService struct {
+	tagRepo repository.TagRepository
+	logger  logger.Logger
+}
+
+// NewTagService cria uma nova instância de TagService
+func NewTagService(tagRepo repository.TagRepository, logger logger.Logger) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+		logger:  logger,
+	}
+}
+
+// List retorna as tags do usuário
+func (s *tagService) List(userID uint) ([]models.Tag, error) {
+	return s.tagRepo.ListByUser(userID)
+}
+
+// Create cria uma tag; o nome é único por usuário
+func (s *tagService) Create(userID uint, name, color string) (*models.Tag, error) {
+	tag := &models.Tag{
+		UserID: userID,
+		Name:   strings.TrimSpace(name),
+		Color:  strings.ToLower(color),
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, ErrTagNameInUse
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Update renomeia ou muda a cor de uma tag
+func (s *tagService) Update(userID, id uint, name, color string) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetByID(userID, id)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	tag.Name = strings.TrimSpace(name)
+	tag.Color = strings.ToLower(color)
+	if err := s.tagRepo.Update(tag); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, ErrTagNameInUse
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Delete remove a tag e a retira dos clientes
+func (s *tagService) Delete(userID, id uint) error {
+	deleted, err := s.tagRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTagNotFound
+	}
+
+	s.logger.Info(fmt.Sprintf("Tag %d removida pelo usuário %d", id, userID))
+	return nil
+}
+
+// Resolve valida os IDs das tags informadas para um cliente
+func (s *tagService) Resolve(userID uint, ids []uint) ([]models.Tag, error) {
+	ids = uniqueIDs(ids)
+	tags, err := s.tagRepo.GetByIDs(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, ErrTagNotFound
+	}
+	return tags, nil
+}
+
+// uniqueIDs remove os IDs repetidos, mantendo a ordem
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
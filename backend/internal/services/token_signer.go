@@ -0,0 +1,160 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jpcode092/crm-freela/configs"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+)
+
+// TokenSigner assina os tokens emitidos pela aplicação com uma chave assimétrica (RS256 ou EdDSA)
+// e os valida contra o conjunto de chaves ativas, permitindo a rotação sem invalidar tokens em circulação.
+type TokenSigner interface {
+	// Sign assina os claims com a chave atual, identificada pelo cabeçalho kid
+	Sign(claims jwt.Claims) (string, error)
+	// Parse valida a assinatura com a chave indicada pelo kid e decodifica os claims
+	Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error)
+	// JWKS retorna as chaves públicas ativas, publicadas em /.well-known/jwks.json
+	JWKS() jwk.Set
+}
+
+// signingKey representa uma chave do conjunto, com o método de assinatura correspondente
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	published jwk.Key
+}
+
+// tokenSigner implementa a interface TokenSigner
+type tokenSigner struct {
+	current *signingKey
+	keys    map[string]*signingKey
+}
+
+// NewTokenSigner carrega a chave de assinatura e as chaves de verificação definidas na configuração.
+// Sem JWT_SIGNING_KEY_FILE, gera uma chave temporária do algoritmo configurado: os tokens deixam de
+// valer ao reiniciar, o que só é aceito fora do modo release (ver configs.Config.Validate).
+func NewTokenSigner(config *configs.Config, logger logger.Logger) (TokenSigner, error) {
+	var private crypto.Signer
+	var err error
+	if config.JWT.SigningKeyFile == "" {
+		if private, err = generateSigningKey(config.JWT.SigningAlgorithm); err != nil {
+			return nil, err
+		}
+		logger.Warn("JWT_SIGNING_KEY_FILE não definido: usando chave de assinatura temporária")
+	} else if private, err = jwk.LoadPrivateKey(config.JWT.SigningKeyFile); err != nil {
+		return nil, err
+	}
+
+	current, err := newSigningKey(private)
+	if err != nil {
+		return nil, err
+	}
+	if current.method.Alg() != config.JWT.SigningAlgorithm {
+		return nil, fmt.Errorf("a chave de assinatura é %s, mas JWT_SIGNING_ALG é %q", current.method.Alg(), config.JWT.SigningAlgorithm)
+	}
+
+	signer := &tokenSigner{
+		current: current,
+		keys:    map[string]*signingKey{current.id: current},
+	}
+
+	for _, path := range config.JWT.VerificationKeyFiles {
+		public, err := jwk.LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := newVerificationKey(public)
+		if err != nil {
+			return nil, err
+		}
+		signer.keys[key.id] = key
+	}
+
+	return signer, nil
+}
+
+// Sign assina os claims com a chave atual
+func (s *tokenSigner) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.current.method, claims)
+	token.Header["kid"] = s.current.id
+	return token.SignedString(s.current.private)
+}
+
+// Parse valida o token com a chave indicada pelo kid, exigindo o algoritmo dessa chave
+func (s *tokenSigner) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("chave %q desconhecida", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("algoritmo %s não corresponde à chave %q", token.Method.Alg(), kid)
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+}
+
+// JWKS retorna as chaves públicas ativas, com a chave atual primeiro
+func (s *tokenSigner) JWKS() jwk.Set {
+	set := jwk.Set{Keys: []jwk.Key{s.current.published}}
+	for id, key := range s.keys {
+		if id != s.current.id {
+			set.Keys = append(set.Keys, key.published)
+		}
+	}
+	return set
+}
+
+// generateSigningKey gera uma chave privada temporária para o algoritmo informado
+func generateSigningKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case jwk.AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	case jwk.AlgRS256:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return nil, fmt.Errorf("algoritmo de assinatura %q não suportado (use EdDSA ou RS256)", alg)
+	}
+}
+
+// newSigningKey monta a chave de assinatura a partir da chave privada
+func newSigningKey(private crypto.Signer) (*signingKey, error) {
+	key, err := newVerificationKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.private = private
+	return key, nil
+}
+
+// newVerificationKey monta uma chave usada apenas para validar tokens
+func newVerificationKey(public crypto.PublicKey) (*signingKey, error) {
+	alg, err := jwk.Algorithm(public)
+	if err != nil {
+		return nil, err
+	}
+	method := jwt.GetSigningMethod(alg)
+
+	published, err := jwk.New(public, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{
+		id:        published.Kid,
+		method:    method,
+		public:    public,
+		published: published,
+	}, nil
+}
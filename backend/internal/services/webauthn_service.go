@@ -0,0 +1,291 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/repository"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/webauthn"
+)
+
+// WebAuthnService define a interface do cadastro de passkeys e do login com elas (WebAuthn)
+type WebAuthnService interface {
+	// BeginRegistration gera as opções de navigator.credentials.create() para o usuário autenticado
+	BeginRegistration(userID uint) (*webauthn.CreationOptions, error)
+	// FinishRegistration valida a resposta do autenticador e grava a nova passkey com o nome informado
+	FinishRegistration(userID uint, name string, response *webauthn.AttestationResponse) (*models.WebAuthnCredential, error)
+	// ListCredentials retorna as passkeys do usuário
+	ListCredentials(userID uint) ([]models.WebAuthnCredential, error)
+	// DeleteCredential remove uma passkey do usuário
+	DeleteCredential(userID, id uint) error
+	// BeginLogin gera as opções de navigator.credentials.get(); o usuário é identificado pela passkey escolhida
+	BeginLogin(ip string) (*webauthn.RequestOptions, error)
+	// FinishLogin valida a assinatura da passkey e conclui o login com as mesmas regras do login com senha
+	FinishLogin(response *webauthn.AssertionResponse, meta SessionMeta) (*LoginResult, error)
+}
+
+// webAuthnCeremony guarda um registro ou login em andamento, indexado pelo challenge
+type webAuthnCeremony struct {
+	userID    uint // usuário que iniciou o registro; zero no login
+	login     bool
+	expiresAt time.Time
+}
+
+// webAuthnService implementa a interface WebAuthnService
+type webAuthnService struct {
+	rp             *webauthn.RelyingParty
+	credentialRepo repository.WebAuthnCredentialRepository
+	authService    AuthService
+	loginBegins    RateLimiter
+	logger         logger.Logger
+	config         *configs.Config
+
+	mu         sync.Mutex
+	ceremonies map[string]webAuthnCeremony
+}
+
+// NewWebAuthnService cria uma nova instância de WebAuthnService.
+// Retorna erro se o relying party (RP ID e origens) não estiver configurado.
+func NewWebAuthnService(
+	credentialRepo repository.WebAuthnCredentialRepository,
+	authService AuthService,
+	logger logger.Logger,
+	config *configs.Config,
+) (WebAuthnService, error) {
+	rp, err := webauthn.New(webauthn.Config{
+		RPID:                    config.WebAuthn.RPID,
+		RPName:                  config.WebAuthn.RPName,
+		Origins:                 config.WebAuthn.Origins,
+		Timeout:                 config.WebAuthn.Timeout,
+		RequireUserVerification: config.WebAuthn.RequireUserVerification,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnService{
+		rp:             rp,
+		credentialRepo: credentialRepo,
+		authService:    authService,
+		loginBegins:    NewMemoryRateLimiter(config.Login.IPMaxFailedAttempts, time.Minute),
+		logger:         logger,
+		config:         config,
+		ceremonies:     make(map[string]webAuthnCeremony),
+	}, nil
+}
+
+// BeginRegistration gera as opções de criação da passkey.
+// As passkeys já cadastradas são excluídas, para o autenticador não criar outra para a mesma conta.
+func (s *webAuthnService) BeginRegistration(userID uint) (*webauthn.CreationOptions, error) {
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.credentialRepo.ListByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(credentials))
+	for i := range credentials {
+		exclude = append(exclude, webauthn.NewCredentialDescriptor(credentials[i].CredentialID, credentials[i].TransportList()))
+	}
+
+	options, err := s.rp.BeginRegistration(webauthn.UserEntity{
+		ID:          userHandle(user.ID),
+		Name:        user.Email,
+		DisplayName: user.Name,
+	}, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	s.storeCeremony(options.Challenge, webAuthnCeremony{userID: user.ID})
+	return options, nil
+}
+
+// FinishRegistration valida a resposta do autenticador contra o registro iniciado pelo mesmo usuário
+func (s *webAuthnService) FinishRegistration(userID uint, name string, response *webauthn.AttestationResponse) (*models.WebAuthnCredential, error) {
+	challenge, err := response.Challenge()
+	if err != nil {
+		return nil, apperrors.ErrInvalidPasskey
+	}
+
+	ceremony, ok := s.takeCeremony(challenge)
+	if !ok || ceremony.login || ceremony.userID != userID {
+		return nil, apperrors.ErrInvalidPasskey
+	}
+
+	verified, err := s.rp.FinishRegistration(challenge, response)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Registro de passkey rejeitado para o usuário %d: %v", userID, err))
+		return nil, apperrors.ErrInvalidPasskey
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	credential := &models.WebAuthnCredential{
+		UserID:         userID,
+		Name:           name,
+		CredentialID:   verified.ID,
+		PublicKey:      verified.PublicKey,
+		Algorithm:      verified.Algorithm,
+		SignCount:      verified.SignCount,
+		Transports:     strings.Join(verified.Transports, " "),
+		AAGUID:         verified.AAGUID,
+		BackupEligible: verified.BackupEligible,
+	}
+	if err := s.credentialRepo.Create(credential); err != nil {
+		if errors.Is(err, models.ErrDuplicateKey) {
+			return nil, apperrors.ErrInvalidPasskey
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Passkey %d cadastrada pelo usuário %d", credential.ID, userID))
+	return credential, nil
+}
+
+// ListCredentials retorna as passkeys do usuário
+func (s *webAuthnService) ListCredentials(userID uint) ([]models.WebAuthnCredential, error) {
+	return s.credentialRepo.ListByUser(userID)
+}
+
+// DeleteCredential remove uma passkey do usuário
+func (s *webAuthnService) DeleteCredential(userID, id uint) error {
+	deleted, err := s.credentialRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.ErrPasskeyNotFound
+	}
+
+	s.logger.Info(fmt.Sprintf("Passkey %d removida pelo usuário %d", id, userID))
+	return nil
+}
+
+// BeginLogin gera as opções de autenticação sem lista de credenciais: o navegador oferece
+// as passkeys do site e o usuário é identificado pela escolhida, sem revelar quais e-mails existem
+func (s *webAuthnService) BeginLogin(ip string) (*webauthn.RequestOptions, error) {
+	if err := s.loginBegins.Allow(ip); err != nil {
+		return nil, err
+	}
+
+	options, err := s.rp.BeginLogin(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.storeCeremony(options.Challenge, webAuthnCeremony{login: true})
+	return options, nil
+}
+
+// FinishLogin valida a assinatura e o contador da passkey e conclui o login.
+// O contador é gravado antes da emissão dos tokens, de modo que a mesma resposta não é aceita duas vezes.
+func (s *webAuthnService) FinishLogin(response *webauthn.AssertionResponse, meta SessionMeta) (*LoginResult, error) {
+	challenge, err := response.Challenge()
+	if err != nil {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	ceremony, ok := s.takeCeremony(challenge)
+	if !ok || !ceremony.login {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	credential, err := s.credentialRepo.GetByCredentialID(response.RawID)
+	if err != nil {
+		if errors.Is(err, models.ErrRecordNotFound) {
+			return nil, apperrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// O user handle devolvido pela passkey precisa ser o do dono da credencial
+	if len(response.Response.UserHandle) > 0 && string(response.Response.UserHandle) != string(userHandle(credential.UserID)) {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	assertion, err := s.rp.FinishLogin(challenge, response, credential.PublicKey, credential.SignCount)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCount) {
+			s.logger.Warn(fmt.Sprintf("Contador da passkey %d do usuário %d regrediu; possível clonagem (IP %s)", credential.ID, credential.UserID, meta.IP))
+		}
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	updated, err := s.credentialRepo.UpdateSignCount(credential.ID, credential.SignCount, assertion.SignCount, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+
+	user, err := s.authService.GetUserByID(credential.UserID)
+	if err != nil {
+		if err == apperrors.ErrUserNotFound {
+			return nil, apperrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Login com passkey %d do usuário %d (IP %s)", credential.ID, user.ID, meta.IP))
+	meta.Method = LoginMethodPasskey
+	return s.authService.LoginVerifiedUser(user, meta)
+}
+
+// storeCeremony registra a cerimônia em andamento e descarta as expiradas
+func (s *webAuthnService) storeCeremony(challenge []byte, ceremony webAuthnCeremony) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, pending := range s.ceremonies {
+		if now.After(pending.expiresAt) {
+			delete(s.ceremonies, key)
+		}
+	}
+
+	ceremony.expiresAt = now.Add(s.config.WebAuthn.Timeout)
+	s.ceremonies[base64.RawURLEncoding.EncodeToString(challenge)] = ceremony
+}
+
+// takeCeremony remove e retorna a cerimônia do challenge (uso único)
+func (s *webAuthnService) takeCeremony(challenge []byte) (webAuthnCeremony, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := base64.RawURLEncoding.EncodeToString(challenge)
+	ceremony, ok := s.ceremonies[key]
+	if !ok {
+		return webAuthnCeremony{}, false
+	}
+	delete(s.ceremonies, key)
+
+	if time.Now().After(ceremony.expiresAt) {
+		return webAuthnCeremony{}, false
+	}
+	return ceremony, true
+}
+
+// userHandle gera o identificador do usuário guardado na passkey: o ID em 8 bytes, sem dados pessoais
+func userHandle(userID uint) []byte {
+	handle := make([]byte, 8)
+	binary.BigEndian.PutUint64(handle, uint64(userID))
+	return handle
+}
@@ -0,0 +1,292 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpcode092/crm-freela/configs"
+	apperrors "github.com/jpcode092/crm-freela/internal/errors"
+	"github.com/jpcode092/crm-freela/internal/models"
+	"github.com/jpcode092/crm-freela/internal/services"
+	"github.com/jpcode092/crm-freela/pkg/logger"
+	"github.com/jpcode092/crm-freela/pkg/webauthn/virtual"
+)
+
+const testWebAuthnOrigin = "http://localhost:3000"
+
+// fakeCredentialRepo guarda as passkeys em memória
+type fakeCredentialRepo struct {
+	credentials []*models.WebAuthnCredential
+}
+
+func (r *fakeCredentialRepo) Create(credential *models.WebAuthnCredential) error {
+	for _, existing := range r.credentials {
+		if string(existing.CredentialID) == string(credential.CredentialID) {
+			return models.ErrDuplicateKey
+		}
+	}
+	credential.ID = uint(len(r.credentials) + 1)
+	r.credentials = append(r.credentials, credential)
+	return nil
+}
+
+func (r *fakeCredentialRepo) GetByCredentialID(credentialID []byte) (*models.WebAuthnCredential, error) {
+	for _, credential := range r.credentials {
+		if string(credential.CredentialID) == string(credentialID) {
+			copied := *credential
+			return &copied, nil
+		}
+	}
+	return nil, models.ErrRecordNotFound
+}
+
+func (r *fakeCredentialRepo) ListByUser(userID uint) ([]models.WebAuthnCredential, error) {
+	var credentials []models.WebAuthnCredential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			credentials = append(credentials, *credential)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *fakeCredentialRepo) UpdateSignCount(id uint, previous, signCount uint32, usedAt time.Time) (bool, error) {
+	for _, credential := range r.credentials {
+		if credential.ID == id && credential.SignCount == previous {
+			credential.SignCount = signCount
+			credential.LastUsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeCredentialRepo) Delete(userID, id uint) (bool, error) {
+	for i, credential := range r.credentials {
+		if credential.ID == id && credential.UserID == userID {
+			r.credentials = append(r.credentials[:i], r.credentials[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeWebAuthnAuthService conhece um único usuário e conclui o login sem emitir tokens
+type fakeWebAuthnAuthService struct {
+	services.AuthService
+	user     *models.User
+	loggedIn []*models.User
+}
+
+func (s *fakeWebAuthnAuthService) GetUserByID(id uint) (*models.User, error) {
+	if id != s.user.ID {
+		return nil, apperrors.ErrUserNotFound
+	}
+	return s.user, nil
+}
+
+func (s *fakeWebAuthnAuthService) LoginVerifiedUser(user *models.User, meta services.SessionMeta) (*services.LoginResult, error) {
+	s.loggedIn = append(s.loggedIn, user)
+	return &services.LoginResult{User: user}, nil
+}
+
+func newTestWebAuthnService(t *testing.T, rpID string, repo *fakeCredentialRepo, auth *fakeWebAuthnAuthService) services.WebAuthnService {
+	t.Helper()
+
+	config := &configs.Config{
+		Login: configs.LoginConfig{IPMaxFailedAttempts: 100},
+		WebAuthn: configs.WebAuthnConfig{
+			RPID:                    rpID,
+			RPName:                  "CRM Freela",
+			Origins:                 []string{testWebAuthnOrigin},
+			Timeout:                 time.Minute,
+			RequireUserVerification: true,
+		},
+	}
+
+	service, err := services.NewWebAuthnService(repo, auth, logger.NewLogger(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+// register cadastra no serviço uma passkey criada pelo autenticador virtual
+func register(t *testing.T, service services.WebAuthnService, authenticator *virtual.Authenticator, userID uint) *models.WebAuthnCredential {
+	t.Helper()
+
+	options, err := service.BeginRegistration(userID)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	response, err := authenticator.Create(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := service.FinishRegistration(userID, "Notebook", response)
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return credential
+}
+
+func TestWebAuthnRegistrationAndLogin(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com", Name: "Ana"}}
+	service := newTestWebAuthnService(t, "localhost", repo, auth)
+	authenticator := virtual.New(testWebAuthnOrigin)
+
+	credential := register(t, service, authenticator, 7)
+	if credential.UserID != 7 || credential.Name != "Notebook" || credential.SignCount != 0 {
+		t.Fatalf("passkey inesperada: %+v", credential)
+	}
+
+	for i := 1; i <= 2; i++ {
+		options, err := service.BeginLogin("127.0.0.1")
+		if err != nil {
+			t.Fatalf("BeginLogin: %v", err)
+		}
+		assertion, err := authenticator.Get(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := service.FinishLogin(assertion, services.SessionMeta{IP: "127.0.0.1"})
+		if err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+		if result.User.ID != 7 {
+			t.Fatalf("login do usuário %d, esperado 7", result.User.ID)
+		}
+		if got := repo.credentials[0].SignCount; got != uint32(i) {
+			t.Fatalf("contador = %d, esperado %d", got, i)
+		}
+	}
+}
+
+func TestWebAuthnRegistrationRejectsCeremonyOfAnotherUser(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com"}}
+	service := newTestWebAuthnService(t, "localhost", repo, auth)
+
+	options, err := service.BeginRegistration(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := virtual.New(testWebAuthnOrigin).Create(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.FinishRegistration(8, "Notebook", response); err != apperrors.ErrInvalidPasskey {
+		t.Fatalf("erro = %v, esperado ErrInvalidPasskey", err)
+	}
+	if len(repo.credentials) != 0 {
+		t.Fatal("passkey gravada para outro usuário")
+	}
+}
+
+func TestWebAuthnRegistrationRejectsWrongRPID(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com"}}
+	service := newTestWebAuthnService(t, "localhost", repo, auth)
+
+	options, err := service.BeginRegistration(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.RP.ID = "evil.example.com"
+	response, err := virtual.New(testWebAuthnOrigin).Create(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.FinishRegistration(7, "Notebook", response); err != apperrors.ErrInvalidPasskey {
+		t.Fatalf("erro = %v, esperado ErrInvalidPasskey", err)
+	}
+	if len(repo.credentials) != 0 {
+		t.Fatal("passkey de outro RP ID gravada")
+	}
+}
+
+func TestWebAuthnLoginRejectsWrongRPID(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com"}}
+	authenticator := virtual.New(testWebAuthnOrigin)
+	register(t, newTestWebAuthnService(t, "localhost", repo, auth), authenticator, 7)
+
+	// Mesmo banco de passkeys, mas o relying party agora é outro domínio
+	service := newTestWebAuthnService(t, "crm.example.com", repo, auth)
+	options, err := service.BeginLogin("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.RPID = "localhost"
+	assertion, err := authenticator.Get(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.FinishLogin(assertion, services.SessionMeta{}); err != apperrors.ErrInvalidCredentials {
+		t.Fatalf("erro = %v, esperado ErrInvalidCredentials", err)
+	}
+	if len(auth.loggedIn) != 0 {
+		t.Fatal("login concluído com passkey de outro RP ID")
+	}
+}
+
+func TestWebAuthnLoginRejectsSignCountRegression(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com"}}
+	service := newTestWebAuthnService(t, "localhost", repo, auth)
+	authenticator := virtual.New(testWebAuthnOrigin)
+	register(t, service, authenticator, 7)
+
+	// O servidor já viu o contador 5: um autenticador que envia 1 pode ser um clone
+	repo.credentials[0].SignCount = 5
+
+	options, err := service.BeginLogin("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion, err := authenticator.Get(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.FinishLogin(assertion, services.SessionMeta{}); err != apperrors.ErrInvalidCredentials {
+		t.Fatalf("erro = %v, esperado ErrInvalidCredentials", err)
+	}
+	if got := repo.credentials[0].SignCount; got != 5 {
+		t.Fatalf("contador = %d, esperado 5 (inalterado)", got)
+	}
+	if len(auth.loggedIn) != 0 {
+		t.Fatal("login concluído com contador regredido")
+	}
+}
+
+func TestWebAuthnLoginRejectsReplayedAssertion(t *testing.T) {
+	repo := &fakeCredentialRepo{}
+	auth := &fakeWebAuthnAuthService{user: &models.User{ID: 7, Email: "ana@example.com"}}
+	service := newTestWebAuthnService(t, "localhost", repo, auth)
+	authenticator := virtual.New(testWebAuthnOrigin)
+	register(t, service, authenticator, 7)
+
+	options, err := service.BeginLogin("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion, err := authenticator.Get(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.FinishLogin(assertion, services.SessionMeta{}); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if _, err := service.FinishLogin(assertion, services.SessionMeta{}); err != apperrors.ErrInvalidCredentials {
+		t.Fatalf("erro = %v, esperado ErrInvalidCredentials ao repetir a resposta", err)
+	}
+	if len(auth.loggedIn) != 1 {
+		t.Fatalf("%d logins, esperado 1", len(auth.loggedIn))
+	}
+}
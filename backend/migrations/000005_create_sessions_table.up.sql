@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    family_id VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_agent VARCHAR(255),
+    ip VARCHAR(45),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+
+COMMENT ON TABLE sessions IS 'Refresh tokens emitidos; cada rotação gera uma nova linha na mesma família';
+COMMENT ON COLUMN sessions.family_id IS 'Identificador da família de sessões (um login)';
+COMMENT ON COLUMN sessions.token_hash IS 'Hash SHA-256 do refresh token (o token em si nunca é armazenado)';
+COMMENT ON COLUMN sessions.rotated_at IS 'Data em que o token foi trocado por um novo; reapresentá-lo revoga a família';
+COMMENT ON COLUMN sessions.revoked_at IS 'Data de revogação da sessão';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_revoked_tokens_expires_at;
+DROP TABLE IF EXISTS revoked_tokens;
+ALTER TABLE sessions DROP COLUMN IF EXISTS access_token_id;
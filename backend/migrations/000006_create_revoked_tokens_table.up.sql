@@ -0,0 +1,14 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS access_token_id VARCHAR(64);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+COMMENT ON TABLE revoked_tokens IS 'Access tokens revogados antes de expirar (logout, revogação de sessão)';
+COMMENT ON COLUMN revoked_tokens.jti IS 'Claim jti do access token revogado';
+COMMENT ON COLUMN revoked_tokens.expires_at IS 'Expiração do token; após esta data a linha pode ser removida';
+COMMENT ON COLUMN sessions.access_token_id IS 'jti do access token emitido junto com o refresh token';
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret VARCHAR(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_last_used_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+COMMENT ON COLUMN users.mfa_enabled IS 'Indica se a autenticação em dois fatores (TOTP) está ativa';
+COMMENT ON COLUMN users.mfa_secret IS 'Segredo TOTP cifrado com AES-256-GCM';
+COMMENT ON COLUMN users.mfa_last_used_step IS 'Última janela TOTP aceita (impede reutilização de códigos)';
+COMMENT ON TABLE mfa_recovery_codes IS 'Códigos de recuperação de uso único do MFA (apenas o hash SHA-256)';
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.failed_login_attempts IS 'Falhas de login consecutivas desde o último acesso bem-sucedido';
+COMMENT ON COLUMN users.locked_until IS 'Data até a qual o login da conta está bloqueado';
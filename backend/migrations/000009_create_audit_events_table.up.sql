@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER REFERENCES users(id),
+    user_id INTEGER REFERENCES users(id),
+    action VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    ip VARCHAR(45),
+    user_agent VARCHAR(255),
+    metadata TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_user_id ON audit_events(user_id);
+CREATE INDEX idx_audit_events_action ON audit_events(action);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+COMMENT ON TABLE audit_events IS 'Trilha de auditoria (somente inserção)';
+COMMENT ON COLUMN audit_events.actor_id IS 'Usuário que executou a ação';
+COMMENT ON COLUMN audit_events.user_id IS 'Conta afetada pela ação';
+COMMENT ON COLUMN audit_events.metadata IS 'Detalhes da ação em JSON';
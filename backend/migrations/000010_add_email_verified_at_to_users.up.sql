@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Contas ativas criadas antes da confirmação de e-mail são consideradas verificadas
+UPDATE users SET email_verified_at = created_at WHERE status = 'active' AND email_verified_at IS NULL;
+
+COMMENT ON COLUMN users.email_verified_at IS 'Data de confirmação do e-mail; contas novas ficam inativas até confirmar';
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+COMMENT ON TABLE api_tokens IS 'Tokens de acesso pessoal para scripts e integrações';
+COMMENT ON COLUMN api_tokens.prefix IS 'Início do token, exibido na listagem para identificação';
+COMMENT ON COLUMN api_tokens.token_hash IS 'Hash SHA-256 do token (o token em si nunca é armazenado)';
+COMMENT ON COLUMN api_tokens.scopes IS 'Escopos concedidos, separados por espaço (ex.: clients:read payments:write)';
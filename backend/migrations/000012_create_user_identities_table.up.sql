@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    provider VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+CREATE UNIQUE INDEX idx_user_identities_provider_subject ON user_identities(provider, subject);
+
+COMMENT ON TABLE user_identities IS 'Contas em provedores de identidade OpenID Connect vinculadas aos usuários';
+COMMENT ON COLUMN user_identities.provider IS 'Emissor (issuer) do provedor';
+COMMENT ON COLUMN user_identities.subject IS 'Identificador estável do usuário no provedor (claim sub)';
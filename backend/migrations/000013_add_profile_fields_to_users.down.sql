@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS pref_email_notifications;
+ALTER TABLE users DROP COLUMN IF EXISTS pref_timezone;
+ALTER TABLE users DROP COLUMN IF EXISTS pref_language;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
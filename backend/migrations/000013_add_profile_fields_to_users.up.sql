@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pref_language VARCHAR(10) NOT NULL DEFAULT 'pt-BR';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pref_timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pref_email_notifications BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN users.pending_email IS 'Novo e-mail aguardando confirmação; só substitui email após o link ser aberto';
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reset_token VARCHAR(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reset_token_expires TIMESTAMP WITH TIME ZONE;
+
+-- Tokens gravados em texto puro antes desta migração deixam de valer
+UPDATE users SET reset_token = NULL WHERE reset_token IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_reset_token ON users(reset_token) WHERE reset_token IS NOT NULL;
+
+COMMENT ON COLUMN users.reset_token IS 'Hash SHA-256 do token de recuperação de senha (uso único)';
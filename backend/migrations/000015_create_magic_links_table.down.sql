@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS password_login_disabled;
+DROP TABLE IF EXISTS magic_links;
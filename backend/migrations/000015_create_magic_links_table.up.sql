@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS magic_links (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    ip VARCHAR(45),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_magic_links_user_id ON magic_links(user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_login_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON TABLE magic_links IS 'Links de login sem senha enviados por e-mail (uso único)';
+COMMENT ON COLUMN magic_links.token_hash IS 'Hash SHA-256 do token (o token em si nunca é armazenado)';
+COMMENT ON COLUMN users.password_login_disabled IS 'Quando verdadeiro, o login com senha é recusado e o usuário entra por link ou provedor de identidade';
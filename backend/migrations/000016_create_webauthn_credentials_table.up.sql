@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS web_authn_credentials (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    algorithm INTEGER NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports VARCHAR(100),
+    aaguid BYTEA,
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_web_authn_credentials_user_id ON web_authn_credentials(user_id);
+
+COMMENT ON TABLE web_authn_credentials IS 'Passkeys (credenciais WebAuthn) cadastradas pelos usuários';
+COMMENT ON COLUMN web_authn_credentials.public_key IS 'Chave pública no formato COSE enviado pelo autenticador';
+COMMENT ON COLUMN web_authn_credentials.sign_count IS 'Último contador de assinaturas; uma regressão indica possível clonagem da credencial';
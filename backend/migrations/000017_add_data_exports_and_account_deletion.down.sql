@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    archive BYTEA NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    downloaded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON TABLE data_exports IS 'Exportações dos dados do usuário (LGPD), baixadas pelo link enviado por e-mail';
+COMMENT ON COLUMN data_exports.token_hash IS 'Hash SHA-256 do token do link (o token em si nunca é armazenado)';
+COMMENT ON COLUMN users.deletion_scheduled_at IS 'Data em que clientes, tarefas e pagamentos serão removidos e o usuário anonimizado, após o pedido de exclusão';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_clients_search_vector;
+ALTER TABLE clients DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS crm_portuguese;
+DROP EXTENSION IF EXISTS unaccent;
@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'crm_portuguese') THEN
+        CREATE TEXT SEARCH CONFIGURATION crm_portuguese (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION crm_portuguese
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('crm_portuguese', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('crm_portuguese', coalesce(company, '')), 'B') ||
+    setweight(to_tsvector('crm_portuguese', regexp_replace(coalesce(email, ''), '[@._+-]+', ' ', 'g')), 'B') ||
+    setweight(to_tsvector('crm_portuguese', coalesce(notes, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_clients_search_vector ON clients USING GIN (search_vector);
+
+COMMENT ON TEXT SEARCH CONFIGURATION crm_portuguese IS 'Português sem acentos (unaccent + stemmer), usado na busca de clientes';
+COMMENT ON COLUMN clients.search_vector IS 'Documento da busca textual: nome (peso A), empresa e e-mail quebrado em partes (B) e observações (C)';
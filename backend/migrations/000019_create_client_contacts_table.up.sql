@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS client_contacts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    name VARCHAR(100) NOT NULL,
+    role VARCHAR(100),
+    email VARCHAR(100),
+    phone VARCHAR(20),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    is_billing BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_client_contacts_user_id ON client_contacts(user_id);
+CREATE INDEX idx_client_contacts_client_id ON client_contacts(client_id);
+CREATE INDEX idx_client_contacts_deleted_at ON client_contacts(deleted_at);
+CREATE UNIQUE INDEX idx_client_contacts_primary ON client_contacts(client_id) WHERE is_primary AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_client_contacts_billing ON client_contacts(client_id) WHERE is_billing AND deleted_at IS NULL;
+
+COMMENT ON TABLE client_contacts IS 'Pessoas de contato de cada cliente';
+COMMENT ON COLUMN client_contacts.is_primary IS 'Contato principal; no máximo um por cliente';
+COMMENT ON COLUMN client_contacts.is_billing IS 'Destinatário das cobranças e lembretes de pagamento; no máximo um por cliente';
@@ -0,0 +1,4 @@
+ALTER TABLE clients DROP COLUMN IF EXISTS custom_fields;
+DROP TABLE IF EXISTS custom_field_definitions;
+DROP TABLE IF EXISTS client_tags;
+DROP TABLE IF EXISTS tags;
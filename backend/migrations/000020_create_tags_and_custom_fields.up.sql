@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags(user_id, name);
+
+CREATE TABLE IF NOT EXISTS client_tags (
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    tag_id INTEGER NOT NULL REFERENCES tags(id),
+    PRIMARY KEY (client_id, tag_id)
+);
+
+CREATE INDEX idx_client_tags_tag_id ON client_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    key VARCHAR(50) NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    options JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_custom_fields_user_key ON custom_field_definitions(user_id, key);
+
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON TABLE tags IS 'Tags que cada usuário usa para organizar os clientes';
+COMMENT ON TABLE client_tags IS 'Associação entre clientes e tags';
+COMMENT ON TABLE custom_field_definitions IS 'Campos personalizados de clientes definidos por cada usuário';
+COMMENT ON COLUMN custom_field_definitions.type IS 'Tipo do campo: text, number, date ou select';
+COMMENT ON COLUMN custom_field_definitions.options IS 'Valores aceitos pelos campos select';
+COMMENT ON COLUMN clients.custom_fields IS 'Valores dos campos personalizados, indexados pela chave da definição';
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS client_activities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    type VARCHAR(20) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    body TEXT,
+    task_id INTEGER REFERENCES tasks(id),
+    payment_id INTEGER REFERENCES payments(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_client_activities_user_id ON client_activities(user_id);
+CREATE INDEX idx_client_activities_timeline ON client_activities(client_id, occurred_at);
+CREATE INDEX idx_client_activities_task_id ON client_activities(task_id);
+CREATE INDEX idx_client_activities_payment_id ON client_activities(payment_id);
+CREATE INDEX idx_client_activities_deleted_at ON client_activities(deleted_at);
+
+COMMENT ON TABLE client_activities IS 'Histórico de interações com cada cliente';
+COMMENT ON COLUMN client_activities.type IS 'Tipo da atividade (ver models.ActivityType)';
+COMMENT ON COLUMN client_activities.occurred_at IS 'Quando a interação aconteceu, que pode ser anterior ao registro';
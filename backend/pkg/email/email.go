@@ -2,12 +2,31 @@ package email
 
 import (
 	"fmt"
+	"html"
 	"net/smtp"
+	"time"
 )
 
 // EmailService define a interface para envio de emails
 type EmailService interface {
-	SendPasswordReset(to, token string) error
+	SendPasswordReset(to, name, link string) error
+	SendEmailVerification(to, name, link string) error
+	SendEmailChangeConfirmation(to, name, link string) error
+	SendEmailChangeRequested(to, name, newEmail string) error
+	SendMagicLink(to, name, link string) error
+	SendDataExportReady(to, name, link string) error
+	SendAccountDeletionScheduled(to, name string, scheduledAt time.Time) error
+	SendPaymentInvoice(to, name string, payment PaymentNotice) error
+	SendPaymentReminder(to, name string, payment PaymentNotice) error
+}
+
+// PaymentNotice representa os dados de um pagamento enviados ao cliente na cobrança ou no lembrete
+type PaymentNotice struct {
+	SenderName    string // freelancer que emitiu a cobrança
+	InvoiceNumber string
+	Description   string
+	Amount        string // valor já formatado com a moeda (ex.: BRL 1500.00)
+	DueDate       time.Time
 }
 
 type emailService struct {
@@ -27,17 +46,140 @@ func NewEmailService(from, password, host, port string) EmailService {
 	}
 }
 
-// SendPasswordReset envia um email com o token de recuperação de senha
-func (s *emailService) SendPasswordReset(to, token string) error {
+// SendPasswordReset envia um email com o link de recuperação de senha
+func (s *emailService) SendPasswordReset(to, name, link string) error {
 	subject := "Recuperação de Senha - CRM Freela"
 	body := fmt.Sprintf(`
-		<h2>Recuperação de Senha</h2>
+		<h2>Olá, %s!</h2>
 		<p>Você solicitou a recuperação de senha. Use o link abaixo para redefinir sua senha:</p>
-		<p><a href="http://localhost:3000/auth/reset-password?token=%s">Redefinir Senha</a></p>
+		<p><a href="%s">Redefinir Senha</a></p>
 		<p>Se você não solicitou a recuperação de senha, ignore este email.</p>
-		<p>O link é válido por 1 hora.</p>
-	`, token)
+		<p>O link pode ser usado uma única vez e expira em breve.</p>
+	`, name, link)
+
+	return s.send(to, subject, body)
+}
+
+// SendEmailVerification envia um email com o link de confirmação do endereço cadastrado
+func (s *emailService) SendEmailVerification(to, name, link string) error {
+	subject := "Confirme seu e-mail - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Obrigado por se cadastrar no CRM Freela. Confirme seu endereço de e-mail para ativar sua conta:</p>
+		<p><a href="%s">Confirmar E-mail</a></p>
+		<p>Se você não criou uma conta, ignore este email.</p>
+	`, name, link)
+
+	return s.send(to, subject, body)
+}
+
+// SendEmailChangeConfirmation envia ao novo endereço o link que confirma a troca de e-mail
+func (s *emailService) SendEmailChangeConfirmation(to, name, link string) error {
+	subject := "Confirme seu novo e-mail - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Recebemos uma solicitação para usar este endereço na sua conta do CRM Freela. Confirme a troca pelo link abaixo:</p>
+		<p><a href="%s">Confirmar Novo E-mail</a></p>
+		<p>Se você não fez essa solicitação, ignore este email.</p>
+	`, name, link)
+
+	return s.send(to, subject, body)
+}
+
+// SendEmailChangeRequested avisa o endereço atual de que foi solicitada a troca de e-mail
+func (s *emailService) SendEmailChangeRequested(to, name, newEmail string) error {
+	subject := "Solicitação de troca de e-mail - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Foi solicitada a troca do e-mail da sua conta para <strong>%s</strong>. A troca só será feita após a confirmação no novo endereço.</p>
+		<p>Se você não fez essa solicitação, altere sua senha imediatamente.</p>
+	`, name, html.EscapeString(newEmail))
+
+	return s.send(to, subject, body)
+}
+
+// SendMagicLink envia o link de login sem senha
+func (s *emailService) SendMagicLink(to, name, link string) error {
+	subject := "Seu link de acesso - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Use o link abaixo para entrar no CRM Freela sem senha:</p>
+		<p><a href="%s">Entrar no CRM Freela</a></p>
+		<p>O link pode ser usado uma única vez e expira em poucos minutos.</p>
+		<p>Se você não solicitou o acesso, ignore este email.</p>
+	`, name, link)
+
+	return s.send(to, subject, body)
+}
+
+// SendDataExportReady envia o link de download da exportação dos dados da conta
+func (s *emailService) SendDataExportReady(to, name, link string) error {
+	subject := "Seus dados estão prontos - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>A exportação dos dados da sua conta foi gerada. Use o link abaixo para baixar o arquivo ZIP:</p>
+		<p><a href="%s">Baixar Meus Dados</a></p>
+		<p>O link expira em breve. Se você não solicitou a exportação, altere sua senha imediatamente.</p>
+	`, name, link)
+
+	return s.send(to, subject, body)
+}
+
+// SendAccountDeletionScheduled confirma o pedido de exclusão da conta e a data da remoção dos dados
+func (s *emailService) SendAccountDeletionScheduled(to, name string, scheduledAt time.Time) error {
+	subject := "Exclusão da conta agendada - CRM Freela"
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Recebemos o pedido de exclusão da sua conta. Seus clientes, tarefas e pagamentos serão removidos definitivamente em <strong>%s</strong>.</p>
+		<p>Até essa data você pode entrar na sua conta e cancelar a exclusão na página do perfil.</p>
+		<p>Se você não fez esse pedido, entre na sua conta, cancele a exclusão e altere sua senha.</p>
+	`, name, scheduledAt.Format("02/01/2006 15:04"))
+
+	return s.send(to, subject, body)
+}
+
+// SendPaymentInvoice envia ao cliente a cobrança de um pagamento pendente
+func (s *emailService) SendPaymentInvoice(to, name string, payment PaymentNotice) error {
+	subject := fmt.Sprintf("Cobrança de %s - vencimento em %s", payment.SenderName, payment.DueDate.Format("02/01/2006"))
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p><strong>%s</strong> enviou a cobrança abaixo pelo CRM Freela.</p>
+		%s
+		<p>Em caso de dúvidas, responda diretamente a quem emitiu a cobrança.</p>
+	`, html.EscapeString(name), html.EscapeString(payment.SenderName), paymentNoticeTable(payment))
+
+	return s.send(to, subject, body)
+}
+
+// SendPaymentReminder envia ao cliente o lembrete de um pagamento vencido
+func (s *emailService) SendPaymentReminder(to, name string, payment PaymentNotice) error {
+	subject := fmt.Sprintf("Lembrete de pagamento - %s", payment.SenderName)
+	body := fmt.Sprintf(`
+		<h2>Olá, %s!</h2>
+		<p>Este é um lembrete de <strong>%s</strong>: o pagamento abaixo venceu em %s e ainda não foi identificado.</p>
+		%s
+		<p>Se o pagamento já foi feito, desconsidere este email.</p>
+	`, html.EscapeString(name), html.EscapeString(payment.SenderName), payment.DueDate.Format("02/01/2006"), paymentNoticeTable(payment))
+
+	return s.send(to, subject, body)
+}
+
+// paymentNoticeTable monta a tabela com os dados do pagamento usada na cobrança e no lembrete
+func paymentNoticeTable(payment PaymentNotice) string {
+	rows := ""
+	if payment.InvoiceNumber != "" {
+		rows += fmt.Sprintf("<tr><td>Fatura</td><td>%s</td></tr>", html.EscapeString(payment.InvoiceNumber))
+	}
+	if payment.Description != "" {
+		rows += fmt.Sprintf("<tr><td>Descrição</td><td>%s</td></tr>", html.EscapeString(payment.Description))
+	}
+	rows += fmt.Sprintf("<tr><td>Valor</td><td>%s</td></tr>", html.EscapeString(payment.Amount))
+	rows += fmt.Sprintf("<tr><td>Vencimento</td><td>%s</td></tr>", payment.DueDate.Format("02/01/2006"))
+	return "<table>" + rows + "</table>"
+}
 
+// send monta a mensagem HTML e a envia via SMTP
+func (s *emailService) send(to, subject, body string) error {
 	msg := fmt.Sprintf("To: %s\r\n"+
 		"Subject: %s\r\n"+
 		"MIME-Version: 1.0\r\n"+
@@ -0,0 +1,177 @@
+// Package jwk implementa a representação JSON de chaves públicas (RFC 7517)
+// e o identificador de chave por thumbprint (RFC 7638).
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Key representa uma chave pública no formato JWK
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// Set representa um JWKS, o documento publicado em /.well-known/jwks.json
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// New converte uma chave pública (RSA, EC ou Ed25519) em JWK de assinatura.
+// O kid é o thumbprint RFC 7638 da chave.
+func New(publicKey interface{}, alg string) (Key, error) {
+	var key Key
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		key = Key{
+			Kty: "RSA",
+			N:   encode(pub.N.Bytes()),
+			E:   encode(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		key = Key{
+			Kty: "EC",
+			Crv: pub.Curve.Params().Name,
+			X:   encode(pub.X.FillBytes(make([]byte, size))),
+			Y:   encode(pub.Y.FillBytes(make([]byte, size))),
+		}
+	case ed25519.PublicKey:
+		key = Key{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   encode(pub),
+		}
+	default:
+		return Key{}, fmt.Errorf("tipo de chave %T não suportado", publicKey)
+	}
+
+	kid, err := key.Thumbprint()
+	if err != nil {
+		return Key{}, err
+	}
+	key.Kid = kid
+	key.Use = "sig"
+	key.Alg = alg
+	return key, nil
+}
+
+// Thumbprint calcula o thumbprint SHA-256 da chave (RFC 7638), usado como kid
+func (k Key) Thumbprint() (string, error) {
+	// Somente os membros obrigatórios, em ordem lexicográfica
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	default:
+		return "", fmt.Errorf("tipo de chave %q não suportado", k.Kty)
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return encode(sum[:]), nil
+}
+
+// PublicKey converte a JWK na chave pública correspondente (RSA, EC ou Ed25519)
+func (k Key) PublicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("expoente RSA inválido")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("curva %q não suportada", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ponto fora da curva")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("curva %q não suportada", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("chave Ed25519 inválida")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("tipo de chave %q não suportado", k.Kty)
+}
+
+// encode codifica bytes em base64url sem padding
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeBigInt decodifica um inteiro em base64url sem padding
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("inteiro inválido na JWK")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,93 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// Algoritmos de assinatura aceitos para as chaves carregadas por LoadPrivateKey
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// minRSABits é o tamanho mínimo aceito para chaves RSA
+const minRSABits = 2048
+
+// Algorithm retorna o algoritmo de assinatura correspondente à chave pública (RS256 ou EdDSA)
+func Algorithm(publicKey crypto.PublicKey) (string, error) {
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return "", fmt.Errorf("chaves RSA devem ter pelo menos %d bits", minRSABits)
+		}
+		return AlgRS256, nil
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	default:
+		return "", fmt.Errorf("tipo de chave %T não suportado (use RSA ou Ed25519)", publicKey)
+	}
+}
+
+// LoadPrivateKey lê uma chave privada PEM (PKCS#8 ou PKCS#1)
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("chave privada em %s não suportada", path)
+		}
+		if _, isEC := signer.(*ecdsa.PrivateKey); isEC {
+			return nil, fmt.Errorf("chave privada em %s não suportada (use RSA ou Ed25519)", path)
+		}
+		return signer, nil
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave privada %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadPublicKey lê uma chave pública PEM (PKIX); aceita também uma chave privada, da qual extrai a pública
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	private, err := LoadPrivateKey(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave pública %s: %w", path, err)
+	}
+	return private.Public(), nil
+}
+
+// readPEM lê o primeiro bloco PEM do arquivo
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave %s: %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("arquivo %s não contém uma chave PEM", path)
+	}
+	return block, nil
+}
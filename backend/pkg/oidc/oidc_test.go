@@ -0,0 +1,359 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jpcode092/crm-freela/pkg/jwk"
+	"github.com/jpcode092/crm-freela/pkg/oidc"
+)
+
+const (
+	testClientID    = "crm-freela"
+	testRedirectURL = "http://localhost:3000/auth/oidc/callback"
+)
+
+// testProvider é um provedor de identidade mínimo servido por httptest: descoberta, JWKS e token endpoint
+type testProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+// authorization é um código emitido pelo provedor, com o code_challenge e o ID token que ele entrega
+type authorization struct {
+	challenge string
+	idToken   string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("erro ao gerar chave: %v", err)
+	}
+	public, err := jwk.New(&key.PublicKey, "RS256")
+	if err != nil {
+		t.Fatalf("erro ao gerar JWK: %v", err)
+	}
+
+	p := &testProvider{key: key, kid: public.Kid, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, oidc.Discovery{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, jwk.Set{Keys: []jwk.Key{public}})
+	})
+	mux.HandleFunc("/token", p.token)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// token troca o código pelo ID token, exigindo o code_verifier que corresponde ao code_challenge
+func (p *testProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != testClientID ||
+		r.PostForm.Get("redirect_uri") != testRedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	p.mu.Lock()
+	auth, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	if !ok || oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != auth.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": auth.idToken, "token_type": "Bearer"})
+}
+
+// authorize registra um código de autorização, como o provedor faria após o login do usuário
+func (p *testProvider) authorize(challenge, idToken string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	code := "code-" + challenge[:8]
+	p.codes[code] = authorization{challenge: challenge, idToken: idToken}
+	return code
+}
+
+// claims retorna claims válidos para o cliente de teste, que cada caso pode alterar
+func (p *testProvider) claims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            "user-123",
+		"aud":            testClientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          "ana@example.com",
+		"email_verified": true,
+		"name":           "Ana",
+	}
+}
+
+// sign assina os claims com a chave publicada no JWKS
+func (p *testProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	return signWith(t, p.key, p.kid, claims)
+}
+
+func signWith(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("erro ao assinar ID token: %v", err)
+	}
+	return signed
+}
+
+func (p *testProvider) client() *oidc.Client {
+	return oidc.NewClient(oidc.Config{Issuer: p.server.URL, ClientID: testClientID, RedirectURL: testRedirectURL}, p.server.Client())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Exemplo do apêndice B da RFC 7636
+	got := oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Fatalf("CodeChallenge = %q, esperado %q", got, want)
+	}
+}
+
+func TestGenerateVerifier(t *testing.T) {
+	a, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A RFC 7636 exige entre 43 e 128 caracteres do alfabeto não reservado
+	if len(a) != 43 || strings.ContainsAny(a, "+/=") {
+		t.Fatalf("verifier fora do formato da RFC 7636: %q", a)
+	}
+	if a == b {
+		t.Fatal("dois verifiers iguais")
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	p := newTestProvider(t)
+
+	raw, err := p.client().AuthCodeURL(context.Background(), "state-1", "nonce-1", "challenge-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != p.server.URL+"/authorize" {
+		t.Fatalf("endpoint = %q", got)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := u.Query().Get(name); got != value {
+			t.Errorf("%s = %q, esperado %q", name, got, value)
+		}
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	p := newTestProvider(t)
+	client := oidc.NewClient(oidc.Config{Issuer: p.server.URL + "/outro", ClientID: testClientID}, p.server.Client())
+
+	_, err := client.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	if !errors.Is(err, oidc.ErrDiscovery) {
+		t.Fatalf("erro = %v, esperado ErrDiscovery", err)
+	}
+}
+
+func TestExchangeAndVerify(t *testing.T) {
+	p := newTestProvider(t)
+	client := p.client()
+	ctx := context.Background()
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := p.authorize(oidc.CodeChallenge(verifier), p.sign(t, p.claims("nonce-1")))
+
+	rawIDToken, err := client.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	idToken, err := client.VerifyIDToken(ctx, rawIDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if idToken.Issuer != p.server.URL || idToken.Subject != "user-123" || idToken.Email != "ana@example.com" || !idToken.EmailVerified || idToken.Name != "Ana" {
+		t.Fatalf("ID token inesperado: %+v", idToken)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	p := newTestProvider(t)
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := p.authorize(oidc.CodeChallenge(verifier), p.sign(t, p.claims("nonce-1")))
+
+	_, err = p.client().Exchange(context.Background(), code, other)
+	if !errors.Is(err, oidc.ErrExchange) {
+		t.Fatalf("erro = %v, esperado ErrExchange", err)
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	p := newTestProvider(t)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"nonce diferente", func() string {
+			return p.sign(t, p.claims("outro-nonce"))
+		}},
+		{"emissor diferente", func() string {
+			claims := p.claims("nonce-1")
+			claims["iss"] = "https://idp.example.com"
+			return p.sign(t, claims)
+		}},
+		{"audiência diferente", func() string {
+			claims := p.claims("nonce-1")
+			claims["aud"] = "outra-aplicacao"
+			return p.sign(t, claims)
+		}},
+		{"várias audiências sem azp", func() string {
+			claims := p.claims("nonce-1")
+			claims["aud"] = []string{testClientID, "outra-aplicacao"}
+			return p.sign(t, claims)
+		}},
+		{"expirado", func() string {
+			claims := p.claims("nonce-1")
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return p.sign(t, claims)
+		}},
+		{"sem expiração", func() string {
+			claims := p.claims("nonce-1")
+			delete(claims, "exp")
+			return p.sign(t, claims)
+		}},
+		{"sem sub", func() string {
+			claims := p.claims("nonce-1")
+			delete(claims, "sub")
+			return p.sign(t, claims)
+		}},
+		{"assinado por outra chave com o mesmo kid", func() string {
+			return signWith(t, otherKey, p.kid, p.claims("nonce-1"))
+		}},
+		{"kid desconhecido", func() string {
+			return signWith(t, otherKey, "desconhecido", p.claims("nonce-1"))
+		}},
+		{"HS256", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims("nonce-1"))
+			token.Header["kid"] = p.kid
+			signed, err := token.SignedString([]byte("segredo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return signed
+		}},
+		{"sem assinatura", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, p.claims("nonce-1"))
+			signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return signed
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.client().VerifyIDToken(context.Background(), tt.token(), "nonce-1")
+			if !errors.Is(err, oidc.ErrInvalidToken) {
+				t.Fatalf("erro = %v, esperado ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenAcceptsAuthorizedParty(t *testing.T) {
+	p := newTestProvider(t)
+
+	claims := p.claims("nonce-1")
+	claims["aud"] = []string{testClientID, "outra-aplicacao"}
+	claims["azp"] = testClientID
+
+	if _, err := p.client().VerifyIDToken(context.Background(), p.sign(t, claims), "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+}
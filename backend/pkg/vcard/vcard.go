@@ -0,0 +1,352 @@
+// Package vcard lê contatos no formato vCard 3.0 (RFC 2426) e 4.0 (RFC 6350),
+// usado na exportação de contatos do Google, do iCloud e do Outlook.
+package vcard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Erros de leitura de vCard
+var (
+	ErrUnsupportedVersion = errors.New("versão de vCard não suportada (use 3.0 ou 4.0)")
+	ErrMalformed          = errors.New("vCard malformado")
+)
+
+// Card representa os dados de um contato usados pelo CRM
+type Card struct {
+	Version       string
+	FormattedName string   // FN
+	Name          []string // N: sobrenome, nome, nomes adicionais, prefixo e sufixo
+	Organization  string   // primeiro componente de ORG (a empresa)
+	Title         string
+	Emails        []string // o preferido primeiro
+	Phones        []string // o preferido primeiro
+	Addresses     []string // cada ADR em uma linha, com os componentes separados por vírgula
+	Note          string
+}
+
+// DisplayName retorna o nome de exibição do contato: o FN ou, na falta dele, o nome montado a partir de N
+func (c *Card) DisplayName() string {
+	if c.FormattedName != "" {
+		return c.FormattedName
+	}
+
+	var parts []string
+	for _, i := range []int{3, 1, 2, 0, 4} { // prefixo, nome, adicionais, sobrenome, sufixo
+		if i < len(c.Name) && c.Name[i] != "" {
+			parts = append(parts, c.Name[i])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// property representa uma linha de conteúdo do vCard (NOME;PARAMETROS:valor)
+type property struct {
+	name   string
+	params map[string][]string
+	value  string
+}
+
+// preferred indica se a propriedade foi marcada como preferida (TYPE=pref no 3.0, PREF=1 no 4.0)
+func (p property) preferred() bool {
+	return p.hasParam("TYPE", "pref") || p.hasParam("PREF", "1")
+}
+
+// hasParam indica se o parâmetro key tem o valor informado, sem distinção de maiúsculas
+func (p property) hasParam(key, value string) bool {
+	for _, v := range p.params[key] {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotedPrintable indica se o valor está em quoted-printable (ENCODING=QUOTED-PRINTABLE, ou só
+// QUOTED-PRINTABLE como no 2.1), como ainda gravam as agendas do Android e do Outlook
+func (p property) quotedPrintable() bool {
+	return p.hasParam("ENCODING", "QUOTED-PRINTABLE") || p.hasParam("TYPE", "QUOTED-PRINTABLE")
+}
+
+// decodedValue retorna o valor da propriedade em UTF-8, desfazendo o quoted-printable e convertendo do CHARSET
+func (p property) decodedValue() (string, error) {
+	value := p.value
+	if p.quotedPrintable() {
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(value)))
+		if err != nil {
+			return "", fmt.Errorf("%w: quoted-printable inválido em %s", ErrMalformed, p.name)
+		}
+		value = string(decoded)
+	}
+
+	if charsets := p.params["CHARSET"]; len(charsets) > 0 && !strings.EqualFold(charsets[0], "UTF-8") {
+		encoding, err := htmlindex.Get(charsets[0])
+		if err != nil {
+			return "", fmt.Errorf("%w: charset %q não suportado", ErrMalformed, charsets[0])
+		}
+		if value, err = encoding.NewDecoder().String(value); err != nil {
+			return "", fmt.Errorf("%w: texto inválido em %s", ErrMalformed, charsets[0])
+		}
+	}
+	return value, nil
+}
+
+// ranked guarda um valor com a indicação de preferência, para ordenar e-mails e telefones
+type ranked struct {
+	value     string
+	preferred bool
+}
+
+// Parse lê todos os contatos do arquivo
+func Parse(r io.Reader) ([]Card, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []Card
+	var current *Card
+	var emails, phones []ranked
+	for _, line := range lines {
+		if strings.TrimSpace(line.text) == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line.number, err)
+		}
+		if prop.value, err = prop.decodedValue(); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line.number, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCARD"):
+			if current != nil {
+				return nil, fmt.Errorf("linha %d: %w: BEGIN sem END anterior", line.number, ErrMalformed)
+			}
+			current = &Card{}
+			emails, phones = nil, nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCARD"):
+			if current == nil {
+				return nil, fmt.Errorf("linha %d: %w: END sem BEGIN", line.number, ErrMalformed)
+			}
+			if current.Version != "3.0" && current.Version != "4.0" {
+				return nil, fmt.Errorf("linha %d: %w", line.number, ErrUnsupportedVersion)
+			}
+			current.Emails = sortRanked(emails)
+			current.Phones = sortRanked(phones)
+			cards = append(cards, *current)
+			current = nil
+		case current == nil:
+			return nil, fmt.Errorf("linha %d: %w: propriedade fora de BEGIN:VCARD", line.number, ErrMalformed)
+		default:
+			applyProperty(current, prop, &emails, &phones)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: arquivo terminou sem END:VCARD", ErrMalformed)
+	}
+	return cards, nil
+}
+
+// applyProperty copia para o contato as propriedades usadas pelo CRM; as demais são ignoradas
+func applyProperty(card *Card, prop property, emails, phones *[]ranked) {
+	switch prop.name {
+	case "VERSION":
+		card.Version = strings.TrimSpace(prop.value)
+	case "FN":
+		card.FormattedName = strings.TrimSpace(unescape(prop.value))
+	case "N":
+		card.Name = splitStructured(prop.value)
+	case "ORG":
+		if parts := splitStructured(prop.value); len(parts) > 0 {
+			card.Organization = parts[0]
+		}
+	case "TITLE":
+		card.Title = strings.TrimSpace(unescape(prop.value))
+	case "EMAIL":
+		if value := strings.TrimSpace(unescape(prop.value)); value != "" {
+			*emails = append(*emails, ranked{value: strings.TrimPrefix(value, "mailto:"), preferred: prop.preferred()})
+		}
+	case "TEL":
+		if value := strings.TrimSpace(unescape(prop.value)); value != "" {
+			*phones = append(*phones, ranked{value: strings.TrimPrefix(value, "tel:"), preferred: prop.preferred()})
+		}
+	case "ADR":
+		var parts []string
+		for _, part := range splitStructured(prop.value) {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			card.Addresses = append(card.Addresses, strings.Join(parts, ", "))
+		}
+	case "NOTE":
+		card.Note = strings.TrimSpace(unescape(prop.value))
+	}
+}
+
+// numberedLine é uma linha lógica do arquivo com o número da linha física em que começa
+type numberedLine struct {
+	number int
+	text   string
+}
+
+// unfold junta as linhas dobradas: uma linha que começa com espaço ou tabulação continua a anterior,
+// assim como a seguinte a um valor quoted-printable terminado em '=' (quebra suave)
+func unfold(r io.Reader) ([]numberedLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []numberedLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff") // BOM do UTF-8
+		}
+		if len(lines) > 0 && softLineBreak(lines[len(lines)-1].text) {
+			last := &lines[len(lines)-1]
+			last.text = strings.TrimSuffix(last.text, "=") + text
+			continue
+		}
+		if len(lines) > 0 && (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, numberedLine{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// softLineBreak indica se a linha é um valor quoted-printable que continua na linha seguinte
+func softLineBreak(line string) bool {
+	if !strings.HasSuffix(line, "=") {
+		return false
+	}
+	head, _, found := strings.Cut(line, ":")
+	return found && strings.Contains(strings.ToUpper(head), "QUOTED-PRINTABLE")
+}
+
+// parseProperty separa o nome, os parâmetros e o valor de uma linha de conteúdo.
+// O grupo opcional (item1.EMAIL) é descartado.
+func parseProperty(line string) (property, error) {
+	// O valor começa no primeiro ':' fora de aspas; parâmetros podem conter ':' entre aspas
+	colon := -1
+	inQuotes := false
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("%w: linha sem ':'", ErrMalformed)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	name := strings.ToUpper(strings.TrimSpace(head[0]))
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	if name == "" {
+		return property{}, fmt.Errorf("%w: propriedade sem nome", ErrMalformed)
+	}
+
+	params := make(map[string][]string)
+	for _, param := range head[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			// vCard 2.1 permitia o tipo sem "TYPE=" (TEL;CELL); aceita por compatibilidade
+			key, value = "TYPE", param
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		for _, v := range strings.Split(value, ",") {
+			params[key] = append(params[key], strings.Trim(strings.TrimSpace(v), `"`))
+		}
+	}
+
+	return property{name: name, params: params, value: line[colon+1:]}, nil
+}
+
+// splitStructured separa os componentes de um valor estruturado (N, ORG, ADR) nos ';' não escapados
+func splitStructured(value string) []string {
+	var parts []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			parts = append(parts, strings.TrimSpace(unescape(current.String())))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	parts = append(parts, strings.TrimSpace(unescape(current.String())))
+	return parts
+}
+
+// unescape desfaz os escapes de texto do vCard (\n, \, \; e \\)
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sortRanked retorna os valores com os preferidos primeiro, mantendo a ordem do arquivo entre os demais
+func sortRanked(values []ranked) []string {
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].preferred && !values[j].preferred
+	})
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, v.value)
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package vcard_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpcode092/crm-freela/pkg/vcard"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []vcard.Card
+	}{
+		{
+			"linhas dobradas com espaço e tabulação",
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Maria da Silva \r\n Souza\r\nNOTE:Cliente desde\r\n\t 2020\r\nEND:VCARD\r\n",
+			[]vcard.Card{{Version: "3.0", FormattedName: "Maria da Silva Souza", Note: "Cliente desde 2020", Emails: []string{}, Phones: []string{}}},
+		},
+		{
+			"escapes de texto e valores estruturados",
+			"BEGIN:VCARD\nVERSION:4.0\nFN:Silva\\, João\nN:Silva;João;;Sr.;\nORG:Acme\\; Filial Sul;Vendas\nADR;TYPE=work:;;Rua das Flores\\, 100;São Paulo;SP;01000-000;Brasil\nNOTE:Linha 1\\nLinha 2\\; com \\\\ barra\nEND:VCARD\n",
+			[]vcard.Card{{
+				Version:       "4.0",
+				FormattedName: "Silva, João",
+				Name:          []string{"Silva", "João", "", "Sr.", ""},
+				Organization:  "Acme; Filial Sul",
+				Addresses:     []string{"Rua das Flores, 100, São Paulo, SP, 01000-000, Brasil"},
+				Note:          "Linha 1\nLinha 2; com \\ barra",
+				Emails:        []string{},
+				Phones:        []string{},
+			}},
+		},
+		{
+			"vários e-mails e telefones com o preferido primeiro",
+			"BEGIN:VCARD\nVERSION:3.0\nFN:Ana\nEMAIL;TYPE=INTERNET:ana@pessoal.com\nitem1.EMAIL;TYPE=INTERNET,pref:ana@acme.com.br\nTEL;CELL:11 98765-4321\nTEL;TYPE=WORK,VOICE,PREF:11 3333-4444\nTEL;TYPE=HOME:11 2222-1111\nEND:VCARD\n",
+			[]vcard.Card{{
+				Version:       "3.0",
+				FormattedName: "Ana",
+				Emails:        []string{"ana@acme.com.br", "ana@pessoal.com"},
+				Phones:        []string{"11 3333-4444", "11 98765-4321", "11 2222-1111"},
+			}},
+		},
+		{
+			"preferido do 4.0 e valores em URI",
+			"BEGIN:VCARD\nVERSION:4.0\nFN:Beto\nEMAIL:beto@pessoal.com\nEMAIL;PREF=1:mailto:beto@acme.com.br\nTEL;VALUE=uri;PREF=1:tel:+55-11-98765-4321\nEND:VCARD\n",
+			[]vcard.Card{{
+				Version:       "4.0",
+				FormattedName: "Beto",
+				Emails:        []string{"beto@acme.com.br", "beto@pessoal.com"},
+				Phones:        []string{"+55-11-98765-4321"},
+			}},
+		},
+		{
+			"quoted-printable em UTF-8 com quebra suave",
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:Concei=C3=A7=C3=A3o;Jo=C3=A3o;;;\r\nFN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:Jo=C3=A3o Concei=C3=A7=C3=A3o\r\nNOTE;ENCODING=QUOTED-PRINTABLE:Prefere liga=C3=A7=C3=B5es =\r\n=C3=A0 tarde=0D=0Adepois das 14h\r\nEND:VCARD\r\n",
+			[]vcard.Card{{
+				Version:       "3.0",
+				FormattedName: "João Conceição",
+				Name:          []string{"Conceição", "João", "", "", ""},
+				Note:          "Prefere ligações à tarde\r\ndepois das 14h",
+				Emails:        []string{},
+				Phones:        []string{},
+			}},
+		},
+		{
+			"charset ISO-8859-1, com e sem quoted-printable",
+			"BEGIN:VCARD\nVERSION:3.0\nFN;CHARSET=ISO-8859-1:Jo\xe3o\nORG;CHARSET=ISO-8859-1;QUOTED-PRINTABLE:Constru=E7=F5es Ltda.\nEND:VCARD\n",
+			[]vcard.Card{{
+				Version:       "3.0",
+				FormattedName: "João",
+				Organization:  "Construções Ltda.",
+				Emails:        []string{},
+				Phones:        []string{},
+			}},
+		},
+		{
+			"vários contatos com BOM",
+			"\ufeffBEGIN:VCARD\nVERSION:3.0\nFN:Ana\nEND:VCARD\n\nBEGIN:VCARD\nVERSION:4.0\nFN:Beto\nPHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQSkZJRg==\nEND:VCARD\n",
+			[]vcard.Card{
+				{Version: "3.0", FormattedName: "Ana", Emails: []string{}, Phones: []string{}},
+				{Version: "4.0", FormattedName: "Beto", Emails: []string{}, Phones: []string{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := vcard.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(cards, tt.want) {
+				t.Fatalf("contatos = %#v\nesperado %#v", cards, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"versão 2.1", "BEGIN:VCARD\nVERSION:2.1\nFN:Ana\nEND:VCARD\n", vcard.ErrUnsupportedVersion},
+		{"sem versão", "BEGIN:VCARD\nFN:Ana\nEND:VCARD\n", vcard.ErrUnsupportedVersion},
+		{"sem END", "BEGIN:VCARD\nVERSION:3.0\nFN:Ana\n", vcard.ErrMalformed},
+		{"END sem BEGIN", "VERSION:3.0\nEND:VCARD\n", vcard.ErrMalformed},
+		{"BEGIN dentro de outro contato", "BEGIN:VCARD\nVERSION:3.0\nBEGIN:VCARD\n", vcard.ErrMalformed},
+		{"linha sem ':'", "BEGIN:VCARD\nVERSION:3.0\nFN Ana\nEND:VCARD\n", vcard.ErrMalformed},
+		{"charset desconhecido", "BEGIN:VCARD\nVERSION:3.0\nFN;CHARSET=X-DESCONHECIDO:Ana\nEND:VCARD\n", vcard.ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vcard.Parse(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		card vcard.Card
+		want string
+	}{
+		{"FN preenchido", vcard.Card{FormattedName: "Ana Lima", Name: []string{"Lima", "Ana"}}, "Ana Lima"},
+		{"montado a partir de N", vcard.Card{Name: []string{"Silva", "João", "Pedro", "Dr.", "Jr."}}, "Dr. João Pedro Silva Jr."},
+		{"N incompleto", vcard.Card{Name: []string{"Silva", "João"}}, "João Silva"},
+		{"sem nome", vcard.Card{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.card.DisplayName(); got != tt.want {
+				t.Fatalf("DisplayName = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package vcard
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLength é o tamanho máximo de uma linha em octetos antes da dobra (RFC 2426, seção 2.6)
+const maxLineLength = 75
+
+// textEscaper escapa os caracteres especiais dos valores de texto
+var textEscaper = strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+// Writer grava contatos no formato vCard 3.0, o mais aceito pelas agendas de celular
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter cria um Writer que grava em w; chame Flush ao terminar
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write grava um contato. Os campos vazios são omitidos, exceto FN e N, obrigatórios no 3.0.
+// Cada endereço é gravado como a rua do ADR, já que o CRM guarda o endereço em uma única linha.
+func (w *Writer) Write(card *Card) error {
+	name := make([]string, 5)
+	copy(name, card.Name)
+	for i := range name {
+		name[i] = textEscaper.Replace(name[i])
+	}
+
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:" + textEscaper.Replace(card.DisplayName()),
+		"N:" + strings.Join(name, ";"),
+	}
+	if card.Organization != "" {
+		lines = append(lines, "ORG:"+textEscaper.Replace(card.Organization))
+	}
+	if card.Title != "" {
+		lines = append(lines, "TITLE:"+textEscaper.Replace(card.Title))
+	}
+	for i, email := range card.Emails {
+		lines = append(lines, "EMAIL;TYPE=INTERNET"+prefParam(i)+":"+textEscaper.Replace(email))
+	}
+	for i, phone := range card.Phones {
+		lines = append(lines, "TEL;TYPE=VOICE"+prefParam(i)+":"+textEscaper.Replace(phone))
+	}
+	for _, address := range card.Addresses {
+		lines = append(lines, "ADR:;;"+textEscaper.Replace(address)+";;;;")
+	}
+	if card.Note != "" {
+		lines = append(lines, "NOTE:"+textEscaper.Replace(card.Note))
+	}
+	lines = append(lines, "END:VCARD")
+
+	for _, line := range lines {
+		if _, err := w.w.WriteString(fold(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush grava os dados pendentes no io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// prefParam marca o primeiro e-mail ou telefone como o preferido
+func prefParam(index int) string {
+	if index == 0 {
+		return ",PREF"
+	}
+	return ""
+}
+
+// fold quebra a linha em linhas de até 75 octetos terminadas em CRLF, sem dividir caracteres UTF-8;
+// as continuações começam com um espaço
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1 // o espaço da continuação conta no tamanho
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package vcard_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jpcode092/crm-freela/pkg/vcard"
+)
+
+// writeCards grava os contatos com o Writer e retorna o arquivo
+func writeCards(t *testing.T, cards ...vcard.Card) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := vcard.NewWriter(&buf)
+	for i := range cards {
+		if err := w.Write(&cards[i]); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriterOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		card vcard.Card
+		want string
+	}{
+		{
+			"apenas o nome",
+			vcard.Card{FormattedName: "Ana Lima"},
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Ana Lima\r\nN:;;;;\r\nEND:VCARD\r\n",
+		},
+		{
+			"vários e-mails e telefones, com o primeiro preferido",
+			vcard.Card{
+				FormattedName: "Ana Lima",
+				Emails:        []string{"ana@acme.com.br", "ana@pessoal.com"},
+				Phones:        []string{"11 98765-4321", "11 3333-4444"},
+			},
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Ana Lima\r\nN:;;;;\r\n" +
+				"EMAIL;TYPE=INTERNET,PREF:ana@acme.com.br\r\nEMAIL;TYPE=INTERNET:ana@pessoal.com\r\n" +
+				"TEL;TYPE=VOICE,PREF:11 98765-4321\r\nTEL;TYPE=VOICE:11 3333-4444\r\nEND:VCARD\r\n",
+		},
+		{
+			"escapes de texto",
+			vcard.Card{
+				Name:         []string{"Silva", "João"},
+				Organization: "Acme; Filial Sul",
+				Addresses:    []string{"Rua das Flores, 100"},
+				Note:         "Linha 1\r\nLinha 2\ncom \\ barra",
+			},
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:João Silva\r\nN:Silva;João;;;\r\nORG:Acme\\; Filial Sul\r\n" +
+				"ADR:;;Rua das Flores\\, 100;;;;\r\nNOTE:Linha 1\\nLinha 2\\ncom \\\\ barra\r\nEND:VCARD\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeCards(t, tt.card); got != tt.want {
+				t.Fatalf("saída = %q\nesperado %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterFoldsLongLines(t *testing.T) {
+	tests := []struct {
+		name string
+		note string
+	}{
+		{"ASCII", strings.Repeat("0123456789", 20)},
+		{"caracteres de vários bytes", strings.Repeat("ação é ótima ", 20)},
+		{"exatamente no limite", strings.Repeat("a", 75-len("NOTE:"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := writeCards(t, vcard.Card{FormattedName: "Ana", Note: tt.note})
+
+			if !strings.HasSuffix(output, "\r\n") {
+				t.Fatalf("saída sem CRLF final: %q", output)
+			}
+			for i, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("linha %d com %d octetos: %q", i+1, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("linha %d divide um caractere UTF-8: %q", i+1, line)
+				}
+			}
+
+			cards, err := vcard.Parse(strings.NewReader(output))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(cards) != 1 || cards[0].Note != strings.TrimSpace(tt.note) {
+				t.Fatalf("observação lida = %q, esperado %q", cards[0].Note, tt.note)
+			}
+		})
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	cards := []vcard.Card{
+		{
+			Version:       "3.0",
+			FormattedName: "Dr. João Conceição",
+			Name:          []string{"Conceição", "João", "", "Dr.", ""},
+			Organization:  "Construções Acme; Filial Sul",
+			Title:         "Diretor, Compras",
+			Emails:        []string{"joao@acme.com.br", "joao@pessoal.com"},
+			Phones:        []string{"+55 11 98765-4321", "11 3333-4444"},
+			Addresses:     []string{"Rua das Flores, 100 - São Paulo/SP"},
+			Note:          "Prefere contato à tarde.\nPaga no dia 10; boleto \\ PIX. " + strings.Repeat("Observação longa ", 8),
+		},
+		{
+			Version:       "3.0",
+			FormattedName: "Ana Lima",
+			Name:          []string{"", "", "", "", ""},
+			Emails:        []string{},
+			Phones:        []string{},
+		},
+	}
+
+	parsed, err := vcard.Parse(strings.NewReader(writeCards(t, cards...)))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cards[0].Note = strings.TrimSpace(cards[0].Note)
+	if !reflect.DeepEqual(parsed, cards) {
+		t.Fatalf("contatos lidos = %#v\nesperado %#v", parsed, cards)
+	}
+}
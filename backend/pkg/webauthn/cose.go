@@ -0,0 +1,124 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Algoritmos COSE (RFC 9053) aceitos para as credenciais
+const (
+	AlgES256 = -7   // ECDSA P-256 com SHA-256
+	AlgEdDSA = -8   // Ed25519
+	AlgRS256 = -257 // RSASSA-PKCS1-v1_5 com SHA-256
+)
+
+// SupportedAlgorithms lista os algoritmos oferecidos ao autenticador, em ordem de preferência
+var SupportedAlgorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+// Rótulos e valores usados nas chaves COSE (RFC 9052)
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+	coseCurve     = -1 // crv (EC2/OKP) ou n (RSA)
+	coseX         = -2 // x (EC2/OKP) ou e (RSA)
+	coseY         = -3
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+	coseKtyRSA = 3
+
+	coseCrvP256    = 1
+	coseCrvEd25519 = 6
+)
+
+// ErrUnsupportedKey indica uma chave COSE com tipo, curva ou algoritmo não suportado
+var ErrUnsupportedKey = errors.New("webauthn: chave pública não suportada")
+
+// PublicKey representa a chave pública de uma credencial, decodificada do formato COSE
+type PublicKey struct {
+	Algorithm int
+	key       crypto.PublicKey
+}
+
+// ParsePublicKey decodifica uma chave pública COSE, como gravada junto à credencial
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	key, rest, err := parseCOSEKey(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: bytes extras após a chave", errCBOR)
+	}
+	return key, nil
+}
+
+// parseCOSEKey decodifica a chave COSE do início de data e retorna o restante dos bytes
+func parseCOSEKey(data []byte) (*PublicKey, []byte, error) {
+	value, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: chave COSE não é um map", ErrUnsupportedKey)
+	}
+
+	kty, _ := entries[int64(coseKeyType)].(int64)
+	alg, _ := entries[int64(coseAlgorithm)].(int64)
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := entries[int64(coseCurve)].(int64)
+		x, _ := entries[int64(coseX)].([]byte)
+		y, _ := entries[int64(coseY)].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, nil, fmt.Errorf("%w: chave EC2 inválida", ErrUnsupportedKey)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, nil, fmt.Errorf("%w: ponto fora da curva", ErrUnsupportedKey)
+		}
+		return &PublicKey{Algorithm: AlgES256, key: key}, rest, nil
+
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := entries[int64(coseCurve)].(int64)
+		x, _ := entries[int64(coseX)].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("%w: chave OKP inválida", ErrUnsupportedKey)
+		}
+		return &PublicKey{Algorithm: AlgEdDSA, key: ed25519.PublicKey(x)}, rest, nil
+
+	case kty == coseKtyRSA && alg == AlgRS256:
+		n, _ := entries[int64(coseCurve)].([]byte)
+		e, _ := entries[int64(coseX)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, nil, fmt.Errorf("%w: chave RSA inválida", ErrUnsupportedKey)
+		}
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		return &PublicKey{Algorithm: AlgRS256, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}}, rest, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: kty %d, alg %d", ErrUnsupportedKey, kty, alg)
+}
+
+// Verify confere a assinatura de data feita pela chave privada correspondente
+func (k *PublicKey) Verify(data, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+// Package virtual implementa um autenticador WebAuthn em software, com chaves ES256 em memória.
+//
+// Ele produz as mesmas respostas que o navegador envia após navigator.credentials.create() e get(),
+// permitindo exercitar as cerimônias do pacote webauthn e os serviços que o usam sem navegador.
+// Não deve ser usado fora de testes: as chaves privadas não são protegidas.
+package virtual
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/jpcode092/crm-freela/pkg/webauthn"
+)
+
+// ErrNoCredential indica que o autenticador não tem credencial aceita pelas opções recebidas
+var ErrNoCredential = errors.New("virtual: nenhuma credencial disponível")
+
+// Authenticator simula um autenticador de plataforma com passkeys residentes
+type Authenticator struct {
+	// Origin é a origem informada no clientDataJSON, como faria o navegador
+	Origin string
+	// UserVerified define se o autenticador declara ter verificado o usuário (biometria/PIN)
+	UserVerified bool
+
+	mu          sync.Mutex
+	credentials []*credential
+}
+
+// credential é uma passkey guardada no autenticador
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// New cria um autenticador que responde como o navegador aberto em origin
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, UserVerified: true}
+}
+
+// Create gera uma nova credencial, como navigator.credentials.create()
+func (a *Authenticator) Create(options *webauthn.CreationOptions) (*webauthn.AttestationResponse, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	cred := &credential{
+		id:         id,
+		rpID:       options.RP.ID,
+		userHandle: options.User.ID,
+		key:        key,
+	}
+
+	clientDataJSON, err := a.clientData("webauthn.create", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	// attestedCredentialData: aaguid (zeros) | tamanho do id | id | chave COSE
+	attested := make([]byte, 16, 16+2+len(id))
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(id)))
+	attested = append(attested, id...)
+	attested = append(attested, coseKey(&key.PublicKey)...)
+
+	authData := a.authenticatorData(cred, 0x40)
+	authData = append(authData, attested...)
+
+	var attestation encoder
+	attestation.mapHeader(3)
+	attestation.text("fmt")
+	attestation.text("none")
+	attestation.text("attStmt")
+	attestation.mapHeader(0)
+	attestation.text("authData")
+	attestation.bytes(authData)
+
+	a.mu.Lock()
+	a.credentials = append(a.credentials, cred)
+	a.mu.Unlock()
+
+	response := &webauthn.AttestationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(id),
+		RawID: id,
+		Type:  "public-key",
+	}
+	response.Response.ClientDataJSON = clientDataJSON
+	response.Response.AttestationObject = attestation.buf
+	response.Response.Transports = []string{"internal"}
+	return response, nil
+}
+
+// Get assina o challenge com uma credencial aceita pelas opções, como navigator.credentials.get().
+// Com AllowCredentials vazio, usa a primeira passkey registrada para o RP.
+func (a *Authenticator) Get(options *webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	cred := a.find(options)
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	clientDataJSON, err := a.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	cred.signCount++
+	authData := a.authenticatorData(cred, 0)
+	a.mu.Unlock()
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	response := &webauthn.AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(cred.id),
+		RawID: cred.id,
+		Type:  "public-key",
+	}
+	response.Response.ClientDataJSON = clientDataJSON
+	response.Response.AuthenticatorData = authData
+	response.Response.Signature = signature
+	response.Response.UserHandle = cred.userHandle
+	return response, nil
+}
+
+// find retorna a credencial do RP aceita por options, ou nil
+func (a *Authenticator) find(options *webauthn.RequestOptions) *credential {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, cred := range a.credentials {
+		if cred.rpID != options.RPID {
+			continue
+		}
+		if len(options.AllowCredentials) == 0 {
+			return cred
+		}
+		for _, allowed := range options.AllowCredentials {
+			if string(allowed.ID) == string(cred.id) {
+				return cred
+			}
+		}
+	}
+	return nil
+}
+
+// clientData monta o clientDataJSON que o navegador enviaria
+func (a *Authenticator) clientData(ceremony string, challenge []byte) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+}
+
+// authenticatorData monta rpIdHash | flags | signCount com presença (e verificação) do usuário
+func (a *Authenticator) authenticatorData(cred *credential, extraFlags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(cred.rpID))
+	flags := byte(0x01) | extraFlags
+	if a.UserVerified {
+		flags |= 0x04
+	}
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, cred.signCount)
+}
+
+// coseKey codifica a chave pública ES256 no formato COSE
+func coseKey(key *ecdsa.PublicKey) []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+
+	var e encoder
+	e.mapHeader(5)
+	e.int(1) // kty: EC2
+	e.int(2)
+	e.int(3) // alg: ES256
+	e.int(webauthn.AlgES256)
+	e.int(-1) // crv: P-256
+	e.int(1)
+	e.int(-2)
+	e.bytes(x)
+	e.int(-3)
+	e.bytes(y)
+	return e.buf
+}
+
+// encoder gera o subconjunto de CBOR usado pelo autenticador
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major<<5|byte(n))
+	case n <= 0xff:
+		e.buf = append(e.buf, major<<5|24, byte(n))
+	case n <= 0xffff:
+		e.buf = binary.BigEndian.AppendUint16(append(e.buf, major<<5|25), uint16(n))
+	case n <= 0xffffffff:
+		e.buf = binary.BigEndian.AppendUint32(append(e.buf, major<<5|26), uint32(n))
+	default:
+		e.buf = binary.BigEndian.AppendUint64(append(e.buf, major<<5|27), n)
+	}
+}
+
+func (e *encoder) int(v int) {
+	if v < 0 {
+		e.head(1, uint64(-1-v))
+		return
+	}
+	e.head(0, uint64(v))
+}
+
+func (e *encoder) bytes(v []byte) {
+	e.head(2, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) text(v string) {
+	e.head(3, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) mapHeader(n int) {
+	e.head(5, uint64(n))
+}
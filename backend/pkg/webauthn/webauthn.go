@@ -0,0 +1,510 @@
+// Package webauthn implementa as cerimônias de registro e autenticação do WebAuthn (nível 2)
+// para passkeys: geração das opções enviadas ao navegador e validação das respostas do autenticador,
+// incluindo clientDataJSON, authenticatorData, chaves COSE e o contador de assinaturas.
+//
+// A atestação não é verificada (conveyance "none"): a confiança está na chave registrada
+// pelo usuário autenticado, não no fabricante do autenticador. O pacote não depende de HTTP
+// nem de navegador; o subpacote virtual oferece um autenticador em software para testes.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Erros retornados na validação das cerimônias
+var (
+	ErrInvalidResponse   = errors.New("webauthn: resposta do autenticador inválida")
+	ErrChallengeMismatch = errors.New("webauthn: challenge não confere")
+	ErrOriginMismatch    = errors.New("webauthn: origem não permitida")
+	ErrRPIDMismatch      = errors.New("webauthn: credencial emitida para outro RP ID")
+	ErrUserNotPresent    = errors.New("webauthn: presença do usuário não confirmada")
+	ErrUserNotVerified   = errors.New("webauthn: verificação do usuário exigida")
+	ErrInvalidSignature  = errors.New("webauthn: assinatura inválida")
+	ErrSignCount         = errors.New("webauthn: contador de assinaturas regrediu; a credencial pode ter sido clonada")
+)
+
+// Tipos do clientDataJSON de cada cerimônia
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+// Flags do authenticatorData
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackupState    = 0x10
+	flagAttestedData   = 0x40
+	flagExtensionData  = 0x80
+)
+
+const (
+	challengeSize      = 32
+	maxCredentialIDLen = 1023
+	credentialType     = "public-key"
+)
+
+// Config representa o relying party: o domínio (RP ID) ao qual as credenciais ficam vinculadas
+// e as origens do frontend autorizadas a usá-las
+type Config struct {
+	RPID    string   // domínio registrável, ex.: "crmfreela.com.br" ou "localhost"
+	RPName  string   // nome exibido pelo navegador ao criar a passkey
+	Origins []string // origens aceitas no clientDataJSON, ex.: "https://app.crmfreela.com.br"
+	Timeout time.Duration
+	// RequireUserVerification exige biometria/PIN em toda cerimônia; com false, basta a presença
+	RequireUserVerification bool
+}
+
+// RelyingParty gera as opções das cerimônias e valida as respostas dos autenticadores
+type RelyingParty struct {
+	config   Config
+	rpIDHash [32]byte
+}
+
+// New cria um RelyingParty a partir da configuração
+func New(config Config) (*RelyingParty, error) {
+	if config.RPID == "" {
+		return nil, errors.New("webauthn: RP ID não configurado")
+	}
+	if len(config.Origins) == 0 {
+		return nil, errors.New("webauthn: nenhuma origem configurada")
+	}
+	if config.RPName == "" {
+		config.RPName = config.RPID
+	}
+	return &RelyingParty{config: config, rpIDHash: sha256.Sum256([]byte(config.RPID))}, nil
+}
+
+// URLEncodedBase64 representa bytes serializados em JSON como base64url sem padding,
+// o formato usado pelo navegador (PublicKeyCredential.toJSON) nos campos binários
+type URLEncodedBase64 []byte
+
+// MarshalJSON serializa os bytes como base64url sem padding
+func (b URLEncodedBase64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON aceita base64url com ou sem padding
+func (b *URLEncodedBase64) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return fmt.Errorf("%w: base64url inválido", ErrInvalidResponse)
+	}
+	*b = decoded
+	return nil
+}
+
+// UserEntity identifica o usuário para o autenticador. ID é o user handle: opaco e sem dados pessoais.
+type UserEntity struct {
+	ID          URLEncodedBase64 `json:"id"`
+	Name        string           `json:"name"`
+	DisplayName string           `json:"displayName"`
+}
+
+// RelyingPartyEntity identifica o relying party para o autenticador
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CredentialParameter indica um algoritmo aceito para a nova credencial
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CredentialDescriptor referencia uma credencial já registrada
+type CredentialDescriptor struct {
+	Type       string           `json:"type"`
+	ID         URLEncodedBase64 `json:"id"`
+	Transports []string         `json:"transports,omitempty"`
+}
+
+// NewCredentialDescriptor cria o descritor de uma credencial registrada
+func NewCredentialDescriptor(id []byte, transports []string) CredentialDescriptor {
+	return CredentialDescriptor{Type: credentialType, ID: id, Transports: transports}
+}
+
+// AuthenticatorSelection define os requisitos do autenticador na criação da credencial
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"` // equivalente de residentKey no WebAuthn nível 1
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions são as opções de navigator.credentials.create() (campo publicKey)
+type CreationOptions struct {
+	Challenge              URLEncodedBase64       `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"` // em milissegundos
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions são as opções de navigator.credentials.get() (campo publicKey).
+// Com AllowCredentials vazio, o autenticador oferece as passkeys (credenciais residentes) do RP.
+type RequestOptions struct {
+	Challenge        URLEncodedBase64       `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout,omitempty"` // em milissegundos
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// BeginRegistration gera as opções de criação de uma passkey (credencial residente) para o usuário.
+// exclude lista as credenciais já registradas, para o autenticador não criar uma duplicada.
+func (rp *RelyingParty) BeginRegistration(user UserEntity, exclude []CredentialDescriptor) (*CreationOptions, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: credentialType, Alg: alg})
+	}
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+
+	return &CreationOptions{
+		Challenge:          challenge,
+		RP:                 RelyingPartyEntity{ID: rp.config.RPID, Name: rp.config.RPName},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            rp.config.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   rp.userVerification(),
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// BeginLogin gera as opções de autenticação. allow restringe as credenciais aceitas;
+// vazio permite qualquer passkey do RP, identificando o usuário pelo user handle.
+func (rp *RelyingParty) BeginLogin(allow []CredentialDescriptor) (*RequestOptions, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.config.RPID,
+		Timeout:          rp.config.Timeout.Milliseconds(),
+		AllowCredentials: allow,
+		UserVerification: rp.userVerification(),
+	}, nil
+}
+
+// AttestationResponse é a credencial devolvida por navigator.credentials.create(), serializada com toJSON()
+type AttestationResponse struct {
+	ID       string           `json:"id"`
+	RawID    URLEncodedBase64 `json:"rawId"`
+	Type     string           `json:"type"`
+	Response struct {
+		ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+		AttestationObject URLEncodedBase64 `json:"attestationObject"`
+		Transports        []string         `json:"transports,omitempty"`
+	} `json:"response"`
+}
+
+// Challenge retorna o challenge assinado pelo autenticador, usado para localizar a cerimônia em andamento
+func (r *AttestationResponse) Challenge() ([]byte, error) {
+	data, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return data.Challenge, nil
+}
+
+// AssertionResponse é a credencial devolvida por navigator.credentials.get(), serializada com toJSON()
+type AssertionResponse struct {
+	ID       string           `json:"id"`
+	RawID    URLEncodedBase64 `json:"rawId"`
+	Type     string           `json:"type"`
+	Response struct {
+		ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+		AuthenticatorData URLEncodedBase64 `json:"authenticatorData"`
+		Signature         URLEncodedBase64 `json:"signature"`
+		UserHandle        URLEncodedBase64 `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// Challenge retorna o challenge assinado pelo autenticador, usado para localizar a cerimônia em andamento
+func (r *AssertionResponse) Challenge() ([]byte, error) {
+	data, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return data.Challenge, nil
+}
+
+// Credential é a credencial validada no registro, pronta para ser gravada
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // chave pública no formato COSE, como enviada pelo autenticador
+	Algorithm      int
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	UserVerified   bool
+	BackupEligible bool
+	BackupState    bool
+}
+
+// FinishRegistration valida a resposta de navigator.credentials.create() contra o challenge emitido
+func (rp *RelyingParty) FinishRegistration(challenge []byte, response *AttestationResponse) (*Credential, error) {
+	if response.Type != credentialType {
+		return nil, fmt.Errorf("%w: tipo %q", ErrInvalidResponse, response.Type)
+	}
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	value, _, err := decodeCBOR(response.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	attestation, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: attestationObject não é um map", ErrInvalidResponse)
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: authData ausente", ErrInvalidResponse)
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if authData.credentialID == nil {
+		return nil, fmt.Errorf("%w: credencial não incluída no authData", ErrInvalidResponse)
+	}
+	if len(response.RawID) > 0 && !bytes.Equal(response.RawID, authData.credentialID) {
+		return nil, fmt.Errorf("%w: rawId diverge do authData", ErrInvalidResponse)
+	}
+
+	return &Credential{
+		ID:             authData.credentialID,
+		PublicKey:      authData.rawPublicKey,
+		Algorithm:      authData.publicKey.Algorithm,
+		SignCount:      authData.signCount,
+		AAGUID:         authData.aaguid,
+		Transports:     response.Response.Transports,
+		UserVerified:   authData.flags&flagUserVerified != 0,
+		BackupEligible: authData.flags&flagBackupEligible != 0,
+		BackupState:    authData.flags&flagBackupState != 0,
+	}, nil
+}
+
+// Assertion é o resultado de uma autenticação validada
+type Assertion struct {
+	CredentialID []byte
+	UserHandle   []byte
+	SignCount    uint32 // novo valor do contador, a ser gravado na credencial
+	UserVerified bool
+	BackupState  bool
+}
+
+// FinishLogin valida a resposta de navigator.credentials.get() contra o challenge emitido,
+// a chave pública COSE registrada e o último contador de assinaturas conhecido
+func (rp *RelyingParty) FinishLogin(challenge []byte, response *AssertionResponse, publicKey []byte, storedSignCount uint32) (*Assertion, error) {
+	if response.Type != credentialType {
+		return nil, fmt.Errorf("%w: tipo %q", ErrInvalidResponse, response.Type)
+	}
+	if err := rp.verifyClientData(response.Response.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// A assinatura cobre authenticatorData || SHA-256(clientDataJSON)
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signed := append(append([]byte{}, response.Response.AuthenticatorData...), clientDataHash[:]...)
+	if !key.Verify(signed, response.Response.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	// Autenticadores sem contador enviam sempre zero; nos demais, o valor precisa crescer
+	if (authData.signCount != 0 || storedSignCount != 0) && authData.signCount <= storedSignCount {
+		return nil, ErrSignCount
+	}
+
+	return &Assertion{
+		CredentialID: response.RawID,
+		UserHandle:   response.Response.UserHandle,
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags&flagUserVerified != 0,
+		BackupState:  authData.flags&flagBackupState != 0,
+	}, nil
+}
+
+// userVerification retorna o requisito de verificação do usuário enviado nas opções
+func (rp *RelyingParty) userVerification() string {
+	if rp.config.RequireUserVerification {
+		return "required"
+	}
+	return "preferred"
+}
+
+// clientData representa os campos usados do clientDataJSON
+type clientData struct {
+	Type        string           `json:"type"`
+	Challenge   URLEncodedBase64 `json:"challenge"`
+	Origin      string           `json:"origin"`
+	CrossOrigin bool             `json:"crossOrigin"`
+}
+
+func parseClientData(raw []byte) (*clientData, error) {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("%w: clientDataJSON inválido", ErrInvalidResponse)
+	}
+	return &data, nil
+}
+
+// verifyClientData confere o tipo da cerimônia, o challenge e a origem do clientDataJSON
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	data, err := parseClientData(raw)
+	if err != nil {
+		return err
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: cerimônia %q", ErrInvalidResponse, data.Type)
+	}
+	if len(challenge) == 0 || subtle.ConstantTimeCompare(data.Challenge, challenge) != 1 {
+		return ErrChallengeMismatch
+	}
+	if data.CrossOrigin {
+		return ErrOriginMismatch
+	}
+	for _, origin := range rp.config.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return ErrOriginMismatch
+}
+
+// verifyAuthenticatorData confere o RP ID e as flags de presença e verificação do usuário
+func (rp *RelyingParty) verifyAuthenticatorData(authData *authenticatorData) error {
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rp.rpIDHash[:]) != 1 {
+		return ErrRPIDMismatch
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	if rp.config.RequireUserVerification && authData.flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+	return nil
+}
+
+// authenticatorData representa a estrutura binária assinada pelo autenticador
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	rawPublicKey []byte
+	publicKey    *PublicKey
+}
+
+// parseAuthenticatorData decodifica rpIdHash (32) | flags (1) | signCount (4) | [attestedCredentialData] | [extensions]
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: authenticatorData curto", ErrInvalidResponse)
+	}
+
+	authData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if authData.flags&flagAttestedData != 0 {
+		// aaguid (16) | credentialIdLength (2) | credentialId | credentialPublicKey (COSE)
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attestedCredentialData curto", ErrInvalidResponse)
+		}
+		authData.aaguid = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > maxCredentialIDLen || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: credentialId inválido", ErrInvalidResponse)
+		}
+		authData.credentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		key, after, err := parseCOSEKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		authData.publicKey = key
+		authData.rawPublicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+
+	if authData.flags&flagExtensionData != 0 {
+		var err error
+		if _, rest, err = decodeCBOR(rest); err != nil {
+			return nil, fmt.Errorf("%w: extensões inválidas", ErrInvalidResponse)
+		}
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: bytes extras no authenticatorData", ErrInvalidResponse)
+	}
+	return authData, nil
+}
+
+// newChallenge gera um challenge aleatório de 32 bytes
+func newChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
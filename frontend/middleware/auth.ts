@@ -3,8 +3,10 @@ import { navigateTo } from '#app'
 
 export default defineNuxtRouteMiddleware(async (to) => {
   const authStore = useAuthStore()
-  const publicPages = ['/auth/login', '/auth/register', '/auth/forgot-password', '/auth/reset-password', '/auth/forgot-password-sent']
-  const authRequired = !publicPages.includes(to.path)
+  const publicPages = ['/auth/login', '/auth/register', '/auth/forgot-password', '/auth/reset-password', '/auth/forgot-password-sent', '/auth/verify-email', '/auth/verify-email-sent', '/auth/magic-link']
+  // Páginas acessíveis com ou sem login (ex.: link de confirmação aberto no mesmo navegador)
+  const sharedPages = ['/auth/confirm-email-change', '/data-export']
+  const authRequired = !publicPages.includes(to.path) && !sharedPages.includes(to.path)
 
   // Verifica se o token está expirado e tenta renovar se necessário
   if (authStore.isAuthenticated && authStore.isTokenExpired) {
@@ -29,7 +31,7 @@ export default defineNuxtRouteMiddleware(async (to) => {
   }
 
   // Redireciona usuário autenticado para dashboard se tentar acessar páginas públicas
-  if (!authRequired && authStore.isAuthenticated) {
+  if (publicPages.includes(to.path) && authStore.isAuthenticated) {
     return navigateTo('/dashboard')
   }
 })
@@ -2,11 +2,22 @@ import { defineStore } from 'pinia'
 import { jwtDecode } from 'jwt-decode'
 import { useRuntimeConfig } from '#app'
 
+interface UserPreferences {
+  language: string
+  timezone: string
+  email_notifications: boolean
+}
+
 interface User {
   id: number
   name: string
   email: string
   plan: string
+  pending_email?: string | null
+  preferences?: UserPreferences
+  password_login_disabled?: boolean
+  mfa_enabled?: boolean
+  deletion_scheduled_at?: string | null
 }
 
 interface JwtPayload {
@@ -81,8 +92,8 @@ export const useAuthStore = defineStore('auth', {
         }
         
         // Ajustado para corresponder à estrutura da resposta do backend
-        this.accessToken = data.token
-        this.refreshToken = data.token // Usando o mesmo token como refresh por enquanto
+        this.accessToken = data.tokens.access_token
+        this.refreshToken = data.tokens.refresh_token
         this.isAuthenticated = true
         
         // Armazena tokens no localStorage com segurança
@@ -121,8 +132,8 @@ export const useAuthStore = defineStore('auth', {
           throw new Error(data.error || 'Falha no registro')
         }
         
-        // Após o registro bem-sucedido, faça login automaticamente
-        return await this.login(email, password)
+        // A conta só é ativada após a confirmação do e-mail
+        return true
       } catch (error: any) {
         this.error = error.message || 'Erro ao registrar'
         return false
@@ -131,6 +142,420 @@ export const useAuthStore = defineStore('auth', {
       }
     },
     
+    async verifyEmail(token: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/verify-email`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ token })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao confirmar e-mail')
+      }
+      
+      return true
+    },
+    
+    async resendVerification(email: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/verify-email/resend`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ email })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao reenviar confirmação')
+      }
+      
+      return true
+    },
+    
+    async requestMagicLink(email: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/magic-link`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ email })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao solicitar link de acesso')
+      }
+      
+      return true
+    },
+    
+    async loginWithMagicLink(token: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/magic-link/consume`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ token }),
+        credentials: 'include'
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao entrar com o link')
+      }
+      
+      if (data.mfa_required) {
+        throw new Error('Sua conta exige o código de verificação. Entre com e-mail e senha')
+      }
+      
+      this.accessToken = data.tokens.access_token
+      this.refreshToken = data.tokens.refresh_token
+      this.isAuthenticated = true
+      this.securelyStoreTokens(this.accessToken, this.refreshToken)
+      
+      await this.fetchUserProfile()
+      
+      return true
+    },
+    
+    async loginWithPasskey() {
+      const config = useRuntimeConfig()
+      const begin = await fetch(`${config.public.apiBase}/auth/passkey/begin`, {
+        method: 'POST'
+      })
+      
+      const options = await begin.json()
+      
+      if (!begin.ok) {
+        throw new Error(options.error || 'Falha ao iniciar login com passkey')
+      }
+      
+      // O navegador exibe as passkeys salvas para este site
+      const credential = await navigator.credentials.get({
+        publicKey: PublicKeyCredential.parseRequestOptionsFromJSON(options.publicKey)
+      }) as PublicKeyCredential | null
+      
+      if (!credential) {
+        throw new Error('Nenhuma passkey selecionada')
+      }
+      
+      const response = await fetch(`${config.public.apiBase}/auth/passkey/finish`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ credential: credential.toJSON() }),
+        credentials: 'include'
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao entrar com passkey')
+      }
+      
+      if (data.mfa_required) {
+        throw new Error('Sua conta exige o código de verificação. Entre com e-mail e senha')
+      }
+      
+      this.accessToken = data.tokens.access_token
+      this.refreshToken = data.tokens.refresh_token
+      this.isAuthenticated = true
+      this.securelyStoreTokens(this.accessToken, this.refreshToken)
+      
+      await this.fetchUserProfile()
+      
+      return true
+    },
+    
+    async registerPasskey(name: string) {
+      const config = useRuntimeConfig()
+      const begin = await fetch(`${config.public.apiBase}/user/passkeys/register/begin`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const options = await begin.json()
+      
+      if (!begin.ok) {
+        throw new Error(options.error || 'Falha ao iniciar cadastro da passkey')
+      }
+      
+      const credential = await navigator.credentials.create({
+        publicKey: PublicKeyCredential.parseCreationOptionsFromJSON(options.publicKey)
+      }) as PublicKeyCredential | null
+      
+      if (!credential) {
+        throw new Error('Cadastro da passkey cancelado')
+      }
+      
+      const response = await fetch(`${config.public.apiBase}/user/passkeys/register/finish`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${this.accessToken}`
+        },
+        body: JSON.stringify({ name, credential: credential.toJSON() })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao cadastrar passkey')
+      }
+      
+      return data.passkey
+    },
+    
+    async requestDataExport() {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/export`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao solicitar exportação dos dados')
+      }
+      
+      return true
+    },
+    
+    async deleteAccount(password: string, mfaCode?: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/account`, {
+        method: 'DELETE',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${this.accessToken}`
+        },
+        body: JSON.stringify({ password, mfa_code: mfaCode })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao excluir conta')
+      }
+      
+      if (this.user) {
+        this.user.deletion_scheduled_at = data.deletion_scheduled_at
+      }
+      return data.deletion_scheduled_at
+    },
+    
+    async cancelAccountDeletion() {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/account/cancel-deletion`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao cancelar exclusão da conta')
+      }
+      
+      if (this.user) {
+        this.user.deletion_scheduled_at = null
+      }
+      return true
+    },
+    
+    async listSecurityEvents(page = 1) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/security-events?page=${page}`, {
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao listar eventos de segurança')
+      }
+      
+      return data.data
+    },
+    
+    async listPasskeys() {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/passkeys`, {
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao listar passkeys')
+      }
+      
+      return data.data
+    },
+    
+    async deletePasskey(id: number) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/passkeys/${id}`, {
+        method: 'DELETE',
+        headers: {
+          'Authorization': `Bearer ${this.accessToken}`
+        }
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao remover passkey')
+      }
+      
+      return true
+    },
+    
+    async forgotPassword(email: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/forgot-password`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ email })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao solicitar recuperação de senha')
+      }
+      
+      return true
+    },
+    
+    async resetPassword(token: string, password: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/reset-password`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ token, password })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao redefinir senha')
+      }
+      
+      return true
+    },
+    
+    async updateProfile(profile: { name?: string, password_login_disabled?: boolean } & Partial<UserPreferences>) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/profile`, {
+        method: 'PUT',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${this.accessToken}`
+        },
+        body: JSON.stringify(profile)
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao atualizar perfil')
+      }
+      
+      this.user = { ...this.user, ...data.user }
+      return true
+    },
+    
+    async changePassword(currentPassword: string, newPassword: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/password`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${this.accessToken}`
+        },
+        body: JSON.stringify({ current_password: currentPassword, new_password: newPassword })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao alterar senha')
+      }
+      
+      return true
+    },
+    
+    async requestEmailChange(email: string, password: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/user/email`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${this.accessToken}`
+        },
+        body: JSON.stringify({ email, password })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao solicitar troca de e-mail')
+      }
+      
+      if (this.user) {
+        this.user.pending_email = email
+      }
+      return true
+    },
+    
+    async confirmEmailChange(token: string) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/auth/email-change/confirm`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json'
+        },
+        body: JSON.stringify({ token })
+      })
+      
+      const data = await response.json()
+      
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao confirmar troca de e-mail')
+      }
+      
+      return true
+    },
+    
     async fetchUserProfile() {
       if (!this.accessToken) return false
       
@@ -156,7 +581,12 @@ export const useAuthStore = defineStore('auth', {
           id: data.id || data.user?.id,
           name: data.name || data.user?.name,
           email: data.email || data.user?.email,
-          plan: data.plan || data.user?.plan || 'free'
+          plan: data.plan || data.user?.plan || 'free',
+          pending_email: data.user?.pending_email,
+          preferences: data.user?.preferences,
+          password_login_disabled: data.user?.password_login_disabled,
+          mfa_enabled: data.user?.mfa_enabled,
+          deletion_scheduled_at: data.user?.deletion_scheduled_at
         }
         
         return true
@@ -1,5 +1,6 @@
 import { defineStore } from 'pinia'
 import { useRuntimeConfig } from '#app'
+import type { ImportField, ImportResult, ClientActivityInput, ClientArchiveResult, ClientMergeResult, ClientTrashEntry, DuplicateSuggestion, ClientContact, ClientContactInput, CustomFieldDefinition, Tag, TimelinePage } from '~/types/client'
 
 interface Client {
   id: number
@@ -11,6 +12,10 @@ interface Client {
   status: string
   created_at: string
   updated_at: string
+  tags?: Tag[]
+  custom_fields?: Record<string, string | number>
+  rank?: number
+  highlights?: Record<string, string>
 }
 
 interface ClientStats {
@@ -26,6 +31,8 @@ interface ClientsState {
   totalClients: number
   activeClients: number
   stats: ClientStats
+  tags: Tag[]
+  customFields: CustomFieldDefinition[]
   loading: boolean
   error: string | null
 }
@@ -42,6 +49,8 @@ export const useClientsStore = defineStore('clients', {
       inactive: 0,
       archived: 0
     },
+    tags: [],
+    customFields: [],
     loading: false,
     error: null
   }),
@@ -57,15 +66,17 @@ export const useClientsStore = defineStore('clients', {
   },
 
   actions: {
-    async fetchClients(page: number = 1, pageSize: number = 10, search?: string, status?: string) {
+    async fetchClients(page: number = 1, pageSize: number = 10, search?: string, status?: string, sort?: string, tagIds: number[] = []) {
       this.loading = true
       this.error = null
       
       try {
         const config = useRuntimeConfig()
         let url = `${config.public.apiBase}/clients?page=${page}&page_size=${pageSize}`
-        if (search) url += `&search=${encodeURIComponent(search)}`
+        if (search) url += `&q=${encodeURIComponent(search)}`
         if (status) url += `&status=${status}`
+        if (sort) url += `&sort=${sort}`
+        if (tagIds.length) url += `&tags=${tagIds.join(',')}`
         
         const response = await fetch(
           url,
@@ -262,6 +273,341 @@ export const useClientsStore = defineStore('clients', {
       }
     },
     
+    async fetchTags() {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/tags`, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar tags')
+      }
+      this.tags = data.data || []
+      return this.tags
+    },
+
+    async createTag(name: string, color?: string): Promise<Tag> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/tags`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body: JSON.stringify({ name, color })
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao criar tag')
+      }
+      this.tags = [...this.tags, data].sort((a, b) => a.name.localeCompare(b.name))
+      return data
+    },
+
+    async fetchCustomFields() {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/custom-fields`, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar campos personalizados')
+      }
+      this.customFields = data.data || []
+      return this.customFields
+    },
+
+    async fetchContacts(clientId: number): Promise<ClientContact[]> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${clientId}/contacts`, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar contatos')
+      }
+      return data.data || []
+    },
+
+    async saveContact(clientId: number, contact: ClientContactInput, contactId?: number): Promise<ClientContact> {
+      const config = useRuntimeConfig()
+      const url = contactId
+        ? `${config.public.apiBase}/clients/${clientId}/contacts/${contactId}`
+        : `${config.public.apiBase}/clients/${clientId}/contacts`
+      const response = await fetch(url, {
+        method: contactId ? 'PUT' : 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body: JSON.stringify(contact)
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao salvar contato')
+      }
+      return data
+    },
+
+    async deleteContact(clientId: number, contactId: number) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${clientId}/contacts/${contactId}`, {
+        method: 'DELETE',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      if (!response.ok) {
+        const data = await response.json()
+        throw new Error(data.error || 'Falha ao remover contato')
+      }
+      return true
+    },
+
+    async fetchTimeline(clientId: number, cursor?: string | null): Promise<TimelinePage> {
+      const config = useRuntimeConfig()
+      let url = `${config.public.apiBase}/clients/${clientId}/timeline`
+      if (cursor) url += `?cursor=${encodeURIComponent(cursor)}`
+      const response = await fetch(url, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar histórico')
+      }
+      return data
+    },
+
+    async saveActivity(clientId: number, activity: ClientActivityInput, activityId?: number) {
+      const config = useRuntimeConfig()
+      const url = activityId
+        ? `${config.public.apiBase}/clients/${clientId}/activities/${activityId}`
+        : `${config.public.apiBase}/clients/${clientId}/activities`
+      const response = await fetch(url, {
+        method: activityId ? 'PUT' : 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body: JSON.stringify(activity)
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao registrar atividade')
+      }
+      return data
+    },
+
+    async deleteActivity(clientId: number, activityId: number) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${clientId}/activities/${activityId}`, {
+        method: 'DELETE',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      if (!response.ok) {
+        const data = await response.json()
+        throw new Error(data.error || 'Falha ao remover atividade')
+      }
+      return true
+    },
+
+    async importClients(file: File, options: { dryRun: boolean, mapping?: Partial<Record<ImportField, string>> }): Promise<ImportResult> {
+      const config = useRuntimeConfig()
+      const body = new FormData()
+      body.append('file', file)
+      body.append('dry_run', String(options.dryRun))
+      if (options.mapping) body.append('mapping', JSON.stringify(options.mapping))
+
+      const response = await fetch(`${config.public.apiBase}/clients/import`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.details || data.error || 'Falha ao importar clientes')
+      }
+      return data
+    },
+
+    // Baixa a exportação com os mesmos filtros da listagem; o download passa pelo fetch por causa do token
+    async exportClients(format: 'vcf' | 'csv', search?: string, status?: string, sort?: string, tagIds: number[] = []) {
+      const config = useRuntimeConfig()
+      let url = `${config.public.apiBase}/clients/export?format=${format}`
+      if (search) url += `&q=${encodeURIComponent(search)}`
+      if (status) url += `&status=${status}`
+      if (sort) url += `&sort=${sort}`
+      if (tagIds.length) url += `&tags=${tagIds.join(',')}`
+
+      const response = await fetch(url, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      if (!response.ok) {
+        const data = await response.json()
+        throw new Error(data.error || 'Falha ao exportar clientes')
+      }
+
+      const disposition = response.headers.get('Content-Disposition') || ''
+      const filename = disposition.match(/filename="([^"]+)"/)?.[1] || `clientes.${format}`
+      const link = document.createElement('a')
+      link.href = URL.createObjectURL(await response.blob())
+      link.download = filename
+      link.click()
+      URL.revokeObjectURL(link.href)
+    },
+
+    async fetchDuplicates(limit: number = 20): Promise<DuplicateSuggestion[]> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/duplicates?limit=${limit}`, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar clientes duplicados')
+      }
+      return data.data || []
+    },
+
+    // Mescla sourceId em targetId; o cliente de origem deixa de existir
+    async mergeClients(targetId: number, sourceId: number): Promise<ClientMergeResult> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${targetId}/merge`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body: JSON.stringify({ source_id: sourceId })
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao mesclar clientes')
+      }
+
+      const merged = this.clients.find((c: Client) => c.id === sourceId)
+      this.clients = this.clients
+        .filter((c: Client) => c.id !== sourceId)
+        .map((c: Client) => (c.id === targetId ? data.client : c))
+      if (merged) {
+        this.stats.total--
+        if (merged.status === 'active') this.stats.active--
+        else if (merged.status === 'inactive') this.stats.inactive--
+        else if (merged.status === 'archived') this.stats.archived--
+      }
+      return data
+    },
+
+    // openTasks: 'cancel' cancela as tarefas em aberto; 'block' recusa o arquivamento se houver alguma
+    async archiveClient(id: number, openTasks: 'cancel' | 'block' = 'block'): Promise<ClientArchiveResult> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${id}/archive`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        },
+        body: JSON.stringify({ open_tasks: openTasks })
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao arquivar cliente')
+      }
+      return data
+    },
+
+    async unarchiveClient(id: number): Promise<Client> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${id}/unarchive`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao reativar cliente')
+      }
+      return data
+    },
+
+    async fetchTrash(page: number = 1, pageSize: number = 20): Promise<{ data: ClientTrashEntry[], meta: { total: number } }> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/trash?page=${page}&page_size=${pageSize}`, {
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao buscar a lixeira')
+      }
+      return data
+    },
+
+    async restoreClient(id: number): Promise<Client> {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${id}/restore`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Falha ao restaurar cliente')
+      }
+      return data
+    },
+
+    async purgeClient(id: number) {
+      const config = useRuntimeConfig()
+      const response = await fetch(`${config.public.apiBase}/clients/${id}/purge`, {
+        method: 'DELETE',
+        headers: {
+          'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+        }
+      })
+
+      if (!response.ok) {
+        const data = await response.json()
+        throw new Error(data.error || 'Falha ao apagar cliente')
+      }
+      return true
+    },
+
     async fetchStats() {
       this.loading = true
       this.error = null
@@ -274,6 +274,33 @@ export const usePaymentsStore = defineStore('payments', {
       }
     },
 
+    async sendPaymentNotice(id: number) {
+      this.error = null
+
+      try {
+        const config = useRuntimeConfig()
+        const response = await fetch(
+          `${config.public.apiBase}/payments/${id}/send`,
+          {
+            method: 'POST',
+            headers: {
+              'Authorization': `Bearer ${localStorage.getItem('accessToken')}`
+            }
+          }
+        )
+
+        const data = await response.json()
+        if (!response.ok) {
+          throw new Error(data.error || 'Falha ao enviar cobrança')
+        }
+
+        return data as { kind: 'invoice' | 'reminder', recipient_name: string, recipient_email: string }
+      } catch (error: any) {
+        this.error = error.message
+        throw error
+      }
+    },
+
     async fetchOverduePayments() {
       this.loading = true
       this.error = null
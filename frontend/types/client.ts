@@ -1,3 +1,19 @@
+export interface Tag {
+  id: number
+  name: string
+  color: string
+}
+
+export type CustomFieldType = 'text' | 'number' | 'date' | 'select'
+
+export interface CustomFieldDefinition {
+  id: number
+  key: string
+  label: string
+  type: CustomFieldType
+  options: string[]
+}
+
 export interface Client {
   id: number
   name: string
@@ -8,6 +24,104 @@ export interface Client {
   status: string
   created_at: string
   updated_at?: string
+  tags?: Tag[]
+  custom_fields?: Record<string, string | number>
+  rank?: number
+  highlights?: Record<string, string> // trechos da busca em HTML, já escapados pela API
+}
+
+export interface ClientContact {
+  id: number
+  client_id: number
+  name: string
+  role: string
+  email: string
+  phone: string
+  is_primary: boolean
+  is_billing: boolean
+  created_at: string
+  updated_at: string
+}
+
+export type ClientContactInput = Pick<ClientContact, 'name' | 'role' | 'email' | 'phone' | 'is_primary' | 'is_billing'>
+
+export type ActivityType = 'note' | 'call' | 'meeting' | 'email' | 'status_change' | 'merge'
+
+export interface ClientActivityInput {
+  type: Exclude<ActivityType, 'status_change' | 'merge'>
+  body: string
+  occurred_at?: string
+  task_id?: number | null
+  payment_id?: number | null
+}
+
+// Item da linha do tempo: uma atividade registrada ou um evento das tarefas e dos pagamentos
+export interface TimelineEntry {
+  source: 'activity' | 'task' | 'payment'
+  kind: ActivityType | 'task_created' | 'task_completed' | 'payment_received' | 'payment_overdue'
+  id: number
+  occurred_at: string
+  title?: string
+  body?: string
+  task_id?: number
+  payment_id?: number
+  amount?: number
+  currency?: string
+}
+
+export interface TimelinePage {
+  data: TimelineEntry[]
+  meta: { next_cursor: string | null }
+}
+
+export type ImportField = 'name' | 'email' | 'phone' | 'company' | 'address' | 'notes'
+
+export interface ImportRow {
+  row: number
+  status: 'valid' | 'duplicate' | 'invalid'
+  values: Record<ImportField, string>
+  errors?: string[]
+  duplicate_of?: number
+  duplicate_of_row?: number
+  client_id?: number
+}
+
+export interface ImportResult {
+  dry_run: boolean
+  format: 'csv' | 'vcard'
+  columns?: string[]
+  mapping?: Partial<Record<ImportField, string>>
+  total: number
+  valid: number
+  duplicates: number
+  invalid: number
+  plan_limit_exceeded: boolean
+  rows: ImportRow[]
+}
+
+export type DuplicateReason = 'name' | 'company' | 'email' | 'email_domain' | 'phone'
+
+// Par de clientes que provavelmente são o mesmo; client é o mais antigo e o destino sugerido
+export interface DuplicateSuggestion {
+  client: Client
+  duplicate: Client
+  score: number
+  reasons: DuplicateReason[]
+}
+
+export interface ClientMergeResult {
+  client: Client
+  moved: { tasks: number; payments: number; contacts: number; activities: number }
+}
+
+export interface ClientArchiveResult {
+  client: Client
+  cancelled_tasks: number
+}
+
+// Cliente removido, na lixeira
+export interface ClientTrashEntry extends Client {
+  deleted_at: string
 }
 
 export interface ClientsResponse {